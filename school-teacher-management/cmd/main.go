@@ -1,30 +1,45 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 
 	_ "school-teacher-management/docs"
+	"school-teacher-management/internal/auth"
 	"school-teacher-management/internal/config"
+	"school-teacher-management/internal/events"
 	"school-teacher-management/internal/handler"
+	"school-teacher-management/internal/logging"
 	"school-teacher-management/internal/metrics"
 	"school-teacher-management/internal/middleware"
 	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/notification"
 	"school-teacher-management/internal/repository"
 	"school-teacher-management/internal/service"
+	"school-teacher-management/internal/tenant"
+	"school-teacher-management/internal/tracing"
 
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 // @title           School Teacher Management API
 // @version         1.0
 // @description     REST APIs for managing teachers and attendance records.
+// @description     Every POST accepts an Idempotency-Key header: a retry with the same key and body gets the first response again (marked Idempotent-Replayed), and reusing the key for another body is refused with 422.
 // @termsOfService  http://swagger.io/terms/
 
 // @contact.name   API Support
@@ -33,53 +48,255 @@ import (
 // @host      localhost:8082
 // @BasePath  /api/v1
 // @schemes   http
+
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
 func main() {
 
-	// -------------------- DATABASE --------------------
-	config.ConnectDatabase()
+	// -------------------- LOGGING --------------------
+	logConfig := config.Log()
+	logger := logging.New(os.Stdout, logConfig.Format, logConfig.Level)
+	slog.SetDefault(logger)
 
-	if config.DB == nil {
-		log.Fatal("Database connection failed")
+	// -------------------- TRACING --------------------
+	tracingConfig := config.Tracing()
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingConfig)
+	if err != nil {
+		fatal("Tracing setup failed", err)
 	}
 
-	config.DB.AutoMigrate(
+	// -------------------- DATABASE --------------------
+	if err := config.ConnectDatabase(logger, logConfig); err != nil {
+		fatal("Database connection failed", err)
+	}
+
+	healthService := service.NewHealthService(config.DB)
+
+	// Background jobs run until the server has drained on shutdown.
+	jobs, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	// A failed migration is reported by /readyz rather than stopping the
+	// server.
+	err = config.DB.AutoMigrate(
+		&model.School{},
+		&model.Campus{},
 		&model.Teacher{},
 		&model.Attendance{},
+		&model.OutboxEvent{},
+		&model.WebhookSubscription{},
+		&model.WebhookDelivery{},
+		&model.LeaveRequest{},
+		&model.NotificationPreference{},
+		&model.NotificationLog{},
+		&model.AttendanceCorrection{},
+		&model.AttendanceChange{},
+		&model.AuditLog{},
+		&model.AttendancePeriod{},
+		&model.AttendanceSnapshot{},
+		&model.Holiday{},
+		&model.OvertimeRecord{},
+		&model.CompOffCredit{},
+		&model.Kiosk{},
+		&model.Terminal{},
+		&model.TeacherPIN{},
+		&model.Geofence{},
+		&model.CampusNetwork{},
+		&model.Device{},
+		&model.DeviceBinding{},
+		&model.OfflinePunch{},
+		&model.Incident{},
+		&model.IdempotencyRecord{},
 	)
+	if err != nil {
+		slog.Error("Migration failed", "error", err)
+	}
+	healthService.Migrated(err)
+
+	// Rows from before schools existed move to the default school before
+	// tenant scoping is switched on.
+	schoolRepo := repository.NewSchoolRepository(config.DB)
+	defaultSchool, err := schoolRepo.EnsureDefault()
+	if err != nil {
+		fatal("School setup failed", err)
+	}
+
+	if err := config.DB.Use(&tenant.Plugin{DefaultSchoolID: defaultSchool.ID}); err != nil {
+		fatal("Tenant scoping setup failed", err)
+	}
+	if err := config.DB.Use(tracing.GormPlugin{}); err != nil {
+		fatal("Query tracing setup failed", err)
+	}
+
+	// -------------------- EVENT BUS --------------------
+	eventBus := events.NewPostgresBus(config.DB, config.DatabaseDSN())
+	healthService.Go(jobs, "events", eventBus.Listen)
 
 	// -------------------- REPOSITORIES --------------------
 	teacherRepo := repository.NewTeacherRepository(config.DB)
+	campusRepo := repository.NewCampusRepository(config.DB)
 	attendanceRepo := repository.NewAttendanceRepository(config.DB)
+	outboxRepo := repository.NewOutboxRepository(config.DB)
+	webhookRepo := repository.NewWebhookRepository(config.DB)
+	leaveRepo := repository.NewLeaveRepository(config.DB)
+	notificationRepo := repository.NewNotificationRepository(config.DB)
+	correctionRepo := repository.NewCorrectionRepository(config.DB)
+	auditRepo := repository.NewAuditRepository(config.DB)
+	periodRepo := repository.NewPeriodRepository(config.DB)
+	holidayRepo := repository.NewHolidayRepository(config.DB)
+	overtimeRepo := repository.NewOvertimeRepository(config.DB)
+	kioskRepo := repository.NewKioskRepository(config.DB)
+	terminalRepo := repository.NewTerminalRepository(config.DB)
+	geofenceRepo := repository.NewGeofenceRepository(config.DB)
+	networkRepo := repository.NewNetworkRepository(config.DB)
+	deviceRepo := repository.NewDeviceRepository(config.DB)
+	punchRepo := repository.NewPunchRepository(config.DB)
+	incidentRepo := repository.NewIncidentRepository(config.DB)
+	idempotencyRepo := repository.NewIdempotencyRepository(config.DB)
+
+	if err := auditRepo.EnsureAppendOnly(); err != nil {
+		fatal("Audit log setup failed", err)
+	}
+	if err := attendanceRepo.EnsureStatusCheck(); err != nil {
+		fatal("Attendance status setup failed", err)
+	}
+	if unlinked, err := networkRepo.EnsureCampusLinks(); err != nil {
+		fatal("Campus network setup failed", err)
+	} else if unlinked > 0 {
+		slog.Warn("Campus networks not linked to a campus no longer apply; add them again", "count", unlinked)
+	}
+	if duplicates, err := attendanceRepo.EnsureUniqueDay(); err != nil {
+		fatal("Attendance day index setup failed", err)
+	} else if len(duplicates) > 0 {
+		// Check-ins rely on the index, so the server cannot start until
+		// each of these days is down to one record.
+		for _, day := range duplicates {
+			slog.Error("Duplicate attendance records", "teacher_id", day.TeacherID, "date", day.Date.Format(time.DateOnly), "ids", day.IDs)
+		}
+		fatal("Attendance day index setup failed", fmt.Errorf("%d days have more than one attendance record; merge them into one", len(duplicates)))
+	}
 
 	// -------------------- SERVICES --------------------
-	teacherService := service.NewTeacherService(teacherRepo)
-	attendanceService := service.NewAttendanceService(attendanceRepo)
+	auditService := service.NewAuditService(auditRepo)
+	teacherService := service.NewTeacherService(teacherRepo, campusRepo, outboxRepo, auditService)
+	holidayService := service.NewHolidayService(holidayRepo, campusRepo)
+	schoolService := service.NewSchoolService(schoolRepo, config.TenantBaseDomain(), defaultSchool.ID)
+	campusService := service.NewCampusService(campusRepo)
+	overtimeService := service.NewOvertimeService(overtimeRepo, holidayRepo, schoolRepo, campusRepo, config.Overtime())
+	geofenceService := service.NewGeofenceService(geofenceRepo, campusRepo, config.Geofence())
+	networkService := service.NewNetworkService(networkRepo, campusRepo, config.NetworkPolicy())
+	deviceService := service.NewDeviceService(deviceRepo, config.DevicePolicy())
+	attendanceService := service.NewAttendanceService(
+		attendanceRepo,
+		correctionRepo,
+		outboxRepo,
+		periodRepo,
+		schoolRepo,
+		campusRepo,
+		overtimeService,
+		geofenceService,
+		networkService,
+		deviceService,
+		auditService,
+		eventBus,
+	)
+	punchSyncService := service.NewPunchSyncService(punchRepo, attendanceService, config.Sync())
+	anomalyService := service.NewAnomalyService(incidentRepo, attendanceRepo, deviceRepo, holidayRepo, networkRepo, config.Anomaly())
+	periodService := service.NewPeriodService(periodRepo, attendanceRepo, auditService)
+	payrollService := service.NewPayrollService(teacherRepo, attendanceRepo, leaveRepo, overtimeService, schoolRepo, config.Payroll())
+	kioskService := service.NewKioskService(kioskRepo, campusRepo, config.KioskTokenSecret(), config.KioskTokenTTL())
+	terminalService := service.NewTerminalService(terminalRepo, teacherRepo, campusRepo, config.PIN())
+	webhookService := service.NewWebhookService(webhookRepo)
+	notificationService := service.NewNotificationService(
+		notificationRepo,
+		teacherRepo,
+		attendanceRepo,
+		leaveRepo,
+		schoolRepo,
+		campusRepo,
+		outboxRepo,
+		notification.NewSMTPMailer(config.SMTP()),
+		config.Notifications(),
+	)
+	leaveService := service.NewLeaveService(leaveRepo, teacherRepo, overtimeService, notificationService)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, config.IdempotencyTTL())
+
+	if err := payrollService.CheckColumns(); err != nil {
+		fatal("Payroll config", err)
+	}
+
+	// -------------------- BACKGROUND JOBS --------------------
+	healthService.Go(jobs, "webhooks", service.NewWebhookDispatcher(webhookRepo, outboxRepo).Run)
+	healthService.Go(jobs, "notifications", notificationService.Run)
+	healthService.Go(jobs, "anomalies", anomalyService.Run)
+	healthService.Go(jobs, "idempotency", idempotencyService.Run)
 
 	// -------------------- HANDLERS --------------------
 	teacherHandler := handler.NewTeacherHandler(teacherService)
 	attendanceHandler := handler.NewAttendanceHandler(attendanceService)
+	attendanceStreamHandler := handler.NewAttendanceStreamHandler(eventBus)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	leaveHandler := handler.NewLeaveHandler(leaveService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	periodHandler := handler.NewPeriodHandler(periodService)
+	payrollHandler := handler.NewPayrollHandler(payrollService)
+	holidayHandler := handler.NewHolidayHandler(holidayService)
+	overtimeHandler := handler.NewOvertimeHandler(overtimeService)
+	kioskHandler := handler.NewKioskHandler(kioskService, attendanceService)
+	terminalHandler := handler.NewTerminalHandler(terminalService, attendanceService)
+	geofenceHandler := handler.NewGeofenceHandler(geofenceService)
+	networkHandler := handler.NewNetworkHandler(networkService)
+	deviceHandler := handler.NewDeviceHandler(deviceService)
+	punchSyncHandler := handler.NewPunchSyncHandler(punchSyncService)
+	incidentHandler := handler.NewIncidentHandler(anomalyService)
+	schoolHandler := handler.NewSchoolHandler(schoolService)
+	campusHandler := handler.NewCampusHandler(campusService)
+	healthHandler := handler.NewHealthHandler(healthService)
 
 	// -------------------- GIN SETUP --------------------
 	r := gin.New()
 
-	// Recovery + Metrics middleware
-	r.Use(gin.Recovery())
+	// Tracing + Access log + Recovery + Metrics middleware; ErrorMiddleware
+	// writes the problem response for errors reported with c.Error. The
+	// probes and /metrics are not traced.
+	r.Use(otelgin.Middleware(tracingConfig.ServiceName, otelgin.WithGinFilter(func(c *gin.Context) bool {
+		switch c.FullPath() {
+		case "/healthz", "/readyz", "/metrics":
+			return false
+		}
+		return true
+	})))
+	r.Use(middleware.AccessLogMiddleware())
+	r.Use(middleware.RecoveryMiddleware())
+	r.Use(middleware.ErrorMiddleware())
+	r.Use(middleware.RequestIDMiddleware())
+	r.Use(middleware.TenantMiddleware(schoolService.ResolveHost))
+	r.Use(middleware.IdempotencyMiddleware(idempotencyService, handler.KioskKeyHeader, handler.TerminalKeyHeader, handler.DeviceIDHeader))
 	r.Use(middleware.MetricsMiddleware())
 
-	// Trust proxies (fix warning)
-	r.SetTrustedProxies(nil)
+	// Client IPs are taken from X-Forwarded-For only behind trusted proxies
+	if err := r.SetTrustedProxies(config.TrustedProxies()); err != nil {
+		fatal("Invalid TRUSTED_PROXIES", err)
+	}
 
 	// CORS
 	r.Use(cors.New(cors.Config{
-		AllowOrigins: []string{"*"},
-		AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders: []string{"Origin", "Content-Type", "Authorization"},
+		AllowOrigins:  []string{"*"},
+		AllowMethods:  []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:  []string{"Origin", "Content-Type", "Authorization", "If-Match", "traceparent", "tracestate", middleware.IdempotencyKeyHeader, middleware.RequestIDHeader, handler.KioskKeyHeader, handler.TerminalKeyHeader, handler.DeviceIDHeader},
+		ExposeHeaders: []string{middleware.RequestIDHeader, "ETag", middleware.ReplayedHeader},
 	}))
 
 	// -------------------- METRICS --------------------
 	metrics.Register()
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
+	// -------------------- HEALTH --------------------
+	r.GET("/healthz", healthHandler.Healthz)
+	r.GET("/readyz", healthHandler.Readyz)
+
 	// -------------------- API ROUTES --------------------
 	api := r.Group("/api/v1")
 	{
@@ -88,27 +305,201 @@ func main() {
 		api.GET("/teachers", teacherHandler.SearchTeachers)
 		api.GET("/teachers/:id", teacherHandler.GetTeacherByID)
 		api.PUT("/teachers/:id", teacherHandler.UpdateTeacher)
+		api.PATCH("/teachers/:id", teacherHandler.PatchTeacher)
 		api.POST("/teachers/bulk", teacherHandler.CreateTeachers)
 
 		// Attendance
-		api.POST("/attendance", attendanceHandler.CreateAttendance)
 		api.GET("/attendance", attendanceHandler.GetAttendances)
-		api.GET("/attendance/:id", attendanceHandler.GetAttendanceByID)
-		api.PUT("/attendance/:id", attendanceHandler.UpdateAttendance)
-		api.DELETE("/attendance/:id", attendanceHandler.DeleteAttendance)
 		api.GET("/attendanceByDate", attendanceHandler.GetAttendanceByDate)
 		api.GET("/attendanceByFilterDate", attendanceHandler.GetAttendanceByFilterDate)
+
+		// Punches, attendance stream, corrections and admin edits
+		attendance := api.Group("/attendance", middleware.AuthMiddleware())
+		attendance.POST("", attendanceHandler.CreateAttendance)
+		attendance.GET("/:id", attendanceHandler.GetAttendanceByID)
+		attendance.GET("/stream", attendanceStreamHandler.StreamSSE)
+		attendance.GET("/ws", attendanceStreamHandler.StreamWebSocket)
+		attendance.POST("/corrections", attendanceHandler.CreateCorrection)
+		attendance.GET("/corrections", attendanceHandler.GetCorrections)
+		attendance.POST("/corrections/:id/approve", attendanceHandler.ApproveCorrection)
+		attendance.POST("/corrections/:id/reject", attendanceHandler.RejectCorrection)
+		attendance.POST("/kiosk", kioskHandler.KioskCheckIn)
+		attendance.POST("/sync", punchSyncHandler.SyncPunches)
+
+		admin := attendance.Group("", middleware.RequireRole(auth.RoleAdmin))
+		admin.PUT("/:id", attendanceHandler.UpdateAttendance)
+		admin.PATCH("/:id", attendanceHandler.PatchAttendance)
+		admin.DELETE("/:id", attendanceHandler.DeleteAttendance)
+		admin.GET("/:id/history", attendanceHandler.GetAttendanceHistory)
+
+		// Attendance periods
+		periods := attendance.Group("/periods", middleware.RequireRole(auth.RoleAdmin, auth.RoleOffice))
+		periods.GET("", periodHandler.GetPeriods)
+		periods.GET("/:period/snapshot", periodHandler.GetPeriodSnapshot)
+		periods.GET("/:period/compare", periodHandler.ComparePeriod)
+		periods.POST("/:period/close", middleware.RequireRole(auth.RoleAdmin), periodHandler.ClosePeriod)
+		periods.POST("/:period/reopen", middleware.RequireRole(auth.RoleAdmin), periodHandler.ReopenPeriod)
+
+		// Webhooks
+		webhooks := api.Group("/webhooks", middleware.AuthMiddleware(), middleware.RequireRole(auth.RoleAdmin))
+		webhooks.POST("", webhookHandler.CreateWebhook)
+		webhooks.GET("", webhookHandler.GetWebhooks)
+		webhooks.GET("/dead-letters", webhookHandler.GetDeadLetters)
+		webhooks.GET("/:id", webhookHandler.GetWebhookByID)
+		webhooks.PUT("/:id", webhookHandler.UpdateWebhook)
+		webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", webhookHandler.GetWebhookDeliveries)
+		webhooks.POST("/deliveries/:id/retry", webhookHandler.RetryDelivery)
+
+		// Leave
+		leaves := api.Group("/leaves", middleware.AuthMiddleware())
+		leaves.POST("", leaveHandler.CreateLeave)
+		leaves.GET("", leaveHandler.GetLeaves)
+		leaves.POST("/:id/approve", leaveHandler.ApproveLeave)
+		leaves.POST("/:id/reject", leaveHandler.RejectLeave)
+
+		// Notification preferences
+		api.GET("/teachers/:id/notification-preferences", middleware.AuthMiddleware(), notificationHandler.GetPreferences)
+		api.PUT("/teachers/:id/notification-preferences", middleware.AuthMiddleware(), notificationHandler.UpdatePreferences)
+
+		// Kiosks
+		kiosks := api.Group("/kiosks", middleware.AuthMiddleware(), middleware.RequireRole(auth.RoleAdmin))
+		kiosks.POST("", kioskHandler.RegisterKiosk)
+		kiosks.GET("", kioskHandler.GetKiosks)
+		kiosks.DELETE("/:id", kioskHandler.DeactivateKiosk)
+		api.GET("/kiosk/token", kioskHandler.GetKioskToken)
+
+		// Shared terminals
+		terminals := api.Group("/terminals", middleware.AuthMiddleware(), middleware.RequireRole(auth.RoleAdmin))
+		terminals.POST("", terminalHandler.RegisterTerminal)
+		terminals.GET("", terminalHandler.GetTerminals)
+		terminals.DELETE("/:id", terminalHandler.DeactivateTerminal)
+		api.PUT("/teachers/:id/pin", middleware.AuthMiddleware(), middleware.RequireRole(auth.RoleAdmin), terminalHandler.SetPIN)
+		api.POST("/terminal/check-in", terminalHandler.TerminalCheckIn)
+
+		// Geofences
+		geofences := api.Group("/geofences", middleware.AuthMiddleware(), middleware.RequireRole(auth.RoleAdmin))
+		geofences.POST("", geofenceHandler.CreateGeofence)
+		geofences.GET("", geofenceHandler.GetGeofences)
+		geofences.PUT("/:id", geofenceHandler.UpdateGeofence)
+		geofences.DELETE("/:id", geofenceHandler.DeleteGeofence)
+
+		// Campus networks
+		networks := api.Group("/networks", middleware.AuthMiddleware(), middleware.RequireRole(auth.RoleAdmin))
+		networks.POST("", networkHandler.CreateNetwork)
+		networks.GET("", networkHandler.GetNetworks)
+		networks.DELETE("/:id", networkHandler.DeleteNetwork)
+
+		// Devices
+		devices := api.Group("/devices", middleware.AuthMiddleware())
+		devices.POST("", deviceHandler.RegisterDevice)
+		devices.GET("", deviceHandler.GetDevices)
+		devices.DELETE("/:id", middleware.RequireRole(auth.RoleAdmin), deviceHandler.DeactivateDevice)
+		devices.GET("/bindings", deviceHandler.GetBindings)
+		devices.POST("/bindings/:id/approve", deviceHandler.ApproveBinding)
+		devices.POST("/bindings/:id/reject", deviceHandler.RejectBinding)
+
+		// Anomaly incidents
+		incidents := api.Group("/incidents", middleware.AuthMiddleware(), middleware.RequireRole(auth.RoleAdmin, auth.RoleOffice, auth.RoleHOD))
+		incidents.GET("", incidentHandler.GetIncidents)
+		incidents.POST("/scan", middleware.RequireRole(auth.RoleAdmin), incidentHandler.ScanIncidents)
+		incidents.POST("/:id/resolve", incidentHandler.ResolveIncident)
+		incidents.POST("/:id/dismiss", incidentHandler.DismissIncident)
+
+		// Holidays
+		holidays := api.Group("/holidays", middleware.AuthMiddleware())
+		holidays.GET("", holidayHandler.GetHolidays)
+		holidays.POST("", middleware.RequireRole(auth.RoleAdmin), holidayHandler.CreateHoliday)
+		holidays.DELETE("/:id", middleware.RequireRole(auth.RoleAdmin), holidayHandler.DeleteHoliday)
+
+		// Overtime and compensatory off
+		overtime := api.Group("/overtime", middleware.AuthMiddleware())
+		overtime.GET("", overtimeHandler.GetOvertimes)
+		overtime.POST("/:id/approve", overtimeHandler.ApproveOvertime)
+		overtime.POST("/:id/reject", overtimeHandler.RejectOvertime)
+		api.GET("/teachers/:id/comp-off-balance", middleware.AuthMiddleware(), overtimeHandler.GetCompOffBalance)
+
+		// Payroll
+		api.GET("/payroll/export", middleware.AuthMiddleware(), middleware.RequireRole(auth.RoleAdmin, auth.RoleOffice), payrollHandler.ExportPayroll)
+
+		// Schools and trust-level reporting
+		schools := api.Group("/schools", middleware.AuthMiddleware(), middleware.RequireRole(auth.RoleAdmin))
+		schools.POST("", middleware.RequireTrustAdmin(), schoolHandler.CreateSchool)
+		schools.GET("", middleware.RequireTrustAdmin(), schoolHandler.GetSchools)
+		schools.GET("/:id", schoolHandler.GetSchool)
+		schools.PUT("/:id", schoolHandler.UpdateSchool)
+		api.GET("/reports/schools", middleware.AuthMiddleware(), middleware.RequireTrustAdmin(), payrollHandler.GetSchoolReport)
+
+		// Campuses of the school
+		campuses := api.Group("/campuses", middleware.AuthMiddleware())
+		campuses.GET("", campusHandler.GetCampuses)
+		campuses.GET("/:id", campusHandler.GetCampus)
+		campuses.POST("", middleware.RequireRole(auth.RoleAdmin), campusHandler.CreateCampus)
+		campuses.PUT("/:id", middleware.RequireRole(auth.RoleAdmin), campusHandler.UpdateCampus)
+
+		// Audit
+		audit := api.Group("/audit", middleware.AuthMiddleware(), middleware.RequireRole(auth.RoleAdmin))
+		audit.GET("", auditHandler.GetAuditLogs)
+		audit.GET("/verify", middleware.RequireTrustAdmin(), auditHandler.VerifyAuditLog)
 	}
 
 	// -------------------- SWAGGER --------------------
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// -------------------- SERVER --------------------
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8082"
+	serverConfig := config.Server()
+	server := &http.Server{
+		Addr:         ":" + serverConfig.Port,
+		Handler:      r,
+		ReadTimeout:  serverConfig.ReadTimeout,
+		WriteTimeout: serverConfig.WriteTimeout,
+		IdleTimeout:  serverConfig.IdleTimeout,
+	}
+	// Open attendance streams would otherwise hold up the shutdown.
+	server.RegisterOnShutdown(attendanceStreamHandler.Close)
+
+	shutdown, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		slog.Info("Server running", "port", serverConfig.Port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("Server failed", err)
+		}
+	}()
+
+	// On SIGTERM the readiness probe fails first and the server keeps
+	// serving until the load balancer has seen it. In-flight requests are
+	// then drained, the background jobs are stopped, and the database pool
+	// is closed last.
+	<-shutdown.Done()
+	slog.Info("Shutting down", "drain_delay", serverConfig.DrainDelay)
+	healthService.Drain()
+	time.Sleep(serverConfig.DrainDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), serverConfig.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Error("Server shutdown", "error", err)
 	}
 
-	log.Println("🚀 Server running on port", port)
-	r.Run(":" + port)
+	stopJobs()
+	healthService.Wait()
+
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("Tracing shutdown", "error", err)
+	}
+
+	if db, err := config.DB.DB(); err == nil {
+		if err := db.Close(); err != nil {
+			slog.Error("Database close", "error", err)
+		}
+	}
+	slog.Info("Server stopped")
+}
+
+// fatal logs a failure to start and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
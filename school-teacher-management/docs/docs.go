@@ -28,20 +28,39 @@ const docTemplate = `{
                     "attendance"
                 ],
                 "summary": "Get all attendance records",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only punches from this device",
+                        "name": "deviceId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only punches at this campus",
+                        "name": "campusId",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/school-teacher-management_internal_model.Attendance"
+                                "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceDTO"
                             }
                         }
                     }
                 }
             },
             "post": {
-                "description": "Creates a new attendance entry (teacher_id and status are mandatory)",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Checks the teacher of the bearer token in or out (status is mandatory). latitude, longitude and accuracy are optional and checked against the campus geofences, and the client IP against the campus networks; depending on the school's geofence and network policies a punch outside them is flagged or rejected. The device is identified by X-Device-ID; depending on DEVICE_POLICY a punch from a device the teacher is not approved to use is flagged or rejected.",
                 "consumes": [
                     "application/json"
                 ],
@@ -61,6 +80,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device identifier",
+                        "name": "X-Device-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key making retries of the request safe",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -76,61 +107,96 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     }
                 }
             }
         },
-        "/attendance/{id}": {
+        "/attendance/corrections": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Teachers see their own requests, heads of department their department's, admin and office staff everyone's.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "attendance"
+                    "corrections"
                 ],
-                "summary": "Get attendance by ID",
+                "summary": "List attendance corrections",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Attendance ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Teacher ID",
+                        "name": "teacherId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, approved or rejected",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/school-teacher-management_internal_model.Attendance"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceCorrection"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Teachers request corrections for themselves (missed punch, wrong time); admin and office staff may pass teacher_id. Nothing changes until the request is approved.",
                 "consumes": [
                     "application/json"
                 ],
@@ -138,180 +204,265 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "attendance"
+                    "corrections"
                 ],
-                "summary": "Update attendance",
+                "summary": "Request an attendance correction",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Attendance ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated attendance",
-                        "name": "attendance",
+                        "description": "Correction request",
+                        "name": "correction",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/school-teacher-management_internal_model.Attendance"
+                            "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceCorrectionInput"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/school-teacher-management_internal_model.Attendance"
+                            "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceCorrection"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/attendance/corrections/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Applies the requested punches and records before/after values in the attendance history.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "attendance"
+                    "corrections"
                 ],
-                "summary": "Delete attendance",
+                "summary": "Approve an attendance correction",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Attendance ID",
+                        "description": "Correction ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Optional note",
+                        "name": "decision",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.CorrectionDecisionInput"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceCorrection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     }
                 }
             }
         },
-        "/attendanceByDate": {
-            "get": {
-                "description": "Get attendance for a teacher filtered by month/year and checked-in count today",
+        "/attendance/corrections/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "attendance"
+                    "corrections"
                 ],
-                "summary": "Get attendance by teacher",
+                "summary": "Reject an attendance correction",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Teacher ID",
-                        "name": "teacherId",
-                        "in": "query",
+                        "description": "Correction ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Month (1-12), default current month",
-                        "name": "month",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Year, default current year",
-                        "name": "year",
-                        "in": "query"
+                        "description": "Optional note",
+                        "name": "decision",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.CorrectionDecisionInput"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceResponse"
+                            "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceCorrection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     }
                 }
             }
         },
-        "/attendanceByFilterDate": {
-            "get": {
-                "description": "Get attendance filtered by date/month/year (teacher independent)",
+        "/attendance/kiosk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The teacher is taken from the bearer token, not the request body. The scanned token must be fresh and come from an active kiosk.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "attendance"
                 ],
-                "summary": "Get attendance for a date",
+                "summary": "Check in or out by scanning a kiosk QR code",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Day of month (1-31)",
-                        "name": "date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Month (1-12)",
-                        "name": "month",
-                        "in": "query"
+                        "description": "Scanned token",
+                        "name": "scan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.KioskCheckInInput"
+                        }
                     },
                     {
-                        "type": "integer",
-                        "description": "Year (YYYY)",
-                        "name": "year",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Device identifier",
+                        "name": "X-Device-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     }
                 }
             }
         },
-        "/teachers": {
+        "/attendance/periods": {
             "get": {
-                "description": "Search teachers across first name, last name, email, subject",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Months that have been closed at least once. Months not listed are open.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "teachers"
+                    "periods"
                 ],
-                "summary": "Search teachers",
+                "summary": "List attendance periods",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Search keyword",
-                        "name": "q",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Search keyword",
-                        "name": "subject",
+                        "type": "integer",
+                        "description": "Year",
+                        "name": "year",
                         "in": "query"
                     }
                 ],
@@ -321,143 +472,171 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                                "$ref": "#/definitions/school-teacher-management_internal_model.AttendancePeriod"
                             }
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/attendance/periods/{period}/close": {
             "post": {
-                "description": "Create a new teacher",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Locks the month against check-ins, edits, deletes and corrections, and snapshots its register.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "teachers"
+                    "periods"
                 ],
-                "summary": "Create teacher",
+                "summary": "Close an attendance period",
                 "parameters": [
                     {
-                        "description": "Teacher data",
-                        "name": "teacher",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
-                        }
+                        "type": "string",
+                        "description": "Month (YYYY-MM)",
+                        "name": "period",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                            "$ref": "#/definitions/school-teacher-management_internal_model.AttendancePeriod"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     }
                 }
             }
         },
-        "/teachers/bulk": {
-            "post": {
-                "consumes": [
-                    "application/json"
+        "/attendance/periods/{period}/compare": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Attendance added, removed or changed since the period was last closed.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "teachers"
+                    "periods"
                 ],
-                "summary": "Create multiple teachers",
+                "summary": "Compare a period with its snapshot",
                 "parameters": [
                     {
-                        "description": "List of teachers",
-                        "name": "teachers",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/school-teacher-management_internal_model.TeacherRequest"
-                            }
-                        }
+                        "type": "string",
+                        "description": "Month (YYYY-MM)",
+                        "name": "period",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/school-teacher-management_internal_model.PeriodComparison"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     }
                 }
             }
         },
-        "/teachers/{id}": {
+        "/attendance/periods/{period}/reopen": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Allows writes into the month again; its snapshots are kept for comparison.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "periods"
+                ],
+                "summary": "Reopen an attendance period",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Month (YYYY-MM)",
+                        "name": "period",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.AttendancePeriod"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/attendance/periods/{period}/snapshot": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The register as it stood when the period was last closed.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "teachers"
+                    "periods"
                 ],
-                "summary": "Get teacher by ID",
+                "summary": "Register snapshot of a period",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Teacher ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Month (YYYY-MM)",
+                        "name": "period",
                         "in": "path",
                         "required": true
                     }
@@ -466,22 +645,83 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                            "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceSnapshot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update a teacher by ID",
+            }
+        },
+        "/attendance/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Pushes check-in, check-out, leave and correction events as Server-Sent Events. Heads of department only receive their own department; teachers only their own events.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Stream attendance events (SSE)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated departments to include",
+                        "name": "department",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token, for clients that cannot set headers",
+                        "name": "access_token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/attendance/sync": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Uploads check-ins and check-outs a device queued while offline, with their device timestamps. Each punch carries an idempotency key, so a retried batch is safe: punches already synced are reported as duplicate. The device clock is compared with the server's via sent_at; beyond SYNC_MAX_CLOCK_SKEW_SECONDS the punch times are shifted by the skew or, under SYNC_SKEW_POLICY=reject, refused. Among offline punches the earliest check-in and latest check-out of each day win; punches recorded online are never replaced. Queued punches cannot show they were made on campus, so with geofences or campus networks configured they are rejected or flagged under the school's geofence and network policies. The result of every punch is reported individually.",
                 "consumes": [
                     "application/json"
                 ],
@@ -489,136 +729,5311 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "teachers"
+                    "attendance"
                 ],
-                "summary": "Update teacher",
+                "summary": "Sync punches queued offline",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Teacher ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Teacher data",
-                        "name": "teacher",
+                        "description": "Queued punches",
+                        "name": "batch",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                            "$ref": "#/definitions/school-teacher-management_internal_model.PunchSyncInput"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device identifier",
+                        "name": "X-Device-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                            "$ref": "#/definitions/school-teacher-management_internal_model.PunchSyncReport"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "school-teacher-management_internal_model.Attendance": {
-            "type": "object",
-            "required": [
-                "status",
-                "teacher_id"
-            ],
-            "properties": {
-                "check_in": {
-                    "type": "string"
-                },
-                "check_out": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "date": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "status": {
-                    "type": "string"
-                },
-                "teacher": {
-                    "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
-                },
-                "teacher_id": {
-                    "type": "integer"
-                },
-                "updated_at": {
-                    "type": "string"
-                }
-            }
         },
-        "school-teacher-management_internal_model.AttendanceDTO": {
-            "type": "object",
-            "properties": {
-                "checkIn": {
-                    "type": "string"
-                },
-                "checkOut": {
-                    "type": "string"
+        "/attendance/ws": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Same events and filtering as /attendance/stream, delivered as JSON WebSocket messages.",
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Stream attendance events (WebSocket)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated departments to include",
+                        "name": "department",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer token, for clients that cannot set headers",
+                        "name": "access_token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/attendance/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The record includes the reported positions, client IPs and devices of its punches, so teachers may only read their own; admin and office staff read everyone's.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Get attendance by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Attendance ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Attendance"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Direct edit, restricted to administrators and recorded in the attendance history. Teachers use correction requests instead. The status may only move along the allowed transitions (for example a worked day cannot become a holiday), and days not worked cannot have check-in or check-out times. Only the fields that changed are written; with If-Match the edit is refused with 412 unless the record still has that version.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Update attendance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Attendance ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record as read",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Updated attendance",
+                        "name": "attendance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Attendance"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Attendance"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restricted to administrators; the deleted values are kept in the attendance history.",
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Delete attendance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Attendance ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Applies a JSON Merge Patch (application/merge-patch+json) or a JSON Patch (application/json-patch+json) to an attendance record, under the same rules as the full update. The edit is refused with 412 if the record changes meanwhile or, with If-Match, no longer has that version.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Partially update attendance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Attendance ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the record as read",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Merge patch or JSON Patch",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Attendance"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/attendance/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Before/after values of every approved correction and admin edit or delete of an attendance record.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "corrections"
+                ],
+                "summary": "Attendance change history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Attendance ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceChange"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/attendanceByDate": {
+            "get": {
+                "description": "Get attendance for a teacher filtered by month/year and checked-in count today",
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Get attendance by teacher",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Teacher ID",
+                        "name": "teacherId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Month (1-12), default current month",
+                        "name": "month",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year, default current year",
+                        "name": "year",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/attendanceByFilterDate": {
+            "get": {
+                "description": "Get attendance filtered by date/month/year (teacher independent)",
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Get attendance for a date",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Day of month (1-31)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Month (1-12)",
+                        "name": "month",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Year (YYYY)",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only punches at this campus",
+                        "name": "campusId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/audit": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Newest first. Filter by entity (teacher, attendance) and ID, or by actor.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Query the audit log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "teacher or attendance",
+                        "name": "entity_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entity ID",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Actor teacher ID",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Actor role",
+                        "name": "actor_role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To, exclusive (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max entries (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.AuditLog"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/audit/verify": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recomputes every entry's hash and link; reports the first entry that was altered, removed or reordered. The chain spans every school, so only trust-level administrators without a school subdomain may verify it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Verify the audit hash chain",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.AuditVerification"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/campuses": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "campuses"
+                ],
+                "summary": "List campuses",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.Campus"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a campus to the school of the request. Working hours left empty follow the school's.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "campuses"
+                ],
+                "summary": "Add a campus",
+                "parameters": [
+                    {
+                        "description": "Campus",
+                        "name": "campus",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.CampusInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Campus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/campuses/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "campuses"
+                ],
+                "summary": "Get a campus",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Campus ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Campus"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Name, address, working hours and whether punches may still be recorded at it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "campuses"
+                ],
+                "summary": "Update a campus",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Campus ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Campus",
+                        "name": "campus",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.CampusInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Campus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/devices": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin and office staff see every device of the school, others the devices they own or are bound to.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devices"
+                ],
+                "summary": "List devices",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.Device"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Teachers register their own devices, which need approval before punches from them are trusted. Administrators may register shared devices, usable by every teacher.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devices"
+                ],
+                "summary": "Register a device",
+                "parameters": [
+                    {
+                        "description": "Device",
+                        "name": "device",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.DeviceInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Device"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/devices/bindings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Teachers see their own bindings, heads of department their department's, admin and office staff everyone's.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devices"
+                ],
+                "summary": "List device bindings",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Teacher ID",
+                        "name": "teacherId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, approved, rejected or revoked",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.DeviceBinding"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/devices/bindings/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devices"
+                ],
+                "summary": "Approve a device binding",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Binding ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.DeviceBinding"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/devices/bindings/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devices"
+                ],
+                "summary": "Reject or revoke a device binding",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Binding ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.DeviceBinding"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/devices/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Punches from the device are treated as unapproved from now on.",
+                "tags": [
+                    "devices"
+                ],
+                "summary": "Deactivate a device",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/geofences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "geofences"
+                ],
+                "summary": "List campus geofences",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.Geofence"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "A radius fence needs center and radius_meters, a polygon fence at least three points. Punches are checked against every active fence of the school.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "geofences"
+                ],
+                "summary": "Add a campus geofence",
+                "parameters": [
+                    {
+                        "description": "Geofence",
+                        "name": "geofence",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.GeofenceInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Geofence"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/geofences/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "geofences"
+                ],
+                "summary": "Update a campus geofence",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Geofence ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Geofence",
+                        "name": "geofence",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.GeofenceInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Geofence"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "geofences"
+                ],
+                "summary": "Remove a campus geofence",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Geofence ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/holidays": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "holidays"
+                ],
+                "summary": "List holidays",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Year",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only holidays closing this campus",
+                        "name": "campusId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.Holiday"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Holidays are not working days for payroll, and work on them is overtime.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "holidays"
+                ],
+                "summary": "Add a holiday",
+                "parameters": [
+                    {
+                        "description": "Holiday",
+                        "name": "holiday",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.HolidayInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Holiday"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/holidays/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "holidays"
+                ],
+                "summary": "Remove a holiday",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Holiday ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Suspicious attendance patterns found by the anomaly scan: shared_device and shared_ip (different teachers punching from one device or address within seconds), repeated_time (check-ins at the identical time on many days), impossible_travel and holiday_check_in. Heads of department see their department's incidents.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "List flagged incidents",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Teacher ID",
+                        "name": "teacherId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Incident kind",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "open, resolved or dismissed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date, inclusive (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.Incident"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/scan": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The scan also runs in the background every ANOMALY_SCAN_INTERVAL_MINUTES over the last ANOMALY_LOOKBACK_DAYS days. Patterns already flagged are not flagged again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Run the anomaly scan now",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "From date (YYYY-MM-DD), default the start of the lookback",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date, inclusive (YYYY-MM-DD), default today",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.IncidentScanResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/dismiss": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The incident was a false positive.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Dismiss an incident",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional note",
+                        "name": "review",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.IncidentReviewInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Incident"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The suspicion was confirmed and dealt with.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Resolve an incident",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional note",
+                        "name": "review",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.IncidentReviewInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Incident"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/kiosk/token": {
+            "get": {
+                "description": "Called by the kiosk with its key in X-Kiosk-Key, on its school's host. The token is short-lived; fetch a new one before expires_at.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "kiosks"
+                ],
+                "summary": "Get a QR token for display",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kiosk key",
+                        "name": "X-Kiosk-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.KioskToken"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/kiosks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "kiosks"
+                ],
+                "summary": "List kiosks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.Kiosk"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the kiosk key once; configure it on the kiosk device.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "kiosks"
+                ],
+                "summary": "Register a kiosk",
+                "parameters": [
+                    {
+                        "description": "Kiosk",
+                        "name": "kiosk",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.KioskInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.KioskRegistration"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/kiosks/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The kiosk can no longer issue tokens, and tokens it already showed are refused.",
+                "tags": [
+                    "kiosks"
+                ],
+                "summary": "Deactivate a kiosk",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Kiosk ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/leaves": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Teachers see their own requests, heads of department their department's, admin and office staff everyone's.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "List leave requests",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Teacher ID",
+                        "name": "teacherId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, approved or rejected",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.LeaveRequest"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Teachers request leave for themselves; admin and office staff may pass teacher_id. The department heads are emailed. comp_off leave is paid from compensatory-off credits and needs enough balance for its working days.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Request leave",
+                "parameters": [
+                    {
+                        "description": "Leave request",
+                        "name": "leave",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.LeaveRequestInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.LeaveRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/leaves/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Approve leave",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Leave request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional note",
+                        "name": "decision",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.LeaveDecisionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.LeaveRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/leaves/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "leave"
+                ],
+                "summary": "Reject leave",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Leave request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional note",
+                        "name": "decision",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.LeaveDecisionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.LeaveRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/networks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "networks"
+                ],
+                "summary": "List campus network ranges",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.CampusNetwork"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a range to a campus of the caller's school. Once the school has any range, direct punches from other addresses are flagged or rejected according to the school's network policy. The client IP honours X-Forwarded-For only from TRUSTED_PROXIES.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "networks"
+                ],
+                "summary": "Add a campus network range",
+                "parameters": [
+                    {
+                        "description": "Network range",
+                        "name": "network",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.CampusNetworkInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.CampusNetwork"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/networks/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "networks"
+                ],
+                "summary": "Remove a campus network range",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Network ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/overtime": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Overtime is detected at check-out and on corrections: time after the scheduled end of the day, or the whole session on a weekend or holiday. Teachers see their own, heads of department their department's, admin and office staff everyone's.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "overtime"
+                ],
+                "summary": "List overtime",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Teacher ID",
+                        "name": "teacherId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, approved or rejected",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.OvertimeRecord"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/overtime/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "compensation comp_off (default) credits compensatory-off leave in half-day steps; paid includes the hours in the payroll export.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "overtime"
+                ],
+                "summary": "Approve overtime",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Overtime ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Compensation and optional note",
+                        "name": "decision",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.OvertimeDecisionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.OvertimeRecord"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/overtime/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "overtime"
+                ],
+                "summary": "Reject overtime",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Overtime ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional note",
+                        "name": "decision",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.OvertimeDecisionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.OvertimeRecord"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/payroll/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Per teacher payable days, loss-of-pay days, overtime hours and late deductions, computed from attendance and approved leave. CSV columns follow PAYROLL_CSV_COLUMNS.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "Export payroll for a pay period",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv (default) or json",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only teachers based at this campus",
+                        "name": "campusId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.PayrollReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/schools": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Per school attendance totals from the payroll computation: teachers, working, present, leave and absent days, late arrivals and the attendance rate. Trust-level administrators only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "Compare schools over a period",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.TrustReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/schools": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Trust-level administrators only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schools"
+                ],
+                "summary": "List schools",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.School"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Trust-level administrators only. The slug is the school's subdomain.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schools"
+                ],
+                "summary": "Add a school",
+                "parameters": [
+                    {
+                        "description": "School",
+                        "name": "school",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.SchoolInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.School"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/schools/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Trust-level administrators, or administrators of the school.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schools"
+                ],
+                "summary": "Get a school",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "School ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.School"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Name, subdomain, timezone, working hours and the geofence and network policies, which override GEOFENCE_POLICY and NETWORK_POLICY when set. Trust-level administrators, or administrators of the school.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schools"
+                ],
+                "summary": "Update a school's settings",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "School ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "School",
+                        "name": "school",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.SchoolInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.School"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/teachers": {
+            "get": {
+                "description": "Search teachers across first name, last name, email, subject",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Search teachers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search keyword",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search keyword",
+                        "name": "subject",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new teacher",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Create teacher",
+                "parameters": [
+                    {
+                        "description": "Teacher data",
+                        "name": "teacher",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/teachers/bulk": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Create multiple teachers",
+                "parameters": [
+                    {
+                        "description": "List of teachers",
+                        "name": "teachers",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.TeacherRequest"
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key making retries of the request safe",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/teachers/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Get teacher by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Teacher ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces a teacher by ID; only the fields that changed are written. With If-Match the update is refused with 412 unless the teacher still has that version.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Update teacher",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Teacher ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the teacher as read",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Teacher data",
+                        "name": "teacher",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Applies a JSON Merge Patch (application/merge-patch+json) or a JSON Patch (application/json-patch+json) to a teacher; only the fields that changed are written. The update is refused with 412 if the teacher changes meanwhile or, with If-Match, no longer has that version.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "teachers"
+                ],
+                "summary": "Partially update teacher",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Teacher ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the teacher as read",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Merge patch or JSON Patch",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/teachers/{id}/comp-off-balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Unexpired credits with days left, soonest expiry first. Teachers see their own balance.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "overtime"
+                ],
+                "summary": "Compensatory-off balance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Teacher ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.CompOffBalance"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/teachers/{id}/notification-preferences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Get notification preferences",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Teacher ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.NotificationPreference"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "With daily_digest enabled, late alerts are folded into one end-of-day digest.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Update notification preferences",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Teacher ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Preferences",
+                        "name": "preferences",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.NotificationPreferenceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.NotificationPreference"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/teachers/{id}/pin": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Also lifts a lockout caused by repeated wrong PINs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "terminals"
+                ],
+                "summary": "Set or reset a teacher's terminal PIN",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Teacher ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New PIN, 4 to 8 digits",
+                        "name": "pin",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.PINInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/terminal/check-in": {
+            "post": {
+                "description": "The terminal authenticates with its key in X-Terminal-Key; the teacher, who must be of the terminal's school, with employee code and PIN. Repeated wrong PINs lock the PIN for a while.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attendance"
+                ],
+                "summary": "Check in or out on a shared terminal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Terminal key",
+                        "name": "X-Terminal-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Employee code, PIN and status",
+                        "name": "punch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.TerminalCheckInInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/terminals": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "terminals"
+                ],
+                "summary": "List shared terminals",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.Terminal"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the terminal key once; configure it on the device.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "terminals"
+                ],
+                "summary": "Register a shared terminal",
+                "parameters": [
+                    {
+                        "description": "Terminal",
+                        "name": "terminal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.TerminalInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.TerminalRegistration"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/terminals/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "terminals"
+                ],
+                "summary": "Deactivate a shared terminal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Terminal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.WebhookSubscription"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Subscribes a URL to event types (\"*\" for all): attendance.check_in, attendance.check_out, attendance.leave, attendance.correction, attendance.absent (sent at the late alert for teachers not checked in), attendance.deleted, teacher.created and teacher.updated. Payloads are signed with HMAC-SHA256 of \"\u003ct\u003e.\u003cbody\u003e\" using the secret, sent as X-Webhook-Signature: t=\u003cunix\u003e,v1=\u003chex\u003e.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create webhook subscription",
+                "parameters": [
+                    {
+                        "description": "Subscription",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.WebhookSubscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/dead-letters": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deliveries that exhausted their retries",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Dead-lettered webhook deliveries",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries/{id}/retry": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Retry a dead-lettered delivery",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.WebhookSubscription"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update webhook subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.WebhookSubscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete webhook subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Most recent deliveries for a subscription, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhook delivery log",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, succeeded or dead",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/school-teacher-management_internal_model.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "school-teacher-management_internal_geo.Point": {
+            "type": "object",
+            "properties": {
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.Actor": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.Attendance": {
+            "type": "object",
+            "required": [
+                "status",
+                "teacher_id"
+            ],
+            "properties": {
+                "check_in": {
+                    "type": "string"
+                },
+                "check_in_campus_id": {
+                    "description": "Campuses the punches were made at.",
+                    "type": "integer"
+                },
+                "check_in_device_id": {
+                    "description": "Devices the punches were submitted from; DeviceUnapproved marks a\npunch from a device the teacher is not approved to use.",
+                    "type": "integer"
+                },
+                "check_in_device_unapproved": {
+                    "type": "boolean"
+                },
+                "check_in_ip": {
+                    "description": "Client addresses of the punches; OffNetwork marks a direct punch from\noutside every campus network.",
+                    "type": "string"
+                },
+                "check_in_kiosk_id": {
+                    "description": "The kiosk and terminal IDs are set for punches scanned at a kiosk or\nentered on a shared terminal.",
+                    "type": "integer"
+                },
+                "check_in_location": {
+                    "description": "Reported device positions; unset for kiosk and terminal punches.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.PunchLocation"
+                        }
+                    ]
+                },
+                "check_in_off_network": {
+                    "type": "boolean"
+                },
+                "check_in_offline": {
+                    "description": "Offline marks punches queued on a device and synced later; their\ntimes come from the device clock.",
+                    "type": "boolean"
+                },
+                "check_in_terminal_id": {
+                    "type": "integer"
+                },
+                "check_out": {
+                    "type": "string"
+                },
+                "check_out_campus_id": {
+                    "type": "integer"
+                },
+                "check_out_device_id": {
+                    "type": "integer"
+                },
+                "check_out_device_unapproved": {
+                    "type": "boolean"
+                },
+                "check_out_ip": {
+                    "type": "string"
+                },
+                "check_out_kiosk_id": {
+                    "type": "integer"
+                },
+                "check_out_location": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.PunchLocation"
+                },
+                "check_out_off_network": {
+                    "type": "boolean"
+                },
+                "check_out_offline": {
+                    "type": "boolean"
+                },
+                "check_out_terminal_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "enum": [
+                        "present",
+                        "absent",
+                        "leave",
+                        "holiday",
+                        "half_day",
+                        "on_duty",
+                        "work_from_home"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceStatus"
+                        }
+                    ],
+                    "example": "present"
+                },
+                "teacher": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is bumped by every update and served as the ETag.",
+                    "type": "integer"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.AttendanceChange": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Actor"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "attendance_id": {
+                    "type": "integer"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "correction_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.AttendanceCorrection": {
+            "type": "object",
+            "properties": {
+                "attendance_id": {
+                    "type": "integer"
+                },
+                "check_in": {
+                    "type": "string"
+                },
+                "check_out": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Actor"
+                },
+                "decision_note": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "requested_by": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Actor"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "teacher": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.AttendanceCorrectionInput": {
+            "type": "object",
+            "required": [
+                "date",
+                "reason"
+            ],
+            "properties": {
+                "check_in": {
+                    "type": "string"
+                },
+                "check_out": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2026-01-15"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.AttendanceDTO": {
+            "type": "object",
+            "properties": {
+                "checkIn": {
+                    "type": "string"
+                },
+                "checkInCampusId": {
+                    "description": "Campuses the check-in and check-out were made at.",
+                    "type": "integer"
+                },
+                "checkInDeviceId": {
+                    "description": "Devices the check-in and check-out were submitted from.",
+                    "type": "integer"
+                },
+                "checkOut": {
+                    "type": "string"
+                },
+                "checkOutCampusId": {
+                    "type": "integer"
+                },
+                "checkOutDeviceId": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "teacherId": {
+                    "type": "integer"
+                },
+                "teacherName": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.AttendanceEvent": {
+            "type": "object",
+            "properties": {
+                "attendance_id": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "department": {
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceStatus"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "teacher_name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.AttendancePeriod": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "locked_at": {
+                    "type": "string"
+                },
+                "locked_by": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Actor"
+                },
+                "month": {
+                    "type": "integer"
+                },
+                "reopened_at": {
+                    "type": "string"
+                },
+                "reopened_by": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Actor"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.AttendanceRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "accuracy": {
+                    "type": "number",
+                    "example": 15
+                },
+                "campus_id": {
+                    "description": "Campus the teacher is punching at. A matching geofence takes\nprecedence; without either the teacher's home campus is recorded.",
+                    "type": "integer"
+                },
+                "latitude": {
+                    "description": "Optional device position, checked against the campus geofences.",
+                    "type": "number",
+                    "example": 12.9716
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 77.5946
+                },
+                "status": {
+                    "description": "Status is the punch; the day's status follows from it.",
+                    "enum": [
+                        "checkIn",
+                        "checkOut"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.PunchType"
+                        }
+                    ],
+                    "example": "checkIn"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.AttendanceResponse": {
+            "type": "object",
+            "properties": {
+                "attendanceList": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceDTO"
+                    }
+                }
+            }
+        },
+        "school-teacher-management_internal_model.AttendanceSnapshot": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "period_id": {
+                    "type": "integer"
+                },
+                "register": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/school-teacher-management_internal_model.RegisterEntry"
+                    }
+                },
+                "taken_by": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Actor"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.AttendanceStatus": {
+            "type": "string",
+            "enum": [
+                "present",
+                "absent",
+                "leave",
+                "holiday",
+                "half_day",
+                "on_duty",
+                "work_from_home"
+            ],
+            "x-enum-varnames": [
+                "StatusPresent",
+                "StatusAbsent",
+                "StatusLeave",
+                "StatusHoliday",
+                "StatusHalfDay",
+                "StatusOnDuty",
+                "StatusWorkFromHome"
+            ]
+        },
+        "school-teacher-management_internal_model.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Actor"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "integer"
+                },
+                "entity_type": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "prev_hash": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.AuditVerification": {
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "type": "integer"
+                },
+                "first_invalid_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.Campus": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "address": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "work_end": {
+                    "type": "string"
+                },
+                "work_start": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.CampusInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "address": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Junior campus"
+                },
+                "work_end": {
+                    "type": "string",
+                    "example": "15:00"
+                },
+                "work_start": {
+                    "type": "string",
+                    "example": "08:00"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.CampusNetwork": {
+            "type": "object",
+            "properties": {
+                "campus_id": {
+                    "type": "integer"
+                },
+                "cidr": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "school_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.CampusNetworkInput": {
+            "type": "object",
+            "required": [
+                "campus_id",
+                "cidr"
+            ],
+            "properties": {
+                "campus_id": {
+                    "type": "integer"
+                },
+                "cidr": {
+                    "type": "string",
+                    "example": "10.20.0.0/16"
+                },
+                "description": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.CompOffBalance": {
+            "type": "object",
+            "properties": {
+                "available_days": {
+                    "type": "number"
+                },
+                "credits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/school-teacher-management_internal_model.CompOffCredit"
+                    }
+                },
+                "teacher_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.CompOffCredit": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "days": {
+                    "type": "number"
+                },
+                "earned_on": {
+                    "type": "string"
+                },
+                "expires_on": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "overtime_id": {
+                    "type": "integer"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "used_days": {
+                    "type": "number"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.CorrectionDecisionInput": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.Device": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "identifier": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "integer"
+                },
+                "platform": {
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "shared": {
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.DeviceBinding": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "description": "Rejecting an approved binding revokes it.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.Actor"
+                        }
+                    ]
+                },
+                "device": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Device"
+                },
+                "device_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "teacher": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.DeviceInput": {
+            "type": "object",
+            "required": [
+                "identifier",
+                "name"
+            ],
+            "properties": {
+                "identifier": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "platform": {
+                    "type": "string"
+                },
+                "shared": {
+                    "description": "Shared may only be set by an administrator.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "required"
+                },
+                "field": {
+                    "type": "string",
+                    "example": "email"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "is required"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.Geofence": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "campus_id": {
+                    "type": "integer"
+                },
+                "center": {
+                    "$ref": "#/definitions/school-teacher-management_internal_geo.Point"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "polygon": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/school-teacher-management_internal_geo.Point"
+                    }
+                },
+                "radius_meters": {
+                    "type": "number"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.GeofenceInput": {
+            "type": "object",
+            "required": [
+                "name",
+                "type"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "campus_id": {
+                    "type": "integer"
+                },
+                "center": {
+                    "$ref": "#/definitions/school-teacher-management_internal_geo.Point"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "polygon": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/school-teacher-management_internal_geo.Point"
+                    }
+                },
+                "radius_meters": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "radius"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.Holiday": {
+            "type": "object",
+            "properties": {
+                "campus_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.HolidayInput": {
+            "type": "object",
+            "required": [
+                "date",
+                "name"
+            ],
+            "properties": {
+                "campus_id": {
+                    "description": "Only this campus is closed; the whole school when unset.",
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2026-12-25"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.Incident": {
+            "type": "object",
+            "properties": {
+                "attendance_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "related_attendance_id": {
+                    "type": "integer"
+                },
+                "related_teacher_id": {
+                    "type": "integer"
+                },
+                "review_note": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Actor"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "teacher": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.IncidentReviewInput": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.IncidentScanResult": {
+            "type": "object",
+            "properties": {
+                "flagged": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.Kiosk": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "campus_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.KioskCheckInInput": {
+            "type": "object",
+            "required": [
+                "status",
+                "token"
+            ],
+            "properties": {
+                "status": {
+                    "enum": [
+                        "checkIn",
+                        "checkOut"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.PunchType"
+                        }
+                    ],
+                    "example": "checkIn"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.KioskInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "campus_id": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.KioskRegistration": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "kiosk": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Kiosk"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.KioskToken": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.LeaveDecisionInput": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.LeaveRequest": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "type": "integer"
+                },
+                "decision_note": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "teacher": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.LeaveRequestInput": {
+            "type": "object",
+            "required": [
+                "end_date",
+                "reason",
+                "start_date"
+            ],
+            "properties": {
+                "end_date": {
+                    "type": "string",
+                    "example": "2026-01-16"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2026-01-15"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "general"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.NotificationPreference": {
+            "type": "object",
+            "properties": {
+                "checkout_reminders": {
+                    "type": "boolean"
+                },
+                "daily_digest": {
+                    "type": "boolean"
+                },
+                "late_alerts": {
+                    "type": "boolean"
+                },
+                "leave_updates": {
+                    "type": "boolean"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.NotificationPreferenceRequest": {
+            "type": "object",
+            "properties": {
+                "checkout_reminders": {
+                    "type": "boolean"
+                },
+                "daily_digest": {
+                    "type": "boolean"
+                },
+                "late_alerts": {
+                    "type": "boolean"
+                },
+                "leave_updates": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.OvertimeDecisionInput": {
+            "type": "object",
+            "properties": {
+                "compensation": {
+                    "description": "Compensation applies to approvals: comp_off (default) or paid.",
+                    "type": "string",
+                    "example": "comp_off"
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.OvertimeRecord": {
+            "type": "object",
+            "properties": {
+                "attendance_id": {
+                    "type": "integer"
+                },
+                "compensation": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Actor"
+                },
+                "decision_note": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "minutes": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "teacher": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Teacher"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.PINInput": {
+            "type": "object",
+            "required": [
+                "pin"
+            ],
+            "properties": {
+                "pin": {
+                    "type": "string",
+                    "maxLength": 8,
+                    "minLength": 4,
+                    "example": "4821"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.PayrollLine": {
+            "type": "object",
+            "properties": {
+                "absent_days": {
+                    "type": "integer"
+                },
+                "campus_id": {
+                    "type": "integer"
+                },
+                "department": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "half_days": {
+                    "description": "HalfDays are present days worked short of the policy's hours;\nMarkedHalfDays have the half_day status and are paid half.",
+                    "type": "integer"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "late_arrivals": {
+                    "type": "integer"
+                },
+                "late_deductions": {
+                    "type": "integer"
+                },
+                "leave_days": {
+                    "type": "integer"
+                },
+                "lop_days": {
+                    "type": "number"
+                },
+                "marked_half_days": {
+                    "type": "integer"
+                },
+                "overtime_hours": {
+                    "type": "number"
+                },
+                "payable_days": {
+                    "type": "number"
+                },
+                "present_days": {
+                    "type": "integer"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "teacher_id": {
+                    "type": "integer"
+                },
+                "working_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.PayrollReport": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/school-teacher-management_internal_model.PayrollLine"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.PeriodComparison": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/school-teacher-management_internal_model.RegisterEntry"
+                    }
+                },
+                "changed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/school-teacher-management_internal_model.RegisterChange"
+                    }
+                },
+                "month": {
+                    "type": "integer"
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/school-teacher-management_internal_model.RegisterEntry"
+                    }
+                },
+                "snapshot_id": {
+                    "type": "integer"
+                },
+                "taken_at": {
+                    "type": "string"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "period_locked"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "attendance period is closed"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/school-teacher-management_internal_model.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/api/v1/attendance"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 409
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Conflict"
+                },
+                "trace_id": {
+                    "type": "string",
+                    "example": "4bf92f3577b34da6a3ce929d0e0e4736"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.PunchLocation": {
+            "type": "object",
+            "properties": {
+                "accuracy": {
+                    "type": "number"
+                },
+                "geofence": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "out_of_fence": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.PunchResult": {
+            "type": "object",
+            "properties": {
+                "attendance_id": {
+                    "type": "integer"
+                },
+                "idempotency_key": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "recorded_at": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.PunchType"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.PunchSyncInput": {
+            "type": "object",
+            "required": [
+                "punches",
+                "sent_at"
+            ],
+            "properties": {
+                "punches": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/school-teacher-management_internal_model.QueuedPunch"
+                    }
+                },
+                "sent_at": {
+                    "description": "SentAt is the device clock when the batch was sent; the difference\nto the server clock is the device's clock skew.",
+                    "type": "string",
+                    "example": "2026-10-18T10:02:00+05:30"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.PunchSyncReport": {
+            "type": "object",
+            "properties": {
+                "clock_skew_seconds": {
+                    "description": "ClockSkewSeconds is how far the device clock was behind the server\n(negative when ahead); Corrected reports whether punch times were\nshifted by it.",
+                    "type": "integer"
+                },
+                "corrected": {
+                    "type": "boolean"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/school-teacher-management_internal_model.PunchResult"
+                    }
+                }
+            }
+        },
+        "school-teacher-management_internal_model.PunchType": {
+            "type": "string",
+            "enum": [
+                "checkIn",
+                "checkOut"
+            ],
+            "x-enum-varnames": [
+                "PunchCheckIn",
+                "PunchCheckOut"
+            ]
+        },
+        "school-teacher-management_internal_model.QueuedPunch": {
+            "type": "object",
+            "required": [
+                "idempotency_key",
+                "punched_at",
+                "status"
+            ],
+            "properties": {
+                "idempotency_key": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "punched_at": {
+                    "type": "string",
+                    "example": "2026-10-18T08:55:00+05:30"
+                },
+                "status": {
+                    "enum": [
+                        "checkIn",
+                        "checkOut"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.PunchType"
+                        }
+                    ]
+                }
+            }
+        },
+        "school-teacher-management_internal_model.RegisterChange": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.RegisterEntry"
+                },
+                "before": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.RegisterEntry"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.RegisterEntry": {
+            "type": "object",
+            "properties": {
+                "attendance_id": {
+                    "type": "integer"
+                },
+                "check_in": {
+                    "type": "string"
+                },
+                "check_out": {
+                    "type": "string"
                 },
                 "date": {
                     "type": "string"
                 },
-                "teacherId": {
+                "status": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceStatus"
+                },
+                "teacher_id": {
                     "type": "integer"
                 },
-                "teacherName": {
+                "teacher_name": {
                     "type": "string"
                 }
             }
         },
-        "school-teacher-management_internal_model.AttendanceRequest": {
+        "school-teacher-management_internal_model.School": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "geofence_policy": {
+                    "description": "GeofencePolicy and NetworkPolicy are off, flag or reject, as\nGEOFENCE_POLICY and NETWORK_POLICY.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "network_policy": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "work_end": {
+                    "type": "string"
+                },
+                "work_start": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.SchoolInput": {
             "type": "object",
             "required": [
-                "status",
-                "teacher_id"
+                "name",
+                "slug"
             ],
             "properties": {
-                "status": {
+                "active": {
+                    "type": "boolean"
+                },
+                "geofence_policy": {
+                    "description": "Empty policies follow the deployment-wide settings.",
+                    "type": "string",
+                    "enum": [
+                        "off",
+                        "flag",
+                        "reject"
+                    ],
+                    "example": "reject"
+                },
+                "name": {
                     "type": "string"
                 },
-                "teacher_id": {
-                    "type": "integer"
+                "network_policy": {
+                    "type": "string",
+                    "enum": [
+                        "off",
+                        "flag",
+                        "reject"
+                    ],
+                    "example": "flag"
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "st-marys"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Europe/London"
+                },
+                "work_end": {
+                    "type": "string",
+                    "example": "16:30"
+                },
+                "work_start": {
+                    "type": "string",
+                    "example": "08:30"
                 }
             }
         },
-        "school-teacher-management_internal_model.AttendanceResponse": {
+        "school-teacher-management_internal_model.SchoolSummary": {
             "type": "object",
             "properties": {
-                "attendanceList": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/school-teacher-management_internal_model.AttendanceDTO"
-                    }
+                "absent_days": {
+                    "type": "integer"
+                },
+                "attendance_rate": {
+                    "description": "Attendance is the percentage of present days among present and\nabsent ones.",
+                    "type": "number"
+                },
+                "late_arrivals": {
+                    "type": "integer"
+                },
+                "leave_days": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "present_days": {
+                    "type": "integer"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "teachers": {
+                    "type": "integer"
+                },
+                "working_days": {
+                    "type": "integer"
                 }
             }
         },
@@ -628,12 +6043,24 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "department": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
+                "employee_code": {
+                    "type": "string"
+                },
                 "first_name": {
                     "type": "string"
                 },
+                "head_of_department": {
+                    "type": "boolean"
+                },
+                "home_campus_id": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -643,11 +6070,18 @@ const docTemplate = `{
                 "phone": {
                     "type": "string"
                 },
+                "school_id": {
+                    "type": "integer"
+                },
                 "subject": {
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "description": "Version is bumped by every update and served as the ETag.",
+                    "type": "integer"
                 }
             }
         },
@@ -659,12 +6093,24 @@ const docTemplate = `{
                 "last_name"
             ],
             "properties": {
+                "department": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
+                "employee_code": {
+                    "type": "string"
+                },
                 "first_name": {
                     "type": "string"
                 },
+                "head_of_department": {
+                    "type": "boolean"
+                },
+                "home_campus_id": {
+                    "type": "integer"
+                },
                 "last_name": {
                     "type": "string"
                 },
@@ -675,6 +6121,219 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "school-teacher-management_internal_model.Terminal": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "campus_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.TerminalCheckInInput": {
+            "type": "object",
+            "required": [
+                "employee_code",
+                "pin",
+                "status"
+            ],
+            "properties": {
+                "employee_code": {
+                    "type": "string"
+                },
+                "pin": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "checkIn",
+                        "checkOut"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/school-teacher-management_internal_model.PunchType"
+                        }
+                    ],
+                    "example": "checkIn"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.TerminalInput": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "campus_id": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.TerminalRegistration": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "terminal": {
+                    "$ref": "#/definitions/school-teacher-management_internal_model.Terminal"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.TrustReport": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "schools": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/school-teacher-management_internal_model.SchoolSummary"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "type": "integer"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "outbox_event_id": {
+                    "type": "integer"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.WebhookRequest": {
+            "type": "object",
+            "required": [
+                "event_types",
+                "secret",
+                "url"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "event_types": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "attendance.check_in",
+                        "attendance.absent"
+                    ]
+                },
+                "secret": {
+                    "type": "string",
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "school-teacher-management_internal_model.WebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "school_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -686,7 +6345,7 @@ var SwaggerInfo = &swag.Spec{
 	BasePath:         "/api/v1",
 	Schemes:          []string{"http"},
 	Title:            "School Teacher Management API",
-	Description:      "REST APIs for managing teachers and attendance records.",
+	Description:      "REST APIs for managing teachers and attendance records.\nEvery POST accepts an Idempotency-Key header: a retry with the same key and body gets the first response again (marked Idempotent-Replayed), and reusing the key for another body is refused with 422.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
@@ -0,0 +1,100 @@
+// Package apperror defines the errors services return for failures a
+// client can act on. Each has a kind, which decides the HTTP status, and a
+// stable code clients can match on instead of the message.
+package apperror
+
+import (
+	"errors"
+
+	"school-teacher-management/internal/model"
+)
+
+type Kind int
+
+const (
+	KindValidation Kind = iota + 1
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindRule
+	KindLocked
+	KindPrecondition
+	KindUnsupportedMedia
+)
+
+// Error is compared by identity, so package-level errors can be matched
+// with errors.Is.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []model.FieldError
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Validation is for input that is malformed or out of range.
+func Validation(code, message string, fields ...model.FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message, Fields: fields}
+}
+
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+// Conflict is for a request that clashes with the current state, such as
+// a duplicate or a write into a closed period.
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// Rule is for well-formed input that a business rule refuses, such as a
+// check-out without a check-in.
+func Rule(code, message string) *Error {
+	return &Error{Kind: KindRule, Code: code, Message: message}
+}
+
+// Locked is for a resource that is temporarily refused, such as a PIN
+// after repeated failures.
+func Locked(code, message string) *Error {
+	return &Error{Kind: KindLocked, Code: code, Message: message}
+}
+
+// PreconditionFailed is for a conditional request, such as one with
+// If-Match, whose condition does not hold.
+func PreconditionFailed(code, message string) *Error {
+	return &Error{Kind: KindPrecondition, Code: code, Message: message}
+}
+
+// UnsupportedMedia is for a request body of a content type the endpoint
+// does not accept.
+func UnsupportedMedia(code, message string) *Error {
+	return &Error{Kind: KindUnsupportedMedia, Code: code, Message: message}
+}
+
+// Field returns a validation error about one field of the request.
+func Field(field, code, message string) *Error {
+	return Validation("validation_failed", field+" "+message, model.FieldError{
+		Field:   field,
+		Code:    code,
+		Message: message,
+	})
+}
+
+// As returns the *Error in err's chain, if any.
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	ok := errors.As(err, &appErr)
+	return appErr, ok
+}
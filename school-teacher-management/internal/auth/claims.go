@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	RoleAdmin   = "admin"
+	RoleOffice  = "office"
+	RoleHOD     = "hod"
+	RoleTeacher = "teacher"
+)
+
+// Claims are the fields this service reads from a bearer token.
+type Claims struct {
+	TeacherID  uint   `json:"teacher_id,omitempty"`
+	Role       string `json:"role"`
+	Department string `json:"department,omitempty"`
+	// SchoolID scopes the token to one school. An admin token without one
+	// is a trust-level administrator's, valid for every school.
+	SchoolID uint `json:"school_id,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// HasRole reports whether the claims carry one of the given roles.
+func (c *Claims) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if c.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTrustAdmin reports whether the claims are those of a trust-level
+// administrator, who may act across schools.
+func (c *Claims) IsTrustAdmin() bool {
+	return c.Role == RoleAdmin && c.SchoolID == 0
+}
+
+// CanViewTeacher reports whether the caller may see data belonging to a
+// teacher of the given department.
+func (c *Claims) CanViewTeacher(teacherID uint, department string) bool {
+	switch c.Role {
+	case RoleAdmin, RoleOffice:
+		return true
+	case RoleHOD:
+		return department != "" && department == c.Department
+	default:
+		return teacherID != 0 && teacherID == c.TeacherID
+	}
+}
+
+// ParseToken verifies an HS256 signed token and returns its claims.
+func ParseToken(token string, secret []byte) (*Claims, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("token verification is not configured")
+	}
+
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}))
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Role == "" {
+		return nil, errors.New("token has no role")
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const kioskAudience = "kiosk-check-in"
+
+// KioskClaims identify the kiosk that displayed a QR token.
+type KioskClaims struct {
+	KioskID uint `json:"kiosk_id"`
+	jwt.RegisteredClaims
+}
+
+// IssueKioskToken signs a QR token for the kiosk, valid for ttl.
+func IssueKioskToken(kioskID uint, ttl time.Duration, secret []byte) (string, time.Time, error) {
+	if len(secret) == 0 {
+		return "", time.Time{}, errors.New("kiosk tokens are not configured")
+	}
+
+	now := time.Now()
+	expires := now.Add(ttl)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, KioskClaims{
+		KioskID: kioskID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{kioskAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expires),
+		},
+	})
+
+	signed, err := token.SignedString(secret)
+	return signed, expires, err
+}
+
+// ParseKioskToken verifies the signature, audience and expiry of a QR token.
+func ParseKioskToken(token string, secret []byte) (*KioskClaims, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("kiosk tokens are not configured")
+	}
+
+	claims := &KioskClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}),
+		jwt.WithAudience(kioskAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,30 @@
+package config
+
+import "time"
+
+// AnomalyPolicy tunes the scan for suspicious attendance patterns.
+type AnomalyPolicy struct {
+	// Interval is how often the background scan runs, over the last
+	// Lookback days.
+	Interval time.Duration
+	Lookback int
+	// SameSourceWindow flags punches by different teachers from one device
+	// or address this close together.
+	SameSourceWindow time.Duration
+	// RepeatedDays flags a teacher checking in at the same second of the
+	// day on this many days.
+	RepeatedDays int
+	// MaxTravelKmh flags consecutive located punches further apart than
+	// a trip at this speed allows.
+	MaxTravelKmh float64
+}
+
+func Anomaly() AnomalyPolicy {
+	return AnomalyPolicy{
+		Interval:         time.Duration(getInt("ANOMALY_SCAN_INTERVAL_MINUTES", 60)) * time.Minute,
+		Lookback:         getInt("ANOMALY_LOOKBACK_DAYS", 14),
+		SameSourceWindow: time.Duration(getInt("ANOMALY_SAME_SOURCE_SECONDS", 30)) * time.Second,
+		RepeatedDays:     getInt("ANOMALY_REPEATED_DAYS", 5),
+		MaxTravelKmh:     getFloat("ANOMALY_MAX_TRAVEL_KMH", 80),
+	}
+}
@@ -0,0 +1,8 @@
+package config
+
+import "os"
+
+// JWTSecret returns the HMAC secret used to verify bearer tokens.
+func JWTSecret() []byte {
+	return []byte(os.Getenv("JWT_SECRET"))
+}
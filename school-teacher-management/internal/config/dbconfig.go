@@ -1,23 +1,52 @@
 package config
 
 import (
-	"fmt"
-	"log"
+	"log/slog"
+	"os"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 var DB *gorm.DB
 
-func ConnectDatabase() {
-	dsn := "host=localhost user=postgres password=root dbname=school_techer_management port=5432 sslmode=disable"
+// DatabaseDSN returns the Postgres connection string, taken from
+// DATABASE_URL when set.
+func DatabaseDSN() string {
+	if dsn := os.Getenv("DATABASE_URL"); dsn != "" {
+		return dsn
+	}
+	return "host=localhost user=postgres password=root dbname=school_techer_management port=5432 sslmode=disable"
+}
+
+// ConnectDatabase opens the connection pool. SQL statements are logged to
+// logger with the context they ran with: failures and slow statements
+// always, every statement at debug level.
+func ConnectDatabase(logger *slog.Logger, cfg LogConfig) error {
+	dsn := DatabaseDSN()
+
+	sqlLevel := gormlogger.Warn
+	if cfg.Level <= slog.LevelDebug {
+		sqlLevel = gormlogger.Info
+	}
 
-	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// TranslateError turns unique and foreign key violations into
+	// gorm.ErrDuplicatedKey and gorm.ErrForeignKeyViolated, which the API
+	// answers with 409 instead of a 500 quoting Postgres.
+	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		TranslateError: true,
+		Logger: gormlogger.NewSlogLogger(logger, gormlogger.Config{
+			SlowThreshold:             cfg.SlowQuery,
+			LogLevel:                  sqlLevel,
+			IgnoreRecordNotFoundError: true,
+		}),
+	})
 	if err != nil {
-		log.Fatal("Failed to connect to database")
+		return err
 	}
 
 	DB = database
-	fmt.Println("Database connected successfully")
+	logger.Info("Database connected successfully")
+	return nil
 }
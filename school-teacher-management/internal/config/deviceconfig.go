@@ -0,0 +1,16 @@
+package config
+
+// Device policies for punches from a device the teacher is not bound to.
+const (
+	DeviceOff    = "off"
+	DeviceFlag   = "flag"
+	DeviceReject = "reject"
+)
+
+func DevicePolicy() string {
+	mode := getEnv("DEVICE_POLICY", DeviceFlag)
+	if mode != DeviceOff && mode != DeviceReject {
+		return DeviceFlag
+	}
+	return mode
+}
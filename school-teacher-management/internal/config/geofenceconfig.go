@@ -0,0 +1,31 @@
+package config
+
+// Geofence policies for punches outside every active geofence.
+const (
+	GeofenceOff    = "off"
+	GeofenceFlag   = "flag"
+	GeofenceReject = "reject"
+)
+
+type GeofencePolicy struct {
+	// Mode is off, flag (record the punch and mark it) or reject, for
+	// schools that set no geofence policy of their own.
+	Mode string
+	// RequireLocation treats a punch without coordinates as outside.
+	RequireLocation bool
+	// MaxAccuracyMeters treats coarser fixes as outside.
+	MaxAccuracyMeters float64
+}
+
+func Geofence() GeofencePolicy {
+	mode := getEnv("GEOFENCE_POLICY", GeofenceFlag)
+	if mode != GeofenceOff && mode != GeofenceReject {
+		mode = GeofenceFlag
+	}
+
+	return GeofencePolicy{
+		Mode:              mode,
+		RequireLocation:   getEnv("GEOFENCE_REQUIRE_LOCATION", "false") == "true",
+		MaxAccuracyMeters: getFloat("GEOFENCE_MAX_ACCURACY_METERS", 100),
+	}
+}
@@ -0,0 +1,9 @@
+package config
+
+import "time"
+
+// IdempotencyTTL is how long the response to a POST sent with an
+// Idempotency-Key is kept for replay to retries.
+func IdempotencyTTL() time.Duration {
+	return time.Duration(getInt("IDEMPOTENCY_TTL_HOURS", 24)) * time.Hour
+}
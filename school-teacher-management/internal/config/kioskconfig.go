@@ -0,0 +1,18 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// KioskTokenSecret signs the QR tokens shown by kiosks. It is separate from
+// JWT_SECRET so a QR token can never pass as a bearer token.
+func KioskTokenSecret() []byte {
+	return []byte(os.Getenv("KIOSK_TOKEN_SECRET"))
+}
+
+// KioskTokenTTL is how long a QR token stays valid; kiosks should refresh
+// well before it runs out.
+func KioskTokenTTL() time.Duration {
+	return time.Duration(getInt("KIOSK_TOKEN_TTL_SECONDS", 30)) * time.Second
+}
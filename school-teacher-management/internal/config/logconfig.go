@@ -0,0 +1,30 @@
+package config
+
+import (
+	"log/slog"
+	"time"
+)
+
+type LogConfig struct {
+	// Format is json or text.
+	Format string
+	Level  slog.Level
+	// SlowQuery is the duration above which SQL statements are logged as
+	// warnings.
+	SlowQuery time.Duration
+}
+
+// Log reads LOG_FORMAT, LOG_LEVEL (debug, info, warn or error) and
+// LOG_SLOW_QUERY_MS. At debug level every SQL statement is logged.
+func Log() LogConfig {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(getEnv("LOG_LEVEL", "info"))); err != nil {
+		level = slog.LevelInfo
+	}
+
+	return LogConfig{
+		Format:    getEnv("LOG_FORMAT", "json"),
+		Level:     level,
+		SlowQuery: time.Duration(getInt("LOG_SLOW_QUERY_MS", 200)) * time.Millisecond,
+	}
+}
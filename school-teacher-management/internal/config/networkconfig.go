@@ -0,0 +1,32 @@
+package config
+
+import "strings"
+
+// Network policies for punches from outside every campus network.
+const (
+	NetworkOff    = "off"
+	NetworkFlag   = "flag"
+	NetworkReject = "reject"
+)
+
+// NetworkPolicy applies to schools that set no network policy of their own.
+func NetworkPolicy() string {
+	mode := getEnv("NETWORK_POLICY", NetworkFlag)
+	if mode != NetworkOff && mode != NetworkReject {
+		return NetworkFlag
+	}
+	return mode
+}
+
+// TrustedProxies lists the proxies, as IPs or CIDRs, whose X-Forwarded-For
+// and X-Real-IP headers are believed when working out the client IP. With
+// none set the connection's peer address is used.
+func TrustedProxies() []string {
+	var proxies []string
+	for _, p := range strings.Split(getEnv("TRUSTED_PROXIES", ""), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTP defaults to a local sink such as MailHog on port 1025.
+func SMTP() SMTPConfig {
+	return SMTPConfig{
+		Host:     getEnv("SMTP_HOST", "localhost"),
+		Port:     getEnv("SMTP_PORT", "1025"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     getEnv("SMTP_FROM", "attendance@school.local"),
+	}
+}
+
+// NotificationSchedule holds times of day, as offsets from midnight in each
+// school's timezone, at which the notification jobs run.
+type NotificationSchedule struct {
+	WorkStart        time.Duration
+	LateAlertAt      time.Duration
+	CheckoutReminder time.Duration
+	DigestAt         time.Duration
+}
+
+func Notifications() NotificationSchedule {
+	return NotificationSchedule{
+		WorkStart:        getClock("WORK_START", "09:00"),
+		LateAlertAt:      getClock("NOTIFY_LATE_AT", "09:30"),
+		CheckoutReminder: getClock("NOTIFY_CHECKOUT_AT", "17:30"),
+		DigestAt:         getClock("NOTIFY_DIGEST_AT", "18:00"),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// getClock parses an HH:MM value, falling back when it is missing or invalid.
+func getClock(key, fallback string) time.Duration {
+	t, err := time.Parse("15:04", getEnv(key, fallback))
+	if err != nil {
+		t, _ = time.Parse("15:04", fallback)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+}
@@ -0,0 +1,29 @@
+package config
+
+import "time"
+
+// OvertimePolicy defines the scheduled day that overtime is measured
+// against and how approved overtime becomes compensatory-off leave.
+type OvertimePolicy struct {
+	WorkStart time.Duration
+	WorkEnd   time.Duration
+	// MinMinutes is the shortest overtime that is recorded.
+	MinMinutes int
+	// CreditValidity is how long a compensatory-off credit can be used.
+	CreditValidity time.Duration
+}
+
+func Overtime() OvertimePolicy {
+	return OvertimePolicy{
+		WorkStart:      getClock("WORK_START", "09:00"),
+		WorkEnd:        getClock("WORK_END", "17:00"),
+		MinMinutes:     getInt("OVERTIME_MIN_MINUTES", 30),
+		CreditValidity: time.Duration(getInt("COMPOFF_VALID_DAYS", 90)) * 24 * time.Hour,
+	}
+}
+
+// DayLength is the scheduled working day; a compensatory-off day is worth
+// this much overtime.
+func (p OvertimePolicy) DayLength() time.Duration {
+	return p.WorkEnd - p.WorkStart
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPayrollColumns is the CSV layout used when PAYROLL_CSV_COLUMNS is
+// not set.
+const DefaultPayrollColumns = "teacher_id,first_name,last_name,department,working_days,present_days,leave_days,absent_days,half_days,lop_days,payable_days,overtime_hours,late_arrivals,late_deductions"
+
+// PayrollPolicy holds the rules the payroll export applies to attendance.
+type PayrollPolicy struct {
+	WorkStart time.Duration
+	LateGrace time.Duration
+
+	// HalfDayBelowHours marks a day worked for less than this as a half day.
+	HalfDayBelowHours float64
+	// HalfDaysAllowed per pay period are paid; each further half day is
+	// half a day's loss of pay.
+	HalfDaysAllowed int
+	// LatesPerDeduction late arrivals make one late deduction.
+	LatesPerDeduction int
+
+	CSVColumns []string
+}
+
+func Payroll() PayrollPolicy {
+	return PayrollPolicy{
+		WorkStart:         getClock("WORK_START", "09:00"),
+		LateGrace:         time.Duration(getInt("PAYROLL_LATE_GRACE_MINUTES", 10)) * time.Minute,
+		HalfDayBelowHours: getFloat("PAYROLL_HALF_DAY_BELOW_HOURS", 6),
+		HalfDaysAllowed:   getInt("PAYROLL_HALF_DAYS_ALLOWED", 2),
+		LatesPerDeduction: getInt("PAYROLL_LATES_PER_DEDUCTION", 3),
+		CSVColumns:        strings.Split(getEnv("PAYROLL_CSV_COLUMNS", DefaultPayrollColumns), ","),
+	}
+}
+
+// getInt and getFloat fall back when the value is missing or invalid.
+func getInt(key string, fallback int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return n
+}
+
+func getFloat(key string, fallback float64) float64 {
+	f, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return fallback
+	}
+	return f
+}
@@ -0,0 +1,30 @@
+package config
+
+import "time"
+
+type ServerConfig struct {
+	Port string
+	// Timeouts of the HTTP server. The write timeout does not apply to the
+	// attendance streams, which lift it for their connection.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// DrainDelay is how long the server keeps serving on SIGTERM after the
+	// readiness probe starts failing, so the load balancer notices and stops
+	// routing to it first. It should be at least one probe period.
+	DrainDelay time.Duration
+	// ShutdownTimeout bounds how long in-flight requests are drained for
+	// on SIGTERM.
+	ShutdownTimeout time.Duration
+}
+
+func Server() ServerConfig {
+	return ServerConfig{
+		Port:            getEnv("PORT", "8082"),
+		ReadTimeout:     time.Duration(getInt("SERVER_READ_TIMEOUT_SECONDS", 15)) * time.Second,
+		WriteTimeout:    time.Duration(getInt("SERVER_WRITE_TIMEOUT_SECONDS", 60)) * time.Second,
+		IdleTimeout:     time.Duration(getInt("SERVER_IDLE_TIMEOUT_SECONDS", 120)) * time.Second,
+		DrainDelay:      time.Duration(getInt("SERVER_DRAIN_DELAY_SECONDS", 10)) * time.Second,
+		ShutdownTimeout: time.Duration(getInt("SERVER_SHUTDOWN_TIMEOUT_SECONDS", 30)) * time.Second,
+	}
+}
@@ -0,0 +1,33 @@
+package config
+
+import "time"
+
+// Clock-skew policies for offline punch batches from a device whose clock
+// is off by more than the tolerance.
+const (
+	SkewCorrect = "correct"
+	SkewReject  = "reject"
+)
+
+type SyncPolicy struct {
+	// MaxSkew is the clock difference accepted without correction.
+	MaxSkew time.Duration
+	// SkewMode is correct (shift punch times by the measured skew) or
+	// reject (refuse the batch's punches).
+	SkewMode string
+	// MaxAge refuses punches older than this.
+	MaxAge time.Duration
+}
+
+func Sync() SyncPolicy {
+	mode := getEnv("SYNC_SKEW_POLICY", SkewCorrect)
+	if mode != SkewReject {
+		mode = SkewCorrect
+	}
+
+	return SyncPolicy{
+		MaxSkew:  time.Duration(getInt("SYNC_MAX_CLOCK_SKEW_SECONDS", 120)) * time.Second,
+		SkewMode: mode,
+		MaxAge:   time.Duration(getInt("SYNC_MAX_AGE_HOURS", 72)) * time.Hour,
+	}
+}
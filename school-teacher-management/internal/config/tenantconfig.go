@@ -0,0 +1,10 @@
+package config
+
+import "strings"
+
+// TenantBaseDomain is the domain under which each school has a subdomain,
+// e.g. attendance.example.org for st-marys.attendance.example.org. Empty
+// disables resolving the school from the host.
+func TenantBaseDomain() string {
+	return strings.ToLower(strings.TrimPrefix(getEnv("TENANT_BASE_DOMAIN", ""), "."))
+}
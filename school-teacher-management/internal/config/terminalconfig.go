@@ -0,0 +1,17 @@
+package config
+
+import "time"
+
+// PINPolicy locks a teacher's PIN for LockoutPeriod after MaxAttempts
+// wrong entries in a row. An administrator's PIN reset also unlocks it.
+type PINPolicy struct {
+	MaxAttempts   int
+	LockoutPeriod time.Duration
+}
+
+func PIN() PINPolicy {
+	return PINPolicy{
+		MaxAttempts:   getInt("PIN_MAX_ATTEMPTS", 5),
+		LockoutPeriod: time.Duration(getInt("PIN_LOCKOUT_MINUTES", 15)) * time.Minute,
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+// Trace exporters, chosen with OTEL_TRACES_EXPORTER.
+const (
+	TraceExporterNone   = "none"
+	TraceExporterOTLP   = "otlp"
+	TraceExporterStdout = "stdout"
+)
+
+type TracingConfig struct {
+	// Exporter is otlp, stdout (for local use) or none. With none, requests
+	// are still traced, so logs and error responses carry trace IDs, but
+	// spans are not sent anywhere.
+	Exporter    string
+	ServiceName string
+}
+
+// Tracing reads OTEL_TRACES_EXPORTER and OTEL_SERVICE_NAME. The OTLP
+// exporter takes its endpoint, headers and protocol options from the
+// standard OTEL_EXPORTER_OTLP_* variables, and the sampler from
+// OTEL_TRACES_SAMPLER and OTEL_TRACES_SAMPLER_ARG.
+func Tracing() TracingConfig {
+	return TracingConfig{
+		Exporter:    getEnv("OTEL_TRACES_EXPORTER", TraceExporterNone),
+		ServiceName: getEnv("OTEL_SERVICE_NAME", "school-teacher-management"),
+	}
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"school-teacher-management/internal/model"
+)
+
+// Bus carries attendance events from the service layer to stream clients.
+type Bus interface {
+	Publish(ctx context.Context, event model.AttendanceEvent) error
+	Subscribe() (<-chan model.AttendanceEvent, func())
+}
+
+const subscriberBuffer = 64
+
+// MemoryBus fans events out to subscribers of this process only.
+type MemoryBus struct {
+	mu          sync.RWMutex
+	subscribers map[chan model.AttendanceEvent]struct{}
+}
+
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{subscribers: make(map[chan model.AttendanceEvent]struct{})}
+}
+
+func (b *MemoryBus) Publish(ctx context.Context, event model.AttendanceEvent) error {
+	b.broadcast(event)
+	return nil
+}
+
+func (b *MemoryBus) Subscribe() (<-chan model.AttendanceEvent, func()) {
+	ch := make(chan model.AttendanceEvent, subscriberBuffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// broadcast never blocks: a subscriber whose buffer is full misses the event
+// rather than stalling check-ins for everyone else.
+func (b *MemoryBus) broadcast(event model.AttendanceEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
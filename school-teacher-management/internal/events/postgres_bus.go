@@ -0,0 +1,84 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"school-teacher-management/internal/model"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+const channel = "attendance_events"
+
+// PostgresBus shares events between server instances using Postgres
+// LISTEN/NOTIFY. Every instance publishes through NOTIFY and delivers to its
+// own subscribers only what it receives back from LISTEN, so local and remote
+// events take the same path.
+type PostgresBus struct {
+	DB    *gorm.DB
+	dsn   string
+	local *MemoryBus
+}
+
+func NewPostgresBus(db *gorm.DB, dsn string) *PostgresBus {
+	return &PostgresBus{DB: db, dsn: dsn, local: NewMemoryBus()}
+}
+
+func (b *PostgresBus) Publish(ctx context.Context, event model.AttendanceEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return b.DB.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", channel, string(payload)).Error
+}
+
+func (b *PostgresBus) Subscribe() (<-chan model.AttendanceEvent, func()) {
+	return b.local.Subscribe()
+}
+
+// Listen receives notifications until ctx is cancelled, reconnecting after
+// connection failures.
+func (b *PostgresBus) Listen(ctx context.Context) {
+	for {
+		if err := b.listen(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "event bus listener", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+func (b *PostgresBus) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+channel); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var event model.AttendanceEvent
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			slog.WarnContext(ctx, "event bus: dropping malformed payload", "error", err)
+			continue
+		}
+
+		b.local.broadcast(event)
+	}
+}
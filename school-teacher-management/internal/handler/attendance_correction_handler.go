@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"school-teacher-management/internal/auth"
+	"school-teacher-management/internal/middleware"
+	"school-teacher-management/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateCorrection godoc
+// @Summary      Request an attendance correction
+// @Description  Teachers request corrections for themselves (missed punch, wrong time); admin and office staff may pass teacher_id. Nothing changes until the request is approved.
+// @Tags         corrections
+// @Accept       json
+// @Produce      json
+// @Param        correction  body      model.AttendanceCorrectionInput  true  "Correction request"
+// @Success      201         {object}  model.AttendanceCorrection
+// @Failure      400         {object}  model.Problem
+// @Failure      409         {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /attendance/corrections [post]
+func (h *AttendanceHandler) CreateCorrection(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+
+	var input model.AttendanceCorrectionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	if !claims.HasRole(auth.RoleAdmin, auth.RoleOffice) || input.TeacherID == 0 {
+		input.TeacherID = claims.TeacherID
+	}
+
+	correction, err := h.Service.RequestCorrection(c.Request.Context(), &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, correction)
+}
+
+// GetCorrections godoc
+// @Summary      List attendance corrections
+// @Description  Teachers see their own requests, heads of department their department's, admin and office staff everyone's.
+// @Tags         corrections
+// @Produce      json
+// @Param        teacherId  query     int     false  "Teacher ID"
+// @Param        status     query     string  false  "pending, approved or rejected"
+// @Success      200        {array}   model.AttendanceCorrection
+// @Failure      500        {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /attendance/corrections [get]
+func (h *AttendanceHandler) GetCorrections(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+
+	teacherID, _ := strconv.Atoi(c.Query("teacherId"))
+	department := ""
+
+	switch claims.Role {
+	case auth.RoleAdmin, auth.RoleOffice:
+	case auth.RoleHOD:
+		department = claims.Department
+	default:
+		teacherID = int(claims.TeacherID)
+	}
+
+	list, err := h.Service.GetCorrections(c.Request.Context(), uint(teacherID), department, c.Query("status"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// ApproveCorrection godoc
+// @Summary      Approve an attendance correction
+// @Description  Applies the requested punches and records before/after values in the attendance history.
+// @Tags         corrections
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                            true   "Correction ID"
+// @Param        decision  body      model.CorrectionDecisionInput  false  "Optional note"
+// @Success      200       {object}  model.AttendanceCorrection
+// @Failure      400       {object}  model.Problem
+// @Failure      403       {object}  model.Problem
+// @Failure      404       {object}  model.Problem
+// @Failure      409       {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /attendance/corrections/{id}/approve [post]
+func (h *AttendanceHandler) ApproveCorrection(c *gin.Context) {
+	h.decideCorrection(c, true)
+}
+
+// RejectCorrection godoc
+// @Summary      Reject an attendance correction
+// @Tags         corrections
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                            true   "Correction ID"
+// @Param        decision  body      model.CorrectionDecisionInput  false  "Optional note"
+// @Success      200       {object}  model.AttendanceCorrection
+// @Failure      400       {object}  model.Problem
+// @Failure      403       {object}  model.Problem
+// @Failure      404       {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /attendance/corrections/{id}/reject [post]
+func (h *AttendanceHandler) RejectCorrection(c *gin.Context) {
+	h.decideCorrection(c, false)
+}
+
+func (h *AttendanceHandler) decideCorrection(c *gin.Context, approve bool) {
+	claims, _ := middleware.CurrentClaims(c)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	var input model.CorrectionDecisionInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			bindError(c, err)
+			return
+		}
+	}
+
+	correction, err := h.Service.GetCorrection(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	allowed := claims.Role == auth.RoleAdmin ||
+		(claims.Role == auth.RoleHOD && claims.Department == correction.Teacher.Department)
+	if !allowed {
+		c.Error(middleware.ErrInsufficientPermissions)
+		return
+	}
+
+	if approve {
+		correction, err = h.Service.ApproveCorrection(c.Request.Context(), uint(id), input.Note)
+	} else {
+		correction, err = h.Service.RejectCorrection(c.Request.Context(), uint(id), input.Note)
+	}
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, correction)
+}
+
+// GetAttendanceHistory godoc
+// @Summary      Attendance change history
+// @Description  Before/after values of every approved correction and admin edit or delete of an attendance record.
+// @Tags         corrections
+// @Produce      json
+// @Param        id   path      int  true  "Attendance ID"
+// @Success      200  {array}   model.AttendanceChange
+// @Failure      500  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /attendance/{id}/history [get]
+func (h *AttendanceHandler) GetAttendanceHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	list, err := h.Service.GetAttendanceHistory(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
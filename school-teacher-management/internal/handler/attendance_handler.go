@@ -5,6 +5,9 @@ import (
 	"strconv"
 	"time"
 
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/auth"
+	"school-teacher-management/internal/middleware"
 	"school-teacher-management/internal/model"
 	"school-teacher-management/internal/service"
 
@@ -23,73 +26,83 @@ func NewAttendanceHandler(s *service.AttendanceService) *AttendanceHandler {
 
 // CreateAttendance godoc
 // @Summary      Create attendance record
-// @Description  Creates a new attendance entry (teacher_id and status are mandatory)
+// @Description  Checks the teacher of the bearer token in or out (status is mandatory). latitude, longitude and accuracy are optional and checked against the campus geofences, and the client IP against the campus networks; depending on the school's geofence and network policies a punch outside them is flagged or rejected. The device is identified by X-Device-ID; depending on DEVICE_POLICY a punch from a device the teacher is not approved to use is flagged or rejected.
 // @Tags         attendance
 // @Accept       json
 // @Produce      json
-// @Param        attendance  body      model.AttendanceRequest  true  "Attendance request"
+// @Param        attendance   body      model.AttendanceRequest  true   "Attendance request"
+// @Param        X-Device-ID  header    string                   false  "Device identifier"
+// @Param        Idempotency-Key  header  string               false  "Key making retries of the request safe"
 // @Success      201         {object}  map[string]string
-// @Failure      400         {object}  map[string]string
-// @Failure      500         {object}  map[string]string
+// @Failure      400         {object}  model.Problem
+// @Failure      401         {object}  model.Problem
+// @Failure      403         {object}  model.Problem
+// @Failure      409         {object}  model.Problem
+// @Failure      422         {object}  model.Problem
+// @Failure      500         {object}  model.Problem
+// @Security     BearerAuth
 // @Router       /attendance [post]
 func (h *AttendanceHandler) CreateAttendance(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+	if claims.TeacherID == 0 {
+		c.Error(errTeachersOnly)
+		return
+	}
+
 	var input model.AttendanceRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		bindError(c, err)
 		return
 	}
 
 	metrics.AttendanceCreatedTotal.Inc()
 
-	if err := h.Service.MarkAttendance(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+	input.TeacherID = claims.TeacherID
+	input.ClientIP = c.ClientIP()
+	input.DeviceIdentifier = c.GetHeader(DeviceIDHeader)
+	if err := h.Service.MarkAttendance(c.Request.Context(), &input); err != nil {
+		c.Error(err)
 		return
 	}
 
-	switch input.Status {
-	case "checkIn":
-		c.JSON(http.StatusCreated, gin.H{
-			"message": "You have checked in successfully",
-		})
-	case "checkOut":
-		c.JSON(http.StatusCreated, gin.H{
-			"message": "You have checked out successfully",
-		})
-	default:
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid status value",
-		})
+	message := "You have checked in successfully"
+	if input.Status == model.PunchCheckOut {
+		message = "You have checked out successfully"
 	}
+	c.JSON(http.StatusCreated, gin.H{"message": message})
 }
 
 // GetAttendances godoc
 // @Summary      Get all attendance records
 // @Tags         attendance
 // @Produce      json
-// @Success      200  {array}   model.Attendance
+// @Param        deviceId  query     int  false  "Only punches from this device"
+// @Param        campusId  query     int  false  "Only punches at this campus"
+// @Success      200       {array}   model.AttendanceDTO
 // @Router       /attendance [get]
 func (h *AttendanceHandler) GetAttendances(c *gin.Context) {
-	list, err := h.Service.GetAttendances()
+	deviceID, _ := strconv.Atoi(c.Query("deviceId"))
+	campusID, _ := strconv.Atoi(c.Query("campusId"))
+
+	list, err := h.Service.GetAttendances(c.Request.Context(), uint(deviceID), uint(campusID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		c.Error(err)
 		return
 	}
 
 	result := []model.AttendanceDTO{}
 	for _, att := range list {
 		dto := model.AttendanceDTO{
-			TeacherID:   att.Teacher.ID,
-			TeacherName: att.Teacher.FirstName + " " + att.Teacher.LastName,
-			CheckIn:     att.CheckIn,
-			CheckOut:    att.CheckOut,
-			Date:        att.Date.Format("02-01-2006"),
+			TeacherID:        att.Teacher.ID,
+			TeacherName:      att.Teacher.FirstName + " " + att.Teacher.LastName,
+			CheckIn:          att.CheckIn,
+			CheckOut:         att.CheckOut,
+			Date:             att.Date.Format("02-01-2006"),
+			CheckInDeviceID:  att.CheckInDeviceID,
+			CheckOutDeviceID: att.CheckOutDeviceID,
+			CheckInCampusID:  att.CheckInCampusID,
+			CheckOutCampusID: att.CheckOutCampusID,
 		}
 		result = append(result, dto)
 	}
@@ -98,92 +111,162 @@ func (h *AttendanceHandler) GetAttendances(c *gin.Context) {
 
 // GetAttendanceByID godoc
 // @Summary      Get attendance by ID
+// @Description  The record includes the reported positions, client IPs and devices of its punches, so teachers may only read their own; admin and office staff read everyone's.
 // @Tags         attendance
 // @Param        id   path      int  true  "Attendance ID"
 // @Produce      json
 // @Success      200  {object}  model.Attendance
-// @Failure      404  {object}  map[string]string
+// @Failure      401  {object}  model.Problem
+// @Failure      403  {object}  model.Problem
+// @Failure      404  {object}  model.Problem
+// @Security     BearerAuth
 // @Router       /attendance/{id} [get]
 func (h *AttendanceHandler) GetAttendanceByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid ID",
-		})
+		c.Error(errInvalidID)
 		return
 	}
 
-	att, err := h.Service.GetAttendance(uint(id))
+	att, err := h.Service.GetAttendance(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, map[string]string{
-			"error": "Attendance not found",
-		})
+		c.Error(err)
+		return
+	}
+
+	claims, _ := middleware.CurrentClaims(c)
+	if !claims.HasRole(auth.RoleAdmin, auth.RoleOffice) && claims.TeacherID != att.TeacherID {
+		c.Error(middleware.ErrInsufficientPermissions)
 		return
 	}
 
+	setETag(c, att.Version)
 	c.JSON(http.StatusOK, att)
 }
 
 // UpdateAttendance godoc
 // @Summary      Update attendance
+// @Description  Direct edit, restricted to administrators and recorded in the attendance history. Teachers use correction requests instead. The status may only move along the allowed transitions (for example a worked day cannot become a holiday), and days not worked cannot have check-in or check-out times. Only the fields that changed are written; with If-Match the edit is refused with 412 unless the record still has that version.
 // @Tags         attendance
 // @Accept       json
 // @Produce      json
-// @Param        id          path      int               true  "Attendance ID"
-// @Param        attendance  body      model.Attendance  true  "Updated attendance"
+// @Param        id          path      int               true   "Attendance ID"
+// @Param        If-Match    header    string            false  "ETag of the record as read"
+// @Param        attendance  body      model.Attendance  true   "Updated attendance"
 // @Success      200         {object}  model.Attendance
-// @Failure      400         {object}  map[string]string
-// @Failure      500         {object}  map[string]string
+// @Failure      400         {object}  model.Problem
+// @Failure      404         {object}  model.Problem
+// @Failure      409         {object}  model.Problem
+// @Failure      412         {object}  model.Problem
+// @Failure      422         {object}  model.Problem
+// @Failure      500         {object}  model.Problem
+// @Security     BearerAuth
 // @Router       /attendance/{id} [put]
 func (h *AttendanceHandler) UpdateAttendance(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid ID",
-		})
+		c.Error(errInvalidID)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
 	var input model.Attendance
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, map[string]string{
-			"error": err.Error(),
-		})
+		bindError(c, err)
 		return
 	}
 
 	input.ID = uint(id)
 
-	if err := h.Service.UpdateAttendance(&input); err != nil {
-		c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+	if err := h.Service.UpdateAttendance(c.Request.Context(), &input, version); err != nil {
+		c.Error(err)
 		return
 	}
 
+	setETag(c, input.Version)
+	c.JSON(http.StatusOK, input)
+}
+
+// PatchAttendance godoc
+// @Summary      Partially update attendance
+// @Description  Applies a JSON Merge Patch (application/merge-patch+json) or a JSON Patch (application/json-patch+json) to an attendance record, under the same rules as the full update. The edit is refused with 412 if the record changes meanwhile or, with If-Match, no longer has that version.
+// @Tags         attendance
+// @Accept       application/merge-patch+json,application/json-patch+json
+// @Produce      json
+// @Param        id        path      int     true   "Attendance ID"
+// @Param        If-Match  header    string  false  "ETag of the record as read"
+// @Param        patch     body      object  true   "Merge patch or JSON Patch"
+// @Success      200       {object}  model.Attendance
+// @Failure      400       {object}  model.Problem
+// @Failure      404       {object}  model.Problem
+// @Failure      409       {object}  model.Problem
+// @Failure      412       {object}  model.Problem
+// @Failure      415       {object}  model.Problem
+// @Failure      422       {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /attendance/{id} [patch]
+func (h *AttendanceHandler) PatchAttendance(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	current, err := h.Service.GetAttendance(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if version == 0 {
+		version = current.Version
+	}
+
+	var input model.Attendance
+	if !applyPatch(c, current, &input) {
+		return
+	}
+
+	input.ID = current.ID
+
+	if err := h.Service.UpdateAttendance(c.Request.Context(), &input, version); err != nil {
+		c.Error(err)
+		return
+	}
+
+	setETag(c, input.Version)
 	c.JSON(http.StatusOK, input)
 }
 
 // DeleteAttendance godoc
 // @Summary      Delete attendance
+// @Description  Restricted to administrators; the deleted values are kept in the attendance history.
 // @Tags         attendance
 // @Param        id   path  int  true  "Attendance ID"
 // @Success      204  "No Content"
-// @Failure      404  {object}  map[string]string
+// @Failure      404  {object}  model.Problem
+// @Failure      409  {object}  model.Problem
+// @Security     BearerAuth
 // @Router       /attendance/{id} [delete]
 func (h *AttendanceHandler) DeleteAttendance(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid ID",
-		})
+		c.Error(errInvalidID)
 		return
 	}
 
-	if err := h.Service.DeleteAttendance(uint(id)); err != nil {
-		c.JSON(http.StatusNotFound, map[string]string{
-			"error": "Attendance not found",
-		})
+	if err := h.Service.DeleteAttendance(c.Request.Context(), uint(id)); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -198,17 +281,18 @@ func (h *AttendanceHandler) DeleteAttendance(c *gin.Context) {
 // @Param month query int false "Month (1-12), default current month"
 // @Param year query int false "Year, default current year"
 // @Success 200 {object} model.AttendanceResponse
+// @Failure 400 {object} model.Problem
 // @Router /attendanceByDate [get]
 func (h *AttendanceHandler) GetAttendanceByDate(c *gin.Context) {
 	teacherIDStr := c.Query("teacherId")
 	if teacherIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "teacherId is required"})
+		c.Error(apperror.Field("teacherId", "required", "is required"))
 		return
 	}
 
 	teacherID, err := strconv.ParseUint(teacherIDStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid teacherId"})
+		c.Error(apperror.Field("teacherId", "numeric", "must be a number"))
 		return
 	}
 
@@ -219,9 +303,9 @@ func (h *AttendanceHandler) GetAttendanceByDate(c *gin.Context) {
 	monthInt, _ := strconv.Atoi(monthStr)
 	yearInt, _ := strconv.Atoi(yearStr)
 
-	resp, err := h.Service.GetAttendanceByTeacherMonth(uint(teacherID), time.Month(monthInt), yearInt)
+	resp, err := h.Service.GetAttendanceByTeacherMonth(c.Request.Context(), uint(teacherID), time.Month(monthInt), yearInt)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -235,7 +319,9 @@ func (h *AttendanceHandler) GetAttendanceByDate(c *gin.Context) {
 // @Param date  query int false "Day of month (1-31)"
 // @Param month query int false "Month (1-12)"
 // @Param year  query int false "Year (YYYY)"
+// @Param campusId query int false "Only punches at this campus"
 // @Success 200 {object} model.AttendanceResponse
+// @Failure 400 {object} model.Problem
 // @Router /attendanceByFilterDate [get]
 func (h *AttendanceHandler) GetAttendanceByFilterDate(c *gin.Context) {
 
@@ -247,24 +333,24 @@ func (h *AttendanceHandler) GetAttendanceByFilterDate(c *gin.Context) {
 
 	day, err := strconv.Atoi(dayStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date"})
+		c.Error(apperror.Field("date", "numeric", "must be a number"))
 		return
 	}
 
 	monthInt, err := strconv.Atoi(monthStr)
 	if monthInt < 1 || monthInt > 12 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "month must be 1-12"})
+		c.Error(apperror.Field("month", "range", "must be 1-12"))
 		return
 	}
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid month"})
+		c.Error(apperror.Field("month", "numeric", "must be a number"))
 		return
 	}
 
 	year, err := strconv.Atoi(yearStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid year"})
+		c.Error(apperror.Field("year", "numeric", "must be a number"))
 		return
 	}
 
@@ -276,9 +362,11 @@ func (h *AttendanceHandler) GetAttendanceByFilterDate(c *gin.Context) {
 		time.Local,
 	)
 
-	resp, err := h.Service.GetAttendanceByMonthAndDate(date)
+	campusID, _ := strconv.Atoi(c.Query("campusId"))
+
+	resp, err := h.Service.GetAttendanceByMonthAndDate(c.Request.Context(), date, uint(campusID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
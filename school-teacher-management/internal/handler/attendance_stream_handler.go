@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/auth"
+	"school-teacher-management/internal/events"
+	"school-teacher-management/internal/middleware"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/requestctx"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const streamKeepAlive = 30 * time.Second
+
+type AttendanceStreamHandler struct {
+	Events   events.Bus
+	upgrader websocket.Upgrader
+	// closing is closed on shutdown to end the open streams.
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+func NewAttendanceStreamHandler(bus events.Bus) *AttendanceStreamHandler {
+	return &AttendanceStreamHandler{
+		Events: bus,
+		upgrader: websocket.Upgrader{
+			// Origins are already open through CORS; the bearer token is what
+			// authorizes the connection.
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		closing: make(chan struct{}),
+	}
+}
+
+// Close ends the open streams. Streams never finish on their own, so the
+// server calls it when it starts shutting down.
+func (h *AttendanceStreamHandler) Close() {
+	h.closeOnce.Do(func() { close(h.closing) })
+}
+
+// StreamSSE godoc
+// @Summary      Stream attendance events (SSE)
+// @Description  Pushes check-in, check-out, leave and correction events as Server-Sent Events. Heads of department only receive their own department; teachers only their own events.
+// @Tags         attendance
+// @Produce      text/event-stream
+// @Param        department    query  string  false  "Comma separated departments to include"
+// @Param        access_token  query  string  false  "Bearer token, for clients that cannot set headers"
+// @Success      200  {object}  model.AttendanceEvent
+// @Failure      401  {object}  model.Problem
+// @Failure      403  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /attendance/stream [get]
+func (h *AttendanceStreamHandler) StreamSSE(c *gin.Context) {
+	allow, ok := h.eventFilter(c)
+	if !ok {
+		return
+	}
+
+	ch, unsubscribe := h.Events.Subscribe()
+	defer unsubscribe()
+
+	ticker := time.NewTicker(streamKeepAlive)
+	defer ticker.Stop()
+
+	// The stream outlives the server's write timeout.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-h.closing:
+			return false
+		case event, open := <-ch:
+			if !open {
+				return false
+			}
+			if allow(event) {
+				c.SSEvent(event.Type, event)
+			}
+			return true
+		case <-ticker.C:
+			c.SSEvent("ping", gin.H{"time": time.Now()})
+			return true
+		}
+	})
+}
+
+// StreamWebSocket godoc
+// @Summary      Stream attendance events (WebSocket)
+// @Description  Same events and filtering as /attendance/stream, delivered as JSON WebSocket messages.
+// @Tags         attendance
+// @Param        department    query  string  false  "Comma separated departments to include"
+// @Param        access_token  query  string  false  "Bearer token, for clients that cannot set headers"
+// @Success      101  {object}  model.AttendanceEvent
+// @Failure      401  {object}  model.Problem
+// @Failure      403  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /attendance/ws [get]
+func (h *AttendanceStreamHandler) StreamWebSocket(c *gin.Context) {
+	allow, ok := h.eventFilter(c)
+	if !ok {
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade has already written the error response.
+		return
+	}
+	defer conn.Close()
+
+	// A hijacked connection keeps the deadlines the server set on it.
+	if err := conn.NetConn().SetDeadline(time.Time{}); err != nil {
+		return
+	}
+
+	ch, unsubscribe := h.Events.Subscribe()
+	defer unsubscribe()
+
+	// Clients never send anything; reading only detects the close.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(streamKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-h.closing:
+			deadline := time.Now().Add(10 * time.Second)
+			message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+			conn.WriteControl(websocket.CloseMessage, message, deadline)
+			return
+		case event, open := <-ch:
+			if !open {
+				return
+			}
+			if !allow(event) {
+				continue
+			}
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			deadline := time.Now().Add(10 * time.Second)
+			if err := conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// eventFilter builds the per-connection filter from the caller's claims, the
+// school the request is scoped to and the department query parameter. Only
+// trust-level administrators outside a school's subdomain receive the
+// events of every school. It reports the error itself and
+// returns false when the request must not be served.
+func (h *AttendanceStreamHandler) eventFilter(c *gin.Context) (func(model.AttendanceEvent) bool, bool) {
+	claims, ok := middleware.CurrentClaims(c)
+	if !ok {
+		c.Error(middleware.ErrMissingToken)
+		return nil, false
+	}
+
+	departments := map[string]bool{}
+	for _, d := range strings.Split(c.Query("department"), ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			departments[d] = true
+		}
+	}
+
+	if claims.Role == auth.RoleHOD {
+		for d := range departments {
+			if d != claims.Department {
+				c.Error(apperror.Forbidden("department_not_permitted", "department not permitted: "+d))
+				return nil, false
+			}
+		}
+	}
+
+	schoolID, scoped := requestctx.School(c.Request.Context())
+
+	return func(event model.AttendanceEvent) bool {
+		if scoped && event.SchoolID != schoolID {
+			return false
+		}
+		if len(departments) > 0 && !departments[event.Department] {
+			return false
+		}
+		return claims.CanViewTeacher(event.TeacherID, event.Department)
+	}, true
+}
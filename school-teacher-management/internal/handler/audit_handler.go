@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	Service *service.AuditService
+}
+
+func NewAuditHandler(s *service.AuditService) *AuditHandler {
+	return &AuditHandler{Service: s}
+}
+
+// GetAuditLogs godoc
+// @Summary      Query the audit log
+// @Description  Newest first. Filter by entity (teacher, attendance) and ID, or by actor.
+// @Tags         audit
+// @Produce      json
+// @Param        entity_type  query     string  false  "teacher or attendance"
+// @Param        entity_id    query     int     false  "Entity ID"
+// @Param        actor_id     query     int     false  "Actor teacher ID"
+// @Param        actor_role   query     string  false  "Actor role"
+// @Param        from         query     string  false  "From (RFC3339)"
+// @Param        to           query     string  false  "To, exclusive (RFC3339)"
+// @Param        limit        query     int     false  "Max entries (default 100, max 1000)"
+// @Success      200          {array}   model.AuditLog
+// @Failure      400          {object}  model.Problem
+// @Failure      500          {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /audit [get]
+func (h *AuditHandler) GetAuditLogs(c *gin.Context) {
+	filter := model.AuditFilter{
+		EntityType: c.Query("entity_type"),
+		ActorRole:  c.Query("actor_role"),
+	}
+
+	for param, target := range map[string]*uint{
+		"entity_id": &filter.EntityID,
+		"actor_id":  &filter.ActorID,
+	} {
+		if v := c.Query(param); v != "" {
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				c.Error(apperror.Field(param, "numeric", "must be a number"))
+				return
+			}
+			*target = uint(n)
+		}
+	}
+
+	for param, target := range map[string]**time.Time{
+		"from": &filter.From,
+		"to":   &filter.To,
+	} {
+		if v := c.Query(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				c.Error(apperror.Field(param, "format", "must be an RFC 3339 time"))
+				return
+			}
+			*target = &t
+		}
+	}
+
+	filter.Limit, _ = strconv.Atoi(c.Query("limit"))
+
+	list, err := h.Service.Find(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// VerifyAuditLog godoc
+// @Summary      Verify the audit hash chain
+// @Description  Recomputes every entry's hash and link; reports the first entry that was altered, removed or reordered. The chain spans every school, so only trust-level administrators without a school subdomain may verify it.
+// @Tags         audit
+// @Produce      json
+// @Success      200  {object}  model.AuditVerification
+// @Failure      403  {object}  model.Problem
+// @Failure      500  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /audit/verify [get]
+func (h *AuditHandler) VerifyAuditLog(c *gin.Context) {
+	result, err := h.Service.Verify(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
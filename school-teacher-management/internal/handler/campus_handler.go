@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CampusHandler struct {
+	Service *service.CampusService
+}
+
+func NewCampusHandler(s *service.CampusService) *CampusHandler {
+	return &CampusHandler{Service: s}
+}
+
+// CreateCampus godoc
+// @Summary      Add a campus
+// @Description  Adds a campus to the school of the request. Working hours left empty follow the school's.
+// @Tags         campuses
+// @Accept       json
+// @Produce      json
+// @Param        campus  body      model.CampusInput  true  "Campus"
+// @Success      201     {object}  model.Campus
+// @Failure      400     {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /campuses [post]
+func (h *CampusHandler) CreateCampus(c *gin.Context) {
+	var input model.CampusInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	campus, err := h.Service.CreateCampus(c.Request.Context(), &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, campus)
+}
+
+// GetCampuses godoc
+// @Summary      List campuses
+// @Tags         campuses
+// @Produce      json
+// @Success      200  {array}   model.Campus
+// @Failure      500  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /campuses [get]
+func (h *CampusHandler) GetCampuses(c *gin.Context) {
+	list, err := h.Service.GetCampuses(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetCampus godoc
+// @Summary      Get a campus
+// @Tags         campuses
+// @Produce      json
+// @Param        id   path      int  true  "Campus ID"
+// @Success      200  {object}  model.Campus
+// @Failure      404  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /campuses/{id} [get]
+func (h *CampusHandler) GetCampus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	campus, err := h.Service.GetCampus(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, campus)
+}
+
+// UpdateCampus godoc
+// @Summary      Update a campus
+// @Description  Name, address, working hours and whether punches may still be recorded at it.
+// @Tags         campuses
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                true  "Campus ID"
+// @Param        campus  body      model.CampusInput  true  "Campus"
+// @Success      200     {object}  model.Campus
+// @Failure      400     {object}  model.Problem
+// @Failure      404     {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /campuses/{id} [put]
+func (h *CampusHandler) UpdateCampus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	var input model.CampusInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	campus, err := h.Service.UpdateCampus(c.Request.Context(), uint(id), &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, campus)
+}
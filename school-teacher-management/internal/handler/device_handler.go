@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/auth"
+	"school-teacher-management/internal/middleware"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeviceIDHeader carries the identifier the teacher's app generated for
+// the device it runs on.
+const DeviceIDHeader = "X-Device-ID"
+
+type DeviceHandler struct {
+	Service *service.DeviceService
+}
+
+func NewDeviceHandler(s *service.DeviceService) *DeviceHandler {
+	return &DeviceHandler{Service: s}
+}
+
+// RegisterDevice godoc
+// @Summary      Register a device
+// @Description  Teachers register their own devices, which need approval before punches from them are trusted. Administrators may register shared devices, usable by every teacher.
+// @Tags         devices
+// @Accept       json
+// @Produce      json
+// @Param        device  body      model.DeviceInput  true  "Device"
+// @Success      201     {object}  model.Device
+// @Failure      400     {object}  model.Problem
+// @Failure      500     {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /devices [post]
+func (h *DeviceHandler) RegisterDevice(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+
+	var input model.DeviceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	if input.Shared && !claims.HasRole(auth.RoleAdmin) {
+		c.Error(apperror.Forbidden("shared_device_admin_only", "only administrators can register shared devices"))
+		return
+	}
+	if !input.Shared && claims.TeacherID == 0 {
+		c.Error(apperror.Forbidden("teachers_only", "only teachers can register their own devices"))
+		return
+	}
+
+	device, err := h.Service.RegisterDevice(c.Request.Context(), claims.TeacherID, &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, device)
+}
+
+// GetDevices godoc
+// @Summary      List devices
+// @Description  Admin and office staff see every device of the school, others the devices they own or are bound to.
+// @Tags         devices
+// @Produce      json
+// @Success      200  {array}   model.Device
+// @Failure      500  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /devices [get]
+func (h *DeviceHandler) GetDevices(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+
+	teacherID := claims.TeacherID
+	if claims.HasRole(auth.RoleAdmin, auth.RoleOffice) {
+		teacherID = 0
+	}
+
+	list, err := h.Service.GetDevices(c.Request.Context(), teacherID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// DeactivateDevice godoc
+// @Summary      Deactivate a device
+// @Description  Punches from the device are treated as unapproved from now on.
+// @Tags         devices
+// @Param        id   path  int  true  "Device ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /devices/{id} [delete]
+func (h *DeviceHandler) DeactivateDevice(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	if err := h.Service.DeactivateDevice(c.Request.Context(), uint(id)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetBindings godoc
+// @Summary      List device bindings
+// @Description  Teachers see their own bindings, heads of department their department's, admin and office staff everyone's.
+// @Tags         devices
+// @Produce      json
+// @Param        teacherId  query     int     false  "Teacher ID"
+// @Param        status     query     string  false  "pending, approved, rejected or revoked"
+// @Success      200        {array}   model.DeviceBinding
+// @Failure      500        {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /devices/bindings [get]
+func (h *DeviceHandler) GetBindings(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+
+	teacherID, _ := strconv.Atoi(c.Query("teacherId"))
+	department := ""
+
+	switch claims.Role {
+	case auth.RoleAdmin, auth.RoleOffice:
+	case auth.RoleHOD:
+		department = claims.Department
+	default:
+		teacherID = int(claims.TeacherID)
+	}
+
+	list, err := h.Service.GetBindings(c.Request.Context(), uint(teacherID), department, c.Query("status"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// ApproveBinding godoc
+// @Summary      Approve a device binding
+// @Tags         devices
+// @Produce      json
+// @Param        id   path      int  true  "Binding ID"
+// @Success      200  {object}  model.DeviceBinding
+// @Failure      400  {object}  model.Problem
+// @Failure      403  {object}  model.Problem
+// @Failure      404  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /devices/bindings/{id}/approve [post]
+func (h *DeviceHandler) ApproveBinding(c *gin.Context) {
+	h.decide(c, true)
+}
+
+// RejectBinding godoc
+// @Summary      Reject or revoke a device binding
+// @Tags         devices
+// @Produce      json
+// @Param        id   path      int  true  "Binding ID"
+// @Success      200  {object}  model.DeviceBinding
+// @Failure      400  {object}  model.Problem
+// @Failure      403  {object}  model.Problem
+// @Failure      404  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /devices/bindings/{id}/reject [post]
+func (h *DeviceHandler) RejectBinding(c *gin.Context) {
+	h.decide(c, false)
+}
+
+func (h *DeviceHandler) decide(c *gin.Context, approve bool) {
+	claims, _ := middleware.CurrentClaims(c)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	binding, err := h.Service.GetBinding(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	allowed := claims.Role == auth.RoleAdmin ||
+		(claims.Role == auth.RoleHOD && claims.Department == binding.Teacher.Department)
+	if !allowed {
+		c.Error(middleware.ErrInsufficientPermissions)
+		return
+	}
+
+	binding, err = h.Service.DecideBinding(c.Request.Context(), uint(id), approve)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, binding)
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"school-teacher-management/internal/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handlers report failures with c.Error and return; ErrorMiddleware writes
+// the problem response.
+
+var (
+	errInvalidID    = apperror.Validation("invalid_id", "Invalid ID")
+	errTeachersOnly = apperror.Forbidden("teachers_only", "only teachers can check in")
+)
+
+// bindError reports a request body or query that could not be bound; the
+// problem lists the invalid fields.
+func bindError(c *gin.Context, err error) {
+	c.Error(err).SetType(gin.ErrorTypeBind)
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GeofenceHandler struct {
+	Service *service.GeofenceService
+}
+
+func NewGeofenceHandler(s *service.GeofenceService) *GeofenceHandler {
+	return &GeofenceHandler{Service: s}
+}
+
+// CreateGeofence godoc
+// @Summary      Add a campus geofence
+// @Description  A radius fence needs center and radius_meters, a polygon fence at least three points. Punches are checked against every active fence of the school.
+// @Tags         geofences
+// @Accept       json
+// @Produce      json
+// @Param        geofence  body      model.GeofenceInput  true  "Geofence"
+// @Success      201       {object}  model.Geofence
+// @Failure      400       {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /geofences [post]
+func (h *GeofenceHandler) CreateGeofence(c *gin.Context) {
+	var input model.GeofenceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	fence, err := h.Service.CreateGeofence(c.Request.Context(), &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, fence)
+}
+
+// GetGeofences godoc
+// @Summary      List campus geofences
+// @Tags         geofences
+// @Produce      json
+// @Success      200  {array}   model.Geofence
+// @Failure      500  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /geofences [get]
+func (h *GeofenceHandler) GetGeofences(c *gin.Context) {
+	list, err := h.Service.GetGeofences(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// UpdateGeofence godoc
+// @Summary      Update a campus geofence
+// @Tags         geofences
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                  true  "Geofence ID"
+// @Param        geofence  body      model.GeofenceInput  true  "Geofence"
+// @Success      200       {object}  model.Geofence
+// @Failure      400       {object}  model.Problem
+// @Failure      404       {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /geofences/{id} [put]
+func (h *GeofenceHandler) UpdateGeofence(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	var input model.GeofenceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	fence, err := h.Service.UpdateGeofence(c.Request.Context(), uint(id), &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fence)
+}
+
+// DeleteGeofence godoc
+// @Summary      Remove a campus geofence
+// @Tags         geofences
+// @Param        id   path  int  true  "Geofence ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /geofences/{id} [delete]
+func (h *GeofenceHandler) DeleteGeofence(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	if err := h.Service.DeleteGeofence(c.Request.Context(), uint(id)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
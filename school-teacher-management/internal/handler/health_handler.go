@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net/http"
+
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler serves the load balancer's probes. Like /metrics they live
+// outside the API and are not in the API documentation.
+type HealthHandler struct {
+	Service *service.HealthService
+}
+
+func NewHealthHandler(s *service.HealthService) *HealthHandler {
+	return &HealthHandler{Service: s}
+}
+
+// Healthz is the liveness probe: 200 while the process serves requests.
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Service.Live())
+}
+
+// Readyz is the readiness probe: 200 when the database, the migrations and
+// the background jobs are healthy, else 503 with the failing checks.
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.Service.Ready(c.Request.Context())
+	status := http.StatusOK
+	if report.Status != model.HealthOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HolidayHandler struct {
+	Service *service.HolidayService
+}
+
+func NewHolidayHandler(s *service.HolidayService) *HolidayHandler {
+	return &HolidayHandler{Service: s}
+}
+
+// CreateHoliday godoc
+// @Summary      Add a holiday
+// @Description  Holidays are not working days for payroll, and work on them is overtime.
+// @Tags         holidays
+// @Accept       json
+// @Produce      json
+// @Param        holiday  body      model.HolidayInput  true  "Holiday"
+// @Success      201      {object}  model.Holiday
+// @Failure      400      {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /holidays [post]
+func (h *HolidayHandler) CreateHoliday(c *gin.Context) {
+	var input model.HolidayInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	holiday, err := h.Service.CreateHoliday(c.Request.Context(), &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, holiday)
+}
+
+// GetHolidays godoc
+// @Summary      List holidays
+// @Tags         holidays
+// @Produce      json
+// @Param        year      query     int  false  "Year"
+// @Param        campusId  query     int  false  "Only holidays closing this campus"
+// @Success      200   {array}   model.Holiday
+// @Failure      500   {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /holidays [get]
+func (h *HolidayHandler) GetHolidays(c *gin.Context) {
+	year, _ := strconv.Atoi(c.Query("year"))
+	campusID, _ := strconv.Atoi(c.Query("campusId"))
+
+	list, err := h.Service.GetHolidays(c.Request.Context(), year, uint(campusID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// DeleteHoliday godoc
+// @Summary      Remove a holiday
+// @Tags         holidays
+// @Param        id   path  int  true  "Holiday ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /holidays/{id} [delete]
+func (h *HolidayHandler) DeleteHoliday(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	if err := h.Service.DeleteHoliday(c.Request.Context(), uint(id)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/auth"
+	"school-teacher-management/internal/middleware"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IncidentHandler struct {
+	Service *service.AnomalyService
+}
+
+func NewIncidentHandler(s *service.AnomalyService) *IncidentHandler {
+	return &IncidentHandler{Service: s}
+}
+
+// GetIncidents godoc
+// @Summary      List flagged incidents
+// @Description  Suspicious attendance patterns found by the anomaly scan: shared_device and shared_ip (different teachers punching from one device or address within seconds), repeated_time (check-ins at the identical time on many days), impossible_travel and holiday_check_in. Heads of department see their department's incidents.
+// @Tags         incidents
+// @Produce      json
+// @Param        teacherId  query     int     false  "Teacher ID"
+// @Param        kind       query     string  false  "Incident kind"
+// @Param        status     query     string  false  "open, resolved or dismissed"
+// @Param        from       query     string  false  "From date (YYYY-MM-DD)"
+// @Param        to         query     string  false  "To date, inclusive (YYYY-MM-DD)"
+// @Success      200        {array}   model.Incident
+// @Failure      400        {object}  model.Problem
+// @Failure      500        {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /incidents [get]
+func (h *IncidentHandler) GetIncidents(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+
+	teacherID, _ := strconv.Atoi(c.Query("teacherId"))
+	filter := model.IncidentFilter{
+		TeacherID: uint(teacherID),
+		Kind:      c.Query("kind"),
+		Status:    c.Query("status"),
+	}
+	if claims.Role == auth.RoleHOD {
+		filter.Department = claims.Department
+	}
+
+	for param, target := range map[string]**time.Time{
+		"from": &filter.From,
+		"to":   &filter.To,
+	} {
+		if v := c.Query(param); v != "" {
+			t, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				c.Error(apperror.Field(param, "format", "must be YYYY-MM-DD"))
+				return
+			}
+			*target = &t
+		}
+	}
+
+	list, err := h.Service.GetIncidents(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// ScanIncidents godoc
+// @Summary      Run the anomaly scan now
+// @Description  The scan also runs in the background every ANOMALY_SCAN_INTERVAL_MINUTES over the last ANOMALY_LOOKBACK_DAYS days. Patterns already flagged are not flagged again.
+// @Tags         incidents
+// @Produce      json
+// @Param        from  query     string  false  "From date (YYYY-MM-DD), default the start of the lookback"
+// @Param        to    query     string  false  "To date, inclusive (YYYY-MM-DD), default today"
+// @Success      200   {object}  model.IncidentScanResult
+// @Failure      400   {object}  model.Problem
+// @Failure      500   {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /incidents/scan [post]
+func (h *IncidentHandler) ScanIncidents(c *gin.Context) {
+	end := time.Now().Truncate(24 * time.Hour)
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			c.Error(apperror.Field("to", "format", "must be YYYY-MM-DD"))
+			return
+		}
+		end = t
+	}
+
+	start := end.AddDate(0, 0, -h.Service.Policy.Lookback)
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			c.Error(apperror.Field("from", "format", "must be YYYY-MM-DD"))
+			return
+		}
+		start = t
+	}
+
+	if end.Before(start) {
+		c.Error(apperror.Validation("invalid_date_range", "to must not be before from"))
+		return
+	}
+
+	flagged, err := h.Service.Scan(c.Request.Context(), start, end)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, model.IncidentScanResult{
+		From:    start.Format("2006-01-02"),
+		To:      end.Format("2006-01-02"),
+		Flagged: flagged,
+	})
+}
+
+// ResolveIncident godoc
+// @Summary      Resolve an incident
+// @Description  The suspicion was confirmed and dealt with.
+// @Tags         incidents
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                        true   "Incident ID"
+// @Param        review  body      model.IncidentReviewInput  false  "Optional note"
+// @Success      200     {object}  model.Incident
+// @Failure      400     {object}  model.Problem
+// @Failure      403     {object}  model.Problem
+// @Failure      404     {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /incidents/{id}/resolve [post]
+func (h *IncidentHandler) ResolveIncident(c *gin.Context) {
+	h.review(c, model.IncidentResolved)
+}
+
+// DismissIncident godoc
+// @Summary      Dismiss an incident
+// @Description  The incident was a false positive.
+// @Tags         incidents
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                        true   "Incident ID"
+// @Param        review  body      model.IncidentReviewInput  false  "Optional note"
+// @Success      200     {object}  model.Incident
+// @Failure      400     {object}  model.Problem
+// @Failure      403     {object}  model.Problem
+// @Failure      404     {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /incidents/{id}/dismiss [post]
+func (h *IncidentHandler) DismissIncident(c *gin.Context) {
+	h.review(c, model.IncidentDismissed)
+}
+
+func (h *IncidentHandler) review(c *gin.Context, status string) {
+	claims, _ := middleware.CurrentClaims(c)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	var input model.IncidentReviewInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			bindError(c, err)
+			return
+		}
+	}
+
+	incident, err := h.Service.GetIncident(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	allowed := claims.Role == auth.RoleAdmin ||
+		(claims.Role == auth.RoleHOD && claims.Department == incident.Teacher.Department)
+	if !allowed {
+		c.Error(middleware.ErrInsufficientPermissions)
+		return
+	}
+
+	incident, err = h.Service.ReviewIncident(c.Request.Context(), uint(id), status, input.Note)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/middleware"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KioskKeyHeader carries the key a kiosk was given at registration.
+const KioskKeyHeader = "X-Kiosk-Key"
+
+type KioskHandler struct {
+	Service    *service.KioskService
+	Attendance *service.AttendanceService
+}
+
+func NewKioskHandler(s *service.KioskService, attendance *service.AttendanceService) *KioskHandler {
+	return &KioskHandler{Service: s, Attendance: attendance}
+}
+
+// RegisterKiosk godoc
+// @Summary      Register a kiosk
+// @Description  Returns the kiosk key once; configure it on the kiosk device.
+// @Tags         kiosks
+// @Accept       json
+// @Produce      json
+// @Param        kiosk  body      model.KioskInput  true  "Kiosk"
+// @Success      201    {object}  model.KioskRegistration
+// @Failure      400    {object}  model.Problem
+// @Failure      500    {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /kiosks [post]
+func (h *KioskHandler) RegisterKiosk(c *gin.Context) {
+	var input model.KioskInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	registration, err := h.Service.RegisterKiosk(c.Request.Context(), &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, registration)
+}
+
+// GetKiosks godoc
+// @Summary      List kiosks
+// @Tags         kiosks
+// @Produce      json
+// @Success      200  {array}   model.Kiosk
+// @Failure      500  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /kiosks [get]
+func (h *KioskHandler) GetKiosks(c *gin.Context) {
+	list, err := h.Service.GetKiosks(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// DeactivateKiosk godoc
+// @Summary      Deactivate a kiosk
+// @Description  The kiosk can no longer issue tokens, and tokens it already showed are refused.
+// @Tags         kiosks
+// @Param        id   path  int  true  "Kiosk ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /kiosks/{id} [delete]
+func (h *KioskHandler) DeactivateKiosk(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	if err := h.Service.DeactivateKiosk(c.Request.Context(), uint(id)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetKioskToken godoc
+// @Summary      Get a QR token for display
+// @Description  Called by the kiosk with its key in X-Kiosk-Key, on its school's host. The token is short-lived; fetch a new one before expires_at.
+// @Tags         kiosks
+// @Produce      json
+// @Param        X-Kiosk-Key  header    string  true  "Kiosk key"
+// @Success      200          {object}  model.KioskToken
+// @Failure      401          {object}  model.Problem
+// @Router       /kiosk/token [get]
+func (h *KioskHandler) GetKioskToken(c *gin.Context) {
+	key := c.GetHeader(KioskKeyHeader)
+	if key == "" {
+		c.Error(apperror.Unauthorized("missing_kiosk_key", "missing kiosk key"))
+		return
+	}
+
+	token, err := h.Service.IssueToken(c.Request.Context(), key)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, token)
+}
+
+// KioskCheckIn godoc
+// @Summary      Check in or out by scanning a kiosk QR code
+// @Description  The teacher is taken from the bearer token, not the request body. The scanned token must be fresh and come from an active kiosk.
+// @Tags         attendance
+// @Accept       json
+// @Produce      json
+// @Param        scan         body      model.KioskCheckInInput  true   "Scanned token"
+// @Param        X-Device-ID  header    string                   false  "Device identifier"
+// @Success      201   {object}  map[string]string
+// @Failure      400   {object}  model.Problem
+// @Failure      401   {object}  model.Problem
+// @Failure      403   {object}  model.Problem
+// @Failure      409   {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /attendance/kiosk [post]
+func (h *KioskHandler) KioskCheckIn(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+	if claims.TeacherID == 0 {
+		c.Error(errTeachersOnly)
+		return
+	}
+
+	var input model.KioskCheckInInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	kiosk, err := h.Service.VerifyToken(c.Request.Context(), input.Token)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = h.Attendance.MarkAttendance(c.Request.Context(), &model.AttendanceRequest{
+		TeacherID:        claims.TeacherID,
+		Status:           input.Status,
+		KioskID:          &kiosk.ID,
+		CampusID:         kiosk.CampusID,
+		ClientIP:         c.ClientIP(),
+		DeviceIdentifier: c.GetHeader(DeviceIDHeader),
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	message := "You have checked in successfully"
+	if input.Status == model.PunchCheckOut {
+		message = "You have checked out successfully"
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": message, "kiosk": kiosk.Name})
+}
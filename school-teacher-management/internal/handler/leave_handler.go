@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"school-teacher-management/internal/auth"
+	"school-teacher-management/internal/middleware"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LeaveHandler struct {
+	Service *service.LeaveService
+}
+
+func NewLeaveHandler(s *service.LeaveService) *LeaveHandler {
+	return &LeaveHandler{Service: s}
+}
+
+// CreateLeave godoc
+// @Summary      Request leave
+// @Description  Teachers request leave for themselves; admin and office staff may pass teacher_id. The department heads are emailed. comp_off leave is paid from compensatory-off credits and needs enough balance for its working days.
+// @Tags         leave
+// @Accept       json
+// @Produce      json
+// @Param        leave  body      model.LeaveRequestInput  true  "Leave request"
+// @Success      201    {object}  model.LeaveRequest
+// @Failure      400    {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /leaves [post]
+func (h *LeaveHandler) CreateLeave(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+
+	var input model.LeaveRequestInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	if !claims.HasRole(auth.RoleAdmin, auth.RoleOffice) || input.TeacherID == 0 {
+		input.TeacherID = claims.TeacherID
+	}
+
+	leave, err := h.Service.RequestLeave(c.Request.Context(), &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, leave)
+}
+
+// GetLeaves godoc
+// @Summary      List leave requests
+// @Description  Teachers see their own requests, heads of department their department's, admin and office staff everyone's.
+// @Tags         leave
+// @Produce      json
+// @Param        teacherId  query     int     false  "Teacher ID"
+// @Param        status     query     string  false  "pending, approved or rejected"
+// @Success      200        {array}   model.LeaveRequest
+// @Failure      500        {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /leaves [get]
+func (h *LeaveHandler) GetLeaves(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+
+	teacherID, _ := strconv.Atoi(c.Query("teacherId"))
+	department := ""
+
+	switch claims.Role {
+	case auth.RoleAdmin, auth.RoleOffice:
+	case auth.RoleHOD:
+		department = claims.Department
+	default:
+		teacherID = int(claims.TeacherID)
+	}
+
+	list, err := h.Service.GetLeaves(c.Request.Context(), uint(teacherID), department, c.Query("status"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// ApproveLeave godoc
+// @Summary      Approve leave
+// @Tags         leave
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                       true   "Leave request ID"
+// @Param        decision  body      model.LeaveDecisionInput  false  "Optional note"
+// @Success      200       {object}  model.LeaveRequest
+// @Failure      400       {object}  model.Problem
+// @Failure      403       {object}  model.Problem
+// @Failure      404       {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /leaves/{id}/approve [post]
+func (h *LeaveHandler) ApproveLeave(c *gin.Context) {
+	h.decide(c, true)
+}
+
+// RejectLeave godoc
+// @Summary      Reject leave
+// @Tags         leave
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                       true   "Leave request ID"
+// @Param        decision  body      model.LeaveDecisionInput  false  "Optional note"
+// @Success      200       {object}  model.LeaveRequest
+// @Failure      400       {object}  model.Problem
+// @Failure      403       {object}  model.Problem
+// @Failure      404       {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /leaves/{id}/reject [post]
+func (h *LeaveHandler) RejectLeave(c *gin.Context) {
+	h.decide(c, false)
+}
+
+func (h *LeaveHandler) decide(c *gin.Context, approve bool) {
+	claims, _ := middleware.CurrentClaims(c)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	var input model.LeaveDecisionInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			bindError(c, err)
+			return
+		}
+	}
+
+	leave, err := h.Service.GetLeave(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	allowed := claims.Role == auth.RoleAdmin ||
+		(claims.Role == auth.RoleHOD && claims.Department == leave.Teacher.Department)
+	if !allowed {
+		c.Error(middleware.ErrInsufficientPermissions)
+		return
+	}
+
+	leave, err = h.Service.DecideLeave(c.Request.Context(), uint(id), approve, claims.TeacherID, input.Note)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, leave)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NetworkHandler struct {
+	Service *service.NetworkService
+}
+
+func NewNetworkHandler(s *service.NetworkService) *NetworkHandler {
+	return &NetworkHandler{Service: s}
+}
+
+// CreateNetwork godoc
+// @Summary      Add a campus network range
+// @Description  Adds a range to a campus of the caller's school. Once the school has any range, direct punches from other addresses are flagged or rejected according to the school's network policy. The client IP honours X-Forwarded-For only from TRUSTED_PROXIES.
+// @Tags         networks
+// @Accept       json
+// @Produce      json
+// @Param        network  body      model.CampusNetworkInput  true  "Network range"
+// @Success      201      {object}  model.CampusNetwork
+// @Failure      400      {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /networks [post]
+func (h *NetworkHandler) CreateNetwork(c *gin.Context) {
+	var input model.CampusNetworkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	network, err := h.Service.CreateNetwork(c.Request.Context(), &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, network)
+}
+
+// GetNetworks godoc
+// @Summary      List campus network ranges
+// @Tags         networks
+// @Produce      json
+// @Success      200  {array}   model.CampusNetwork
+// @Failure      500  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /networks [get]
+func (h *NetworkHandler) GetNetworks(c *gin.Context) {
+	list, err := h.Service.GetNetworks(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// DeleteNetwork godoc
+// @Summary      Remove a campus network range
+// @Tags         networks
+// @Param        id   path  int  true  "Network ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /networks/{id} [delete]
+func (h *NetworkHandler) DeleteNetwork(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	if err := h.Service.DeleteNetwork(c.Request.Context(), uint(id)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"school-teacher-management/internal/auth"
+	"school-teacher-management/internal/middleware"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	Service *service.NotificationService
+}
+
+func NewNotificationHandler(s *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{Service: s}
+}
+
+// GetPreferences godoc
+// @Summary      Get notification preferences
+// @Tags         notifications
+// @Produce      json
+// @Param        id   path      int  true  "Teacher ID"
+// @Success      200  {object}  model.NotificationPreference
+// @Failure      403  {object}  model.Problem
+// @Failure      404  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /teachers/{id}/notification-preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	id, ok := h.teacherID(c)
+	if !ok {
+		return
+	}
+
+	pref, err := h.Service.GetPreference(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pref)
+}
+
+// UpdatePreferences godoc
+// @Summary      Update notification preferences
+// @Description  With daily_digest enabled, late alerts are folded into one end-of-day digest.
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Param        id           path      int                                  true  "Teacher ID"
+// @Param        preferences  body      model.NotificationPreferenceRequest  true  "Preferences"
+// @Success      200          {object}  model.NotificationPreference
+// @Failure      400          {object}  model.Problem
+// @Failure      403          {object}  model.Problem
+// @Failure      404          {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /teachers/{id}/notification-preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	id, ok := h.teacherID(c)
+	if !ok {
+		return
+	}
+
+	var input model.NotificationPreferenceRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	pref, err := h.Service.UpdatePreference(c.Request.Context(), id, &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pref)
+}
+
+// teacherID reads the path ID and checks the caller is that teacher or an
+// administrator.
+func (h *NotificationHandler) teacherID(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return 0, false
+	}
+
+	claims, _ := middleware.CurrentClaims(c)
+	if claims.Role != auth.RoleAdmin && claims.TeacherID != uint(id) {
+		c.Error(middleware.ErrInsufficientPermissions)
+		return 0, false
+	}
+
+	return uint(id), true
+}
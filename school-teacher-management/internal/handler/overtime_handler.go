@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"school-teacher-management/internal/auth"
+	"school-teacher-management/internal/middleware"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OvertimeHandler struct {
+	Service *service.OvertimeService
+}
+
+func NewOvertimeHandler(s *service.OvertimeService) *OvertimeHandler {
+	return &OvertimeHandler{Service: s}
+}
+
+// GetOvertimes godoc
+// @Summary      List overtime
+// @Description  Overtime is detected at check-out and on corrections: time after the scheduled end of the day, or the whole session on a weekend or holiday. Teachers see their own, heads of department their department's, admin and office staff everyone's.
+// @Tags         overtime
+// @Produce      json
+// @Param        teacherId  query     int     false  "Teacher ID"
+// @Param        status     query     string  false  "pending, approved or rejected"
+// @Success      200        {array}   model.OvertimeRecord
+// @Failure      500        {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /overtime [get]
+func (h *OvertimeHandler) GetOvertimes(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+
+	teacherID, _ := strconv.Atoi(c.Query("teacherId"))
+	department := ""
+
+	switch claims.Role {
+	case auth.RoleAdmin, auth.RoleOffice:
+	case auth.RoleHOD:
+		department = claims.Department
+	default:
+		teacherID = int(claims.TeacherID)
+	}
+
+	list, err := h.Service.GetOvertimes(c.Request.Context(), uint(teacherID), department, c.Query("status"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// ApproveOvertime godoc
+// @Summary      Approve overtime
+// @Description  compensation comp_off (default) credits compensatory-off leave in half-day steps; paid includes the hours in the payroll export.
+// @Tags         overtime
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                          true   "Overtime ID"
+// @Param        decision  body      model.OvertimeDecisionInput  false  "Compensation and optional note"
+// @Success      200       {object}  model.OvertimeRecord
+// @Failure      400       {object}  model.Problem
+// @Failure      403       {object}  model.Problem
+// @Failure      404       {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /overtime/{id}/approve [post]
+func (h *OvertimeHandler) ApproveOvertime(c *gin.Context) {
+	h.decide(c, true)
+}
+
+// RejectOvertime godoc
+// @Summary      Reject overtime
+// @Tags         overtime
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                          true   "Overtime ID"
+// @Param        decision  body      model.OvertimeDecisionInput  false  "Optional note"
+// @Success      200       {object}  model.OvertimeRecord
+// @Failure      400       {object}  model.Problem
+// @Failure      403       {object}  model.Problem
+// @Failure      404       {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /overtime/{id}/reject [post]
+func (h *OvertimeHandler) RejectOvertime(c *gin.Context) {
+	h.decide(c, false)
+}
+
+func (h *OvertimeHandler) decide(c *gin.Context, approve bool) {
+	claims, _ := middleware.CurrentClaims(c)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	var input model.OvertimeDecisionInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			bindError(c, err)
+			return
+		}
+	}
+
+	record, err := h.Service.GetOvertime(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	allowed := claims.Role == auth.RoleAdmin ||
+		(claims.Role == auth.RoleHOD && claims.Department == record.Teacher.Department)
+	if !allowed {
+		c.Error(middleware.ErrInsufficientPermissions)
+		return
+	}
+
+	record, err = h.Service.DecideOvertime(c.Request.Context(), uint(id), approve, &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+// GetCompOffBalance godoc
+// @Summary      Compensatory-off balance
+// @Description  Unexpired credits with days left, soonest expiry first. Teachers see their own balance.
+// @Tags         overtime
+// @Produce      json
+// @Param        id   path      int  true  "Teacher ID"
+// @Success      200  {object}  model.CompOffBalance
+// @Failure      403  {object}  model.Problem
+// @Failure      500  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /teachers/{id}/comp-off-balance [get]
+func (h *OvertimeHandler) GetCompOffBalance(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	claims, _ := middleware.CurrentClaims(c)
+	if !claims.HasRole(auth.RoleAdmin, auth.RoleOffice) && claims.TeacherID != uint(id) {
+		c.Error(middleware.ErrInsufficientPermissions)
+		return
+	}
+
+	balance, err := h.Service.GetBalance(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
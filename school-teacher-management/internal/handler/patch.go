@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/service"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	mergePatchType = "application/merge-patch+json"
+	jsonPatchType  = "application/json-patch+json"
+)
+
+var errUnsupportedPatch = apperror.UnsupportedMedia("unsupported_patch_type",
+	"PATCH body must be "+mergePatchType+" or "+jsonPatchType)
+
+// Versioned resources are served with their version as a strong ETag.
+func setETag(c *gin.Context, version uint) {
+	c.Header("ETag", strconv.Quote(strconv.FormatUint(uint64(version), 10)))
+}
+
+// ifMatch returns the version named by the If-Match header, or 0 when the
+// header is missing or "*". A header naming anything else than one version
+// cannot match.
+func ifMatch(c *gin.Context) (uint, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tag, err := strconv.Unquote(header)
+	if err != nil {
+		return 0, service.ErrVersionMismatch
+	}
+	version, err := strconv.ParseUint(tag, 10, 32)
+	if err != nil || version == 0 {
+		return 0, service.ErrVersionMismatch
+	}
+	return uint(version), nil
+}
+
+// applyPatch applies the request body to current, as a JSON Merge Patch
+// (RFC 7396) or a JSON Patch (RFC 6902) by its content type, and decodes
+// and validates the result into target. A body sent as application/json
+// is taken as a merge patch. It reports the error itself and returns false
+// when the patch cannot be applied.
+func applyPatch(c *gin.Context, current, target interface{}) bool {
+	patched, err := patchDocument(c, current)
+	if err != nil {
+		c.Error(err)
+		return false
+	}
+
+	if err := json.Unmarshal(patched, target); err != nil {
+		bindError(c, err)
+		return false
+	}
+	if err := binding.Validator.ValidateStruct(target); err != nil {
+		bindError(c, err)
+		return false
+	}
+	return true
+}
+
+func patchDocument(c *gin.Context, current interface{}) ([]byte, error) {
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.ContentType() {
+	case mergePatchType, binding.MIMEJSON:
+		patched, err := jsonpatch.MergePatch(doc, body)
+		if err != nil {
+			return nil, apperror.Validation("invalid_patch", "invalid merge patch: "+err.Error())
+		}
+		return patched, nil
+	case jsonPatchType:
+		patch, err := jsonpatch.DecodePatch(body)
+		if err != nil {
+			return nil, apperror.Validation("invalid_patch", "invalid JSON patch: "+err.Error())
+		}
+		patched, err := patch.Apply(doc)
+		if err != nil {
+			return nil, apperror.Rule("patch_failed", "JSON patch cannot be applied: "+err.Error())
+		}
+		return patched, nil
+	}
+	return nil, errUnsupportedPatch
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PayrollHandler struct {
+	Service *service.PayrollService
+}
+
+func NewPayrollHandler(s *service.PayrollService) *PayrollHandler {
+	return &PayrollHandler{Service: s}
+}
+
+// ExportPayroll godoc
+// @Summary      Export payroll for a pay period
+// @Description  Per teacher payable days, loss-of-pay days, overtime hours and late deductions, computed from attendance and approved leave. CSV columns follow PAYROLL_CSV_COLUMNS.
+// @Tags         payroll
+// @Produce      json
+// @Produce      text/csv
+// @Param        from      query     string  true   "First day (YYYY-MM-DD)"
+// @Param        to        query     string  true   "Last day (YYYY-MM-DD)"
+// @Param        format    query     string  false  "csv (default) or json"
+// @Param        campusId  query     int     false  "Only teachers based at this campus"
+// @Success      200       {object}  model.PayrollReport
+// @Failure      400       {object}  model.Problem
+// @Failure      500       {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /payroll/export [get]
+func (h *PayrollHandler) ExportPayroll(c *gin.Context) {
+	from, err := time.Parse("2006-01-02", c.Query("from"))
+	if err != nil {
+		c.Error(apperror.Field("from", "format", "must be YYYY-MM-DD"))
+		return
+	}
+
+	to, err := time.Parse("2006-01-02", c.Query("to"))
+	if err != nil {
+		c.Error(apperror.Field("to", "format", "must be YYYY-MM-DD"))
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		c.Error(apperror.Field("format", "oneof", "must be one of: csv json"))
+		return
+	}
+
+	campusID, _ := strconv.Atoi(c.Query("campusId"))
+
+	report, err := h.Service.Report(c.Request.Context(), from, to, uint(campusID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	h.writeCSV(c, report)
+}
+
+func (h *PayrollHandler) writeCSV(c *gin.Context, report *model.PayrollReport) {
+	filename := "payroll_" + report.From + "_" + report.To + ".csv"
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	if err := h.Service.WriteCSV(c.Writer, report); err != nil {
+		c.Error(err)
+	}
+}
+
+// GetSchoolReport godoc
+// @Summary      Compare schools over a period
+// @Description  Per school attendance totals from the payroll computation: teachers, working, present, leave and absent days, late arrivals and the attendance rate. Trust-level administrators only.
+// @Tags         payroll
+// @Produce      json
+// @Param        from  query     string  true  "First day (YYYY-MM-DD)"
+// @Param        to    query     string  true  "Last day (YYYY-MM-DD)"
+// @Success      200   {object}  model.TrustReport
+// @Failure      400   {object}  model.Problem
+// @Failure      403   {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /reports/schools [get]
+func (h *PayrollHandler) GetSchoolReport(c *gin.Context) {
+	from, err := time.Parse("2006-01-02", c.Query("from"))
+	if err != nil {
+		c.Error(apperror.Field("from", "format", "must be YYYY-MM-DD"))
+		return
+	}
+
+	to, err := time.Parse("2006-01-02", c.Query("to"))
+	if err != nil {
+		c.Error(apperror.Field("to", "format", "must be YYYY-MM-DD"))
+		return
+	}
+
+	report, err := h.Service.TrustReport(c.Request.Context(), from, to)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PeriodHandler struct {
+	Service *service.PeriodService
+}
+
+func NewPeriodHandler(s *service.PeriodService) *PeriodHandler {
+	return &PeriodHandler{Service: s}
+}
+
+// GetPeriods godoc
+// @Summary      List attendance periods
+// @Description  Months that have been closed at least once. Months not listed are open.
+// @Tags         periods
+// @Produce      json
+// @Param        year  query     int  false  "Year"
+// @Success      200   {array}   model.AttendancePeriod
+// @Failure      500   {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /attendance/periods [get]
+func (h *PeriodHandler) GetPeriods(c *gin.Context) {
+	year, _ := strconv.Atoi(c.Query("year"))
+
+	list, err := h.Service.GetPeriods(c.Request.Context(), year)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// ClosePeriod godoc
+// @Summary      Close an attendance period
+// @Description  Locks the month against check-ins, edits, deletes and corrections, and snapshots its register.
+// @Tags         periods
+// @Produce      json
+// @Param        period  path      string  true  "Month (YYYY-MM)"
+// @Success      200     {object}  model.AttendancePeriod
+// @Failure      400     {object}  model.Problem
+// @Failure      409     {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /attendance/periods/{period}/close [post]
+func (h *PeriodHandler) ClosePeriod(c *gin.Context) {
+	h.changePeriod(c, h.Service.ClosePeriod)
+}
+
+// ReopenPeriod godoc
+// @Summary      Reopen an attendance period
+// @Description  Allows writes into the month again; its snapshots are kept for comparison.
+// @Tags         periods
+// @Produce      json
+// @Param        period  path      string  true  "Month (YYYY-MM)"
+// @Success      200     {object}  model.AttendancePeriod
+// @Failure      400     {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /attendance/periods/{period}/reopen [post]
+func (h *PeriodHandler) ReopenPeriod(c *gin.Context) {
+	h.changePeriod(c, h.Service.ReopenPeriod)
+}
+
+// GetPeriodSnapshot godoc
+// @Summary      Register snapshot of a period
+// @Description  The register as it stood when the period was last closed.
+// @Tags         periods
+// @Produce      json
+// @Param        period  path      string  true  "Month (YYYY-MM)"
+// @Success      200     {object}  model.AttendanceSnapshot
+// @Failure      400     {object}  model.Problem
+// @Failure      404     {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /attendance/periods/{period}/snapshot [get]
+func (h *PeriodHandler) GetPeriodSnapshot(c *gin.Context) {
+	month, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+
+	snapshot, err := h.Service.GetSnapshot(c.Request.Context(), month.Year(), month.Month())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// ComparePeriod godoc
+// @Summary      Compare a period with its snapshot
+// @Description  Attendance added, removed or changed since the period was last closed.
+// @Tags         periods
+// @Produce      json
+// @Param        period  path      string  true  "Month (YYYY-MM)"
+// @Success      200     {object}  model.PeriodComparison
+// @Failure      400     {object}  model.Problem
+// @Failure      404     {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /attendance/periods/{period}/compare [get]
+func (h *PeriodHandler) ComparePeriod(c *gin.Context) {
+	month, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+
+	comparison, err := h.Service.ComparePeriod(c.Request.Context(), month.Year(), month.Month())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+func (h *PeriodHandler) changePeriod(
+	c *gin.Context,
+	change func(ctx context.Context, year int, month time.Month) (*model.AttendancePeriod, error),
+) {
+	month, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+
+	period, err := change(c.Request.Context(), month.Year(), month.Month())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, period)
+}
+
+func parsePeriod(c *gin.Context) (time.Time, bool) {
+	month, err := time.Parse("2006-01", c.Param("period"))
+	if err != nil {
+		c.Error(apperror.Field("period", "format", "must be YYYY-MM"))
+		return time.Time{}, false
+	}
+	return month, true
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+
+	"school-teacher-management/internal/middleware"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PunchSyncHandler struct {
+	Service *service.PunchSyncService
+}
+
+func NewPunchSyncHandler(s *service.PunchSyncService) *PunchSyncHandler {
+	return &PunchSyncHandler{Service: s}
+}
+
+// SyncPunches godoc
+// @Summary      Sync punches queued offline
+// @Description  Uploads check-ins and check-outs a device queued while offline, with their device timestamps. Each punch carries an idempotency key, so a retried batch is safe: punches already synced are reported as duplicate. The device clock is compared with the server's via sent_at; beyond SYNC_MAX_CLOCK_SKEW_SECONDS the punch times are shifted by the skew or, under SYNC_SKEW_POLICY=reject, refused. Among offline punches the earliest check-in and latest check-out of each day win; punches recorded online are never replaced. Queued punches cannot show they were made on campus, so with geofences or campus networks configured they are rejected or flagged under the school's geofence and network policies. The result of every punch is reported individually.
+// @Tags         attendance
+// @Accept       json
+// @Produce      json
+// @Param        batch        body      model.PunchSyncInput  true   "Queued punches"
+// @Param        X-Device-ID  header    string                false  "Device identifier"
+// @Success      200          {object}  model.PunchSyncReport
+// @Failure      400          {object}  model.Problem
+// @Failure      403          {object}  model.Problem
+// @Failure      500          {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /attendance/sync [post]
+func (h *PunchSyncHandler) SyncPunches(c *gin.Context) {
+	claims, _ := middleware.CurrentClaims(c)
+	if claims.TeacherID == 0 {
+		c.Error(errTeachersOnly)
+		return
+	}
+
+	var input model.PunchSyncInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	report, err := h.Service.Sync(c.Request.Context(), claims.TeacherID, c.GetHeader(DeviceIDHeader), &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"school-teacher-management/internal/middleware"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SchoolHandler struct {
+	Service *service.SchoolService
+}
+
+func NewSchoolHandler(s *service.SchoolService) *SchoolHandler {
+	return &SchoolHandler{Service: s}
+}
+
+// CreateSchool godoc
+// @Summary      Add a school
+// @Description  Trust-level administrators only. The slug is the school's subdomain.
+// @Tags         schools
+// @Accept       json
+// @Produce      json
+// @Param        school  body      model.SchoolInput  true  "School"
+// @Success      201     {object}  model.School
+// @Failure      400     {object}  model.Problem
+// @Failure      403     {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /schools [post]
+func (h *SchoolHandler) CreateSchool(c *gin.Context) {
+	var input model.SchoolInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	school, err := h.Service.CreateSchool(c.Request.Context(), &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, school)
+}
+
+// GetSchools godoc
+// @Summary      List schools
+// @Description  Trust-level administrators only.
+// @Tags         schools
+// @Produce      json
+// @Success      200  {array}   model.School
+// @Failure      403  {object}  model.Problem
+// @Failure      500  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /schools [get]
+func (h *SchoolHandler) GetSchools(c *gin.Context) {
+	list, err := h.Service.GetSchools(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetSchool godoc
+// @Summary      Get a school
+// @Description  Trust-level administrators, or administrators of the school.
+// @Tags         schools
+// @Produce      json
+// @Param        id   path      int  true  "School ID"
+// @Success      200  {object}  model.School
+// @Failure      403  {object}  model.Problem
+// @Failure      404  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /schools/{id} [get]
+func (h *SchoolHandler) GetSchool(c *gin.Context) {
+	id, ok := h.schoolParam(c)
+	if !ok {
+		return
+	}
+
+	school, err := h.Service.GetSchool(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, school)
+}
+
+// UpdateSchool godoc
+// @Summary      Update a school's settings
+// @Description  Name, subdomain, timezone, working hours and the geofence and network policies, which override GEOFENCE_POLICY and NETWORK_POLICY when set. Trust-level administrators, or administrators of the school.
+// @Tags         schools
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                true  "School ID"
+// @Param        school  body      model.SchoolInput  true  "School"
+// @Success      200     {object}  model.School
+// @Failure      400     {object}  model.Problem
+// @Failure      403     {object}  model.Problem
+// @Failure      404     {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /schools/{id} [put]
+func (h *SchoolHandler) UpdateSchool(c *gin.Context) {
+	id, ok := h.schoolParam(c)
+	if !ok {
+		return
+	}
+
+	var input model.SchoolInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	school, err := h.Service.UpdateSchool(c.Request.Context(), id, &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, school)
+}
+
+// schoolParam reads the school ID and checks that the caller may manage
+// that school.
+func (h *SchoolHandler) schoolParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return 0, false
+	}
+
+	claims, _ := middleware.CurrentClaims(c)
+	if !claims.IsTrustAdmin() && claims.SchoolID != uint(id) {
+		c.Error(middleware.ErrInsufficientPermissions)
+		return 0, false
+	}
+
+	return uint(id), true
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"school-teacher-management/internal/apperror"
 	"school-teacher-management/internal/model"
 	"school-teacher-management/internal/service"
 
@@ -28,23 +29,20 @@ func NewTeacherHandler(s *service.TeacherService) *TeacherHandler {
 // @Produce      json
 // @Param        teacher  body      model.Teacher  true  "Teacher data"
 // @Success      201      {object}  model.Teacher
-// @Failure      400      {object}  map[string]string
-// @Failure      500      {object}  map[string]string
+// @Failure      400      {object}  model.Problem
+// @Failure      409      {object}  model.Problem
+// @Failure      500      {object}  model.Problem
 // @Router       /teachers [post]
 func (h *TeacherHandler) CreateTeacher(c *gin.Context) {
 	var input model.Teacher
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, map[string]string{
-			"error": err.Error(),
-		})
+		bindError(c, err)
 		return
 	}
 
-	if err := h.Service.CreateTeacher(&input); err != nil {
-		c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+	if err := h.Service.CreateTeacher(c.Request.Context(), &input); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -56,42 +54,101 @@ func (h *TeacherHandler) CreateTeacher(c *gin.Context) {
 
 // UpdateTeacher godoc
 // @Summary      Update teacher
-// @Description  Update a teacher by ID
+// @Description  Replaces a teacher by ID; only the fields that changed are written. With If-Match the update is refused with 412 unless the teacher still has that version.
 // @Tags         teachers
 // @Accept       json
 // @Produce      json
-// @Param        id       path      int            true  "Teacher ID"
-// @Param        teacher  body      model.Teacher  true  "Teacher data"
-// @Success      200      {object}  model.Teacher
-// @Failure      400      {object}  map[string]string
-// @Failure      500      {object}  map[string]string
+// @Param        id        path      int            true   "Teacher ID"
+// @Param        If-Match  header    string         false  "ETag of the teacher as read"
+// @Param        teacher   body      model.Teacher  true   "Teacher data"
+// @Success      200       {object}  model.Teacher
+// @Failure      400       {object}  model.Problem
+// @Failure      409       {object}  model.Problem
+// @Failure      412       {object}  model.Problem
+// @Failure      500       {object}  model.Problem
 // @Router       /teachers/{id} [put]
 func (h *TeacherHandler) UpdateTeacher(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid ID",
-		})
+		c.Error(errInvalidID)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
 	var input model.Teacher
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, map[string]string{
-			"error": err.Error(),
-		})
+		bindError(c, err)
 		return
 	}
 
 	input.ID = uint(id)
 
-	if err := h.Service.UpdateTeacher(&input); err != nil {
-		c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+	if err := h.Service.UpdateTeacher(c.Request.Context(), &input, version); err != nil {
+		c.Error(err)
+		return
+	}
+
+	setETag(c, input.Version)
+	c.JSON(http.StatusOK, input)
+}
+
+// PatchTeacher godoc
+// @Summary      Partially update teacher
+// @Description  Applies a JSON Merge Patch (application/merge-patch+json) or a JSON Patch (application/json-patch+json) to a teacher; only the fields that changed are written. The update is refused with 412 if the teacher changes meanwhile or, with If-Match, no longer has that version.
+// @Tags         teachers
+// @Accept       application/merge-patch+json,application/json-patch+json
+// @Produce      json
+// @Param        id        path      int     true   "Teacher ID"
+// @Param        If-Match  header    string  false  "ETag of the teacher as read"
+// @Param        patch     body      object  true   "Merge patch or JSON Patch"
+// @Success      200       {object}  model.Teacher
+// @Failure      400       {object}  model.Problem
+// @Failure      404       {object}  model.Problem
+// @Failure      409       {object}  model.Problem
+// @Failure      412       {object}  model.Problem
+// @Failure      415       {object}  model.Problem
+// @Failure      422       {object}  model.Problem
+// @Router       /teachers/{id} [patch]
+func (h *TeacherHandler) PatchTeacher(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	version, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	current, err := h.Service.GetTeacher(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if version == 0 {
+		version = current.Version
+	}
+
+	var input model.Teacher
+	if !applyPatch(c, current, &input) {
+		return
+	}
+
+	input.ID = current.ID
+
+	if err := h.Service.UpdateTeacher(c.Request.Context(), &input, version); err != nil {
+		c.Error(err)
 		return
 	}
 
+	setETag(c, input.Version)
 	c.JSON(http.StatusOK, input)
 }
 
@@ -103,17 +160,15 @@ func (h *TeacherHandler) UpdateTeacher(c *gin.Context) {
 // @Param        q   query     string  false  "Search keyword"
 // @Param        subject   query     string  false  "Search keyword"
 // @Success      200 {array}   model.Teacher
-// @Failure      500 {object}  map[string]string
+// @Failure      500 {object}  model.Problem
 // @Router       /teachers [get]
 func (h *TeacherHandler) SearchTeachers(c *gin.Context) {
 	q := c.Query("q")
 	subject := c.Query("subject")
 
-	teachers, err := h.Service.SearchTeachers(q, subject)
+	teachers, err := h.Service.SearchTeachers(c.Request.Context(), q, subject)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": err.Error(),
-		})
+		c.Error(err)
 		return
 	}
 
@@ -126,25 +181,22 @@ func (h *TeacherHandler) SearchTeachers(c *gin.Context) {
 // @Param        id   path      int  true  "Teacher ID"
 // @Produce      json
 // @Success      200  {object}  model.Teacher
-// @Failure      404  {object}  map[string]string
+// @Failure      404  {object}  model.Problem
 // @Router       /teachers/{id} [get]
 func (h *TeacherHandler) GetTeacherByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid ID",
-		})
+		c.Error(errInvalidID)
 		return
 	}
 
-	teacher, err := h.Service.GetTeacher(uint(id))
+	teacher, err := h.Service.GetTeacher(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, map[string]string{
-			"error": "Teacher not found",
-		})
+		c.Error(err)
 		return
 	}
 
+	setETag(c, teacher.Version)
 	c.JSON(http.StatusOK, teacher)
 }
 
@@ -154,31 +206,27 @@ func (h *TeacherHandler) GetTeacherByID(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param teachers body []model.TeacherRequest true "List of teachers"
+// @Param Idempotency-Key header string false "Key making retries of the request safe"
 // @Success 201 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Router /teachers/bulk [post]
 func (h *TeacherHandler) CreateTeachers(c *gin.Context) {
 	var input []model.TeacherRequest
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		bindError(c, err)
 		return
 	}
 
 	if len(input) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "teacher list cannot be empty",
-		})
+		c.Error(apperror.Validation("empty_list", "teacher list cannot be empty"))
 		return
 	}
 
-	if err := h.Service.CreateTeachers(input); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+	if err := h.Service.CreateTeachers(c.Request.Context(), input); err != nil {
+		c.Error(err)
 		return
 	}
 
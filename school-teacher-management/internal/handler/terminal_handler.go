@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TerminalKeyHeader carries the key a terminal was given at registration.
+const TerminalKeyHeader = "X-Terminal-Key"
+
+type TerminalHandler struct {
+	Service    *service.TerminalService
+	Attendance *service.AttendanceService
+}
+
+func NewTerminalHandler(s *service.TerminalService, attendance *service.AttendanceService) *TerminalHandler {
+	return &TerminalHandler{Service: s, Attendance: attendance}
+}
+
+// RegisterTerminal godoc
+// @Summary      Register a shared terminal
+// @Description  Returns the terminal key once; configure it on the device.
+// @Tags         terminals
+// @Accept       json
+// @Produce      json
+// @Param        terminal  body      model.TerminalInput  true  "Terminal"
+// @Success      201       {object}  model.TerminalRegistration
+// @Failure      400       {object}  model.Problem
+// @Failure      500       {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /terminals [post]
+func (h *TerminalHandler) RegisterTerminal(c *gin.Context) {
+	var input model.TerminalInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	registration, err := h.Service.RegisterTerminal(c.Request.Context(), &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, registration)
+}
+
+// GetTerminals godoc
+// @Summary      List shared terminals
+// @Tags         terminals
+// @Produce      json
+// @Success      200  {array}   model.Terminal
+// @Failure      500  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /terminals [get]
+func (h *TerminalHandler) GetTerminals(c *gin.Context) {
+	list, err := h.Service.GetTerminals(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// DeactivateTerminal godoc
+// @Summary      Deactivate a shared terminal
+// @Tags         terminals
+// @Param        id   path  int  true  "Terminal ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /terminals/{id} [delete]
+func (h *TerminalHandler) DeactivateTerminal(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	if err := h.Service.DeactivateTerminal(c.Request.Context(), uint(id)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetPIN godoc
+// @Summary      Set or reset a teacher's terminal PIN
+// @Description  Also lifts a lockout caused by repeated wrong PINs.
+// @Tags         terminals
+// @Accept       json
+// @Param        id   path  int             true  "Teacher ID"
+// @Param        pin  body  model.PINInput  true  "New PIN, 4 to 8 digits"
+// @Success      204  "No Content"
+// @Failure      400  {object}  model.Problem
+// @Failure      404  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /teachers/{id}/pin [put]
+func (h *TerminalHandler) SetPIN(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	var input model.PINInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	if err := h.Service.SetPIN(c.Request.Context(), uint(id), input.PIN); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// TerminalCheckIn godoc
+// @Summary      Check in or out on a shared terminal
+// @Description  The terminal authenticates with its key in X-Terminal-Key; the teacher, who must be of the terminal's school, with employee code and PIN. Repeated wrong PINs lock the PIN for a while.
+// @Tags         attendance
+// @Accept       json
+// @Produce      json
+// @Param        X-Terminal-Key  header    string                      true  "Terminal key"
+// @Param        punch           body      model.TerminalCheckInInput  true  "Employee code, PIN and status"
+// @Success      201             {object}  map[string]string
+// @Failure      400             {object}  model.Problem
+// @Failure      401             {object}  model.Problem
+// @Failure      409             {object}  model.Problem
+// @Failure      423             {object}  model.Problem
+// @Router       /terminal/check-in [post]
+func (h *TerminalHandler) TerminalCheckIn(c *gin.Context) {
+	terminal, err := h.Service.Authenticate(c.Request.Context(), c.GetHeader(TerminalKeyHeader))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var input model.TerminalCheckInInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	teacher, err := h.Service.VerifyPIN(c.Request.Context(), terminal, input.EmployeeCode, input.PIN)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = h.Attendance.MarkAttendance(c.Request.Context(), &model.AttendanceRequest{
+		TeacherID:  teacher.ID,
+		Status:     input.Status,
+		TerminalID: &terminal.ID,
+		CampusID:   terminal.CampusID,
+		ClientIP:   c.ClientIP(),
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	message := "You have checked in successfully"
+	if input.Status == model.PunchCheckOut {
+		message = "You have checked out successfully"
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": message, "teacher": teacher.FirstName + " " + teacher.LastName})
+}
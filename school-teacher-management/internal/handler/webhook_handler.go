@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	Service *service.WebhookService
+}
+
+func NewWebhookHandler(s *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{Service: s}
+}
+
+// CreateWebhook godoc
+// @Summary      Create webhook subscription
+// @Description  Subscribes a URL to event types ("*" for all): attendance.check_in, attendance.check_out, attendance.leave, attendance.correction, attendance.absent (sent at the late alert for teachers not checked in), attendance.deleted, teacher.created and teacher.updated. Payloads are signed with HMAC-SHA256 of "<t>.<body>" using the secret, sent as X-Webhook-Signature: t=<unix>,v1=<hex>.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        webhook  body      model.WebhookRequest  true  "Subscription"
+// @Success      201      {object}  model.WebhookSubscription
+// @Failure      400      {object}  model.Problem
+// @Failure      500      {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var input model.WebhookRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	sub, err := h.Service.CreateSubscription(c.Request.Context(), &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sub)
+}
+
+// GetWebhooks godoc
+// @Summary      List webhook subscriptions
+// @Tags         webhooks
+// @Produce      json
+// @Success      200  {array}   model.WebhookSubscription
+// @Failure      500  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /webhooks [get]
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	list, err := h.Service.GetSubscriptions(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetWebhookByID godoc
+// @Summary      Get webhook subscription
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      int  true  "Subscription ID"
+// @Success      200  {object}  model.WebhookSubscription
+// @Failure      404  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhookByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	sub, err := h.Service.GetSubscription(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// UpdateWebhook godoc
+// @Summary      Update webhook subscription
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true  "Subscription ID"
+// @Param        webhook  body      model.WebhookRequest  true  "Subscription"
+// @Success      200      {object}  model.WebhookSubscription
+// @Failure      400      {object}  model.Problem
+// @Failure      404      {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	var input model.WebhookRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	sub, err := h.Service.UpdateSubscription(c.Request.Context(), uint(id), &input)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// DeleteWebhook godoc
+// @Summary      Delete webhook subscription
+// @Tags         webhooks
+// @Param        id   path  int  true  "Subscription ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	if err := h.Service.DeleteSubscription(c.Request.Context(), uint(id)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries godoc
+// @Summary      Webhook delivery log
+// @Description  Most recent deliveries for a subscription, newest first
+// @Tags         webhooks
+// @Produce      json
+// @Param        id      path      int     true   "Subscription ID"
+// @Param        status  query     string  false  "pending, succeeded or dead"
+// @Success      200     {array}   model.WebhookDelivery
+// @Failure      500     {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	list, err := h.Service.GetDeliveries(c.Request.Context(), uint(id), c.Query("status"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetDeadLetters godoc
+// @Summary      Dead-lettered webhook deliveries
+// @Description  Deliveries that exhausted their retries
+// @Tags         webhooks
+// @Produce      json
+// @Success      200  {array}   model.WebhookDelivery
+// @Failure      500  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /webhooks/dead-letters [get]
+func (h *WebhookHandler) GetDeadLetters(c *gin.Context) {
+	list, err := h.Service.GetDeadLetters(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// RetryDelivery godoc
+// @Summary      Retry a dead-lettered delivery
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      int  true  "Delivery ID"
+// @Success      200  {object}  model.WebhookDelivery
+// @Failure      400  {object}  model.Problem
+// @Security     BearerAuth
+// @Router       /webhooks/deliveries/{id}/retry [post]
+func (h *WebhookHandler) RetryDelivery(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errInvalidID)
+		return
+	}
+
+	delivery, err := h.Service.RetryDelivery(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
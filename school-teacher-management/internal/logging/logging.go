@@ -0,0 +1,61 @@
+// Package logging builds the structured logger. Records logged with a
+// request's context carry its request ID, trace, school and caller, so a
+// failing request can be followed through the services and its SQL.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"school-teacher-management/internal/requestctx"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// New returns a logger writing to w as JSON, or as text for FormatText.
+func New(w io.Writer, format string, level slog.Level) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler = slog.NewJSONHandler(w, opts)
+	if format == FormatText {
+		handler = slog.NewTextHandler(w, opts)
+	}
+	return slog.New(contextHandler{handler})
+}
+
+// contextHandler adds the request values found in the record's context.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		if id := requestctx.RequestID(ctx); id != "" {
+			r.AddAttrs(slog.String("request_id", id))
+		}
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+		}
+		if schoolID, ok := requestctx.School(ctx); ok {
+			r.AddAttrs(slog.Uint64("school_id", uint64(schoolID)))
+		}
+		if actor := requestctx.Actor(ctx); actor.Role != "" {
+			r.AddAttrs(slog.Uint64("user_id", uint64(actor.TeacherID)), slog.String("role", actor.Role))
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
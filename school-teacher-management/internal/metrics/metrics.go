@@ -73,6 +73,18 @@ var (
 			Help: "Number of teachers checked in today",
 		},
 	)
+
+	// =========================
+	// WEBHOOK METRICS
+	// =========================
+
+	WebhookDeliveriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_deliveries_total",
+			Help: "Webhook delivery attempts by event type and outcome",
+		},
+		[]string{"event_type", "outcome"},
+	)
 )
 
 // Register all metrics here
@@ -91,5 +103,8 @@ func Register() {
 		AttendanceCheckInTotal,
 		AttendanceCheckOutTotal,
 		AttendanceTodayCheckedIn,
+
+		// Webhooks
+		WebhookDeliveriesTotal,
 	)
 }
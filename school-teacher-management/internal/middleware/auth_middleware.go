@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"strings"
+
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/auth"
+	"school-teacher-management/internal/config"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/requestctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+const claimsKey = "auth.claims"
+
+var (
+	ErrMissingToken            = apperror.Unauthorized("missing_token", "missing bearer token")
+	ErrInvalidToken            = apperror.Unauthorized("invalid_token", "invalid token")
+	ErrWrongSchool             = apperror.Forbidden("wrong_school", "token is not valid for this school")
+	ErrInsufficientPermissions = apperror.Forbidden("insufficient_permissions", "insufficient permissions")
+)
+
+// AuthMiddleware rejects requests without a valid bearer token. Browsers
+// cannot set headers on EventSource or WebSocket requests, so the token
+// is also accepted as the access_token query parameter.
+func AuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := bearerToken(c)
+		if token == "" {
+			abort(c, ErrMissingToken)
+			return
+		}
+
+		claims, err := auth.ParseToken(token, config.JWTSecret())
+		if err != nil {
+			abort(c, ErrInvalidToken)
+			return
+		}
+
+		ctx := c.Request.Context()
+		hostSchool, fromHost := requestctx.School(ctx)
+		fromHost = fromHost && c.GetBool(tenantFromHostKey)
+
+		switch {
+		case claims.SchoolID != 0:
+			if fromHost && hostSchool != claims.SchoolID {
+				abort(c, ErrWrongSchool)
+				return
+			}
+			ctx = requestctx.WithSchool(ctx, claims.SchoolID)
+		case claims.IsTrustAdmin() && !fromHost:
+			// Trust-level administrators see every school unless the
+			// subdomain names one.
+			ctx = requestctx.WithSchool(ctx, 0)
+		}
+
+		c.Set(claimsKey, claims)
+		c.Request = c.Request.WithContext(requestctx.WithActor(ctx, model.Actor{
+			TeacherID: claims.TeacherID,
+			Role:      claims.Role,
+		}))
+		c.Next()
+	}
+}
+
+func bearerToken(c *gin.Context) string {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" {
+		token = c.Query("access_token")
+	}
+	return token
+}
+
+// RequireRole must run after AuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := CurrentClaims(c)
+		if !ok || !claims.HasRole(roles...) {
+			abort(c, ErrInsufficientPermissions)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireTrustAdmin must run after AuthMiddleware. It admits only
+// administrators whose token is not tied to one school.
+func RequireTrustAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := CurrentClaims(c)
+		if !ok || !claims.IsTrustAdmin() {
+			abort(c, ErrInsufficientPermissions)
+			return
+		}
+		c.Next()
+	}
+}
+
+// CurrentClaims returns the claims stored by AuthMiddleware.
+func CurrentClaims(c *gin.Context) (*auth.Claims, bool) {
+	value, ok := c.Get(claimsKey)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := value.(*auth.Claims)
+	return claims, ok
+}
@@ -0,0 +1,198 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/requestctx"
+	"school-teacher-management/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+const problemContentType = "application/problem+json"
+
+var kindStatus = map[apperror.Kind]int{
+	apperror.KindValidation:       http.StatusBadRequest,
+	apperror.KindUnauthorized:     http.StatusUnauthorized,
+	apperror.KindForbidden:        http.StatusForbidden,
+	apperror.KindNotFound:         http.StatusNotFound,
+	apperror.KindConflict:         http.StatusConflict,
+	apperror.KindRule:             http.StatusUnprocessableEntity,
+	apperror.KindLocked:           http.StatusLocked,
+	apperror.KindPrecondition:     http.StatusPreconditionFailed,
+	apperror.KindUnsupportedMedia: http.StatusUnsupportedMediaType,
+}
+
+// ErrorMiddleware turns the last error a handler or middleware added with
+// c.Error into an RFC 7807 problem response. Errors from binding the
+// request (gin.ErrorTypeBind) become validation problems listing the
+// invalid fields; errors other than apperror ones are logged and answered
+// with a bare 500, so database messages never reach the client.
+func ErrorMiddleware() gin.HandlerFunc {
+	// Report fields by their JSON names rather than the Go ones.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonFieldName)
+	}
+
+	return func(c *gin.Context) {
+		c.Next()
+		writeProblem(c)
+	}
+}
+
+// writeProblem answers with the last error reported, unless there is none
+// or a response was already written. Middleware that needs the final
+// response before ErrorMiddleware returns calls it itself.
+func writeProblem(c *gin.Context) {
+	last := c.Errors.Last()
+	if last == nil || c.Writer.Written() {
+		return
+	}
+
+	problem := newProblem(last)
+	problem.Instance = c.Request.URL.Path
+	problem.RequestID = requestctx.RequestID(c.Request.Context())
+	problem.TraceID = tracing.TraceID(c.Request.Context())
+
+	if problem.Status == http.StatusInternalServerError {
+		slog.ErrorContext(c.Request.Context(), "request failed", "error", last.Err)
+	}
+
+	c.Header("Content-Type", problemContentType)
+	c.JSON(problem.Status, problem)
+}
+
+// abort stops the chain; ErrorMiddleware answers with err.
+func abort(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
+
+func newProblem(ginErr *gin.Error) model.Problem {
+	err := ginErr.Err
+
+	if ginErr.IsType(gin.ErrorTypeBind) {
+		return bindProblem(err)
+	}
+
+	// Services may wrap their errors with context, so the detail is the
+	// whole message.
+	if appErr, ok := apperror.As(err); ok {
+		return problem(kindStatus[appErr.Kind], appErr.Code, err.Error(), appErr.Fields)
+	}
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return problem(http.StatusNotFound, "not_found", "resource not found", nil)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return problem(http.StatusConflict, "duplicate", "a record with the same unique values already exists", nil)
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return problem(http.StatusConflict, "invalid_reference", "the record refers to a missing record, or is still referred to", nil)
+	}
+
+	return problem(http.StatusInternalServerError, "internal_error", "an unexpected error occurred", nil)
+}
+
+// bindProblem describes why the request body or query could not be bound.
+func bindProblem(err error) model.Problem {
+	var invalid validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+
+	switch {
+	case errors.As(err, &invalid):
+		fields := make([]model.FieldError, len(invalid))
+		for i, fe := range invalid {
+			fields[i] = model.FieldError{
+				Field:   fieldPath(fe),
+				Code:    fe.Tag(),
+				Message: validationMessage(fe),
+			}
+		}
+		return problem(http.StatusBadRequest, "validation_failed", "request has invalid fields", fields)
+	case errors.As(err, &typeErr):
+		return problem(http.StatusBadRequest, "validation_failed", "request has invalid fields", []model.FieldError{{
+			Field:   typeErr.Field,
+			Code:    "type",
+			Message: "must be a " + typeErr.Type.String(),
+		}})
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return problem(http.StatusBadRequest, "malformed_body", "request body is not valid JSON", nil)
+	}
+
+	return problem(http.StatusBadRequest, "invalid_request", err.Error(), nil)
+}
+
+func problem(status int, code, detail string, fields []model.FieldError) model.Problem {
+	return model.Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+		Errors: fields,
+	}
+}
+
+// fieldPath drops the struct name from the validator's namespace, leaving
+// e.g. "punches[0].status".
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	case "min", "gte":
+		return "must be at least " + fe.Param() + sizeUnit(fe.Kind())
+	case "max", "lte":
+		return "must be at most " + fe.Param() + sizeUnit(fe.Kind())
+	case "numeric":
+		return "must contain only digits"
+	case "url":
+		return "must be a valid URL"
+	}
+	return "is invalid"
+}
+
+// sizeUnit says what min and max count for strings and lists.
+func sizeUnit(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items"
+	}
+	return ""
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,160 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/auth"
+	"school-teacher-management/internal/config"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/requestctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// ReplayedHeader marks a response replayed for a retried key.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKey = 255
+)
+
+var errInvalidIdempotencyKey = apperror.Field(IdempotencyKeyHeader, "max", "must be at most 255 characters")
+
+// IdempotencyStore keeps the responses to keyed requests; see
+// service.IdempotencyService.
+type IdempotencyStore interface {
+	Begin(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *model.IdempotencyRecord) error
+	Abandon(ctx context.Context, record *model.IdempotencyRecord) error
+}
+
+// IdempotencyMiddleware honours the Idempotency-Key header on POST
+// requests. The first response to a key is stored and replayed to retries
+// with the same body; reusing the key for another body is refused. Server
+// errors are not stored, so their retries run again.
+//
+// Keys are scoped to the caller: its school, the identity in its bearer
+// token and its credentialHeaders, such as terminal keys. It must run
+// after TenantMiddleware.
+func IdempotencyMiddleware(store IdempotencyStore, credentialHeaders ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKey {
+			abort(c, errInvalidIdempotencyKey)
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record := &model.IdempotencyRecord{
+			Caller:      digest([]byte(idempotencyCaller(c, credentialHeaders))),
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			Key:         key,
+			RequestHash: digest(body),
+		}
+
+		ctx := c.Request.Context()
+		stored, err := store.Begin(ctx, record)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+		if stored != nil {
+			c.Header(ReplayedHeader, "true")
+			c.Data(stored.StatusCode, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		// A panic leaves the key free for the retry.
+		completed := false
+		defer func() {
+			if !completed {
+				if err := store.Abandon(context.WithoutCancel(ctx), record); err != nil {
+					slog.ErrorContext(ctx, "idempotency abandon", "error", err)
+				}
+			}
+		}()
+
+		c.Next()
+		writeProblem(c)
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		record.StatusCode = recorder.Status()
+		record.ContentType = recorder.Header().Get("Content-Type")
+		record.Body = recorder.body.Bytes()
+		if err := store.Complete(context.WithoutCancel(ctx), record); err != nil {
+			slog.ErrorContext(ctx, "idempotency complete", "error", err)
+			return
+		}
+		completed = true
+	}
+}
+
+// idempotencyCaller identifies who sent the request, so one caller's key
+// never replays another's response. Callers with a valid bearer token are
+// known by its claims, so a refreshed token keeps its keys and an expired
+// or forged one never matches them. Anonymous callers, such as terminals,
+// are known by their credential headers.
+func idempotencyCaller(c *gin.Context, credentialHeaders []string) string {
+	schoolID, _ := requestctx.School(c.Request.Context())
+
+	var parts []string
+	if claims, err := auth.ParseToken(bearerToken(c), config.JWTSecret()); err == nil {
+		if claims.SchoolID != 0 {
+			schoolID = claims.SchoolID
+		}
+		parts = append(parts, claims.Role, fmt.Sprint(claims.TeacherID), claims.Subject, claims.Department)
+	}
+	for _, header := range credentialHeaders {
+		parts = append(parts, c.GetHeader(header))
+	}
+	return fmt.Sprint(schoolID) + "\n" + strings.Join(parts, "\n")
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// responseRecorder keeps a copy of the response body.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"context"
+
+	"school-teacher-management/internal/requestctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+const tenantFromHostKey = "tenant.from_host"
+
+// SchoolResolver returns the school a request host names, and whether the
+// host named one rather than falling back to the default school.
+type SchoolResolver func(ctx context.Context, host string) (schoolID uint, fromHost bool, err error)
+
+// TenantMiddleware scopes every request to the school named by the
+// subdomain, or to the default school. AuthMiddleware then applies the
+// token's school.
+func TenantMiddleware(resolve SchoolResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schoolID, fromHost, err := resolve(c.Request.Context(), c.Request.Host)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+
+		c.Set(tenantFromHostKey, fromHost)
+		c.Request = c.Request.WithContext(requestctx.WithSchool(c.Request.Context(), schoolID))
+		c.Next()
+	}
+}
@@ -2,16 +2,59 @@ package model
 
 import "time"
 
+// Attendance is a teacher's record of one day. A teacher has one record per
+// day, kept so by the unique index AttendanceRepository.EnsureUniqueDay
+// installs.
 type Attendance struct {
-	ID        uint       `gorm:"primaryKey" json:"id"`
-	TeacherID uint       `json:"teacher_id" binding:"required"`
-	Date      time.Time  `gorm:"type:date" json:"date"`
-	Status    string     `json:"status" binding:"required"`
-	CheckIn   *time.Time `json:"check_in,omitempty"`
-	CheckOut  *time.Time `json:"check_out,omitempty"`
-	Teacher   Teacher    `gorm:"foreignKey:TeacherID" json:"teacher"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
+	ID        uint             `gorm:"primaryKey" json:"id"`
+	SchoolID  uint             `gorm:"index" json:"school_id"`
+	TeacherID uint             `json:"teacher_id" binding:"required"`
+	Date      time.Time        `gorm:"type:date" json:"date"`
+	Status    AttendanceStatus `json:"status" binding:"required,oneof=present absent leave holiday half_day on_duty work_from_home" example:"present"`
+	CheckIn   *time.Time       `json:"check_in,omitempty"`
+	CheckOut  *time.Time       `json:"check_out,omitempty"`
+	// The kiosk and terminal IDs are set for punches scanned at a kiosk or
+	// entered on a shared terminal.
+	CheckInKioskID     *uint `json:"check_in_kiosk_id,omitempty"`
+	CheckOutKioskID    *uint `json:"check_out_kiosk_id,omitempty"`
+	CheckInTerminalID  *uint `json:"check_in_terminal_id,omitempty"`
+	CheckOutTerminalID *uint `json:"check_out_terminal_id,omitempty"`
+	// Reported device positions; unset for kiosk and terminal punches.
+	CheckInLocation  *PunchLocation `gorm:"embedded;embeddedPrefix:check_in_" json:"check_in_location,omitempty"`
+	CheckOutLocation *PunchLocation `gorm:"embedded;embeddedPrefix:check_out_" json:"check_out_location,omitempty"`
+	// Client addresses of the punches; OffNetwork marks a direct punch from
+	// outside every campus network.
+	CheckInIP          string `json:"check_in_ip,omitempty"`
+	CheckOutIP         string `json:"check_out_ip,omitempty"`
+	CheckInOffNetwork  bool   `json:"check_in_off_network,omitempty"`
+	CheckOutOffNetwork bool   `json:"check_out_off_network,omitempty"`
+	// Devices the punches were submitted from; DeviceUnapproved marks a
+	// punch from a device the teacher is not approved to use.
+	CheckInDeviceID          *uint `gorm:"index" json:"check_in_device_id,omitempty"`
+	CheckOutDeviceID         *uint `gorm:"index" json:"check_out_device_id,omitempty"`
+	CheckInDeviceUnapproved  bool  `json:"check_in_device_unapproved,omitempty"`
+	CheckOutDeviceUnapproved bool  `json:"check_out_device_unapproved,omitempty"`
+	// Campuses the punches were made at.
+	CheckInCampusID  *uint `gorm:"index" json:"check_in_campus_id,omitempty"`
+	CheckOutCampusID *uint `gorm:"index" json:"check_out_campus_id,omitempty"`
+	// Offline marks punches queued on a device and synced later; their
+	// times come from the device clock.
+	CheckInOffline  bool `json:"check_in_offline,omitempty"`
+	CheckOutOffline bool `json:"check_out_offline,omitempty"`
+	// Version is bumped by every update and served as the ETag.
+	Version   uint      `gorm:"not null;default:1" json:"version"`
+	Teacher   Teacher   `gorm:"foreignKey:TeacherID" json:"teacher"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DuplicateDay is a teacher's day with more than one record, left by
+// concurrent check-ins made before one record per day was enforced. IDs
+// lists the records.
+type DuplicateDay struct {
+	TeacherID uint
+	Date      time.Time
+	IDs       string
 }
 
 type AttendanceDTO struct {
@@ -20,6 +63,12 @@ type AttendanceDTO struct {
 	CheckIn     *time.Time `json:"checkIn"`
 	CheckOut    *time.Time `json:"checkOut"`
 	Date        string     `json:"date"`
+	// Devices the check-in and check-out were submitted from.
+	CheckInDeviceID  *uint `json:"checkInDeviceId,omitempty"`
+	CheckOutDeviceID *uint `json:"checkOutDeviceId,omitempty"`
+	// Campuses the check-in and check-out were made at.
+	CheckInCampusID  *uint `json:"checkInCampusId,omitempty"`
+	CheckOutCampusID *uint `json:"checkOutCampusId,omitempty"`
 }
 
 type AttendanceResponse struct {
@@ -28,6 +77,22 @@ type AttendanceResponse struct {
 }
 
 type AttendanceRequest struct {
-	TeacherID uint   `json:"teacher_id" binding:"required"`
-	Status    string `json:"status" binding:"required"`
+	// TeacherID is taken from the bearer token, never from the client.
+	TeacherID uint `json:"-"`
+	// Status is the punch; the day's status follows from it.
+	Status PunchType `json:"status" binding:"required,oneof=checkIn checkOut" example:"checkIn"`
+	// Optional device position, checked against the campus geofences.
+	Latitude  *float64 `json:"latitude" example:"12.9716"`
+	Longitude *float64 `json:"longitude" example:"77.5946"`
+	Accuracy  *float64 `json:"accuracy" example:"15"`
+	// Campus the teacher is punching at. A matching geofence takes
+	// precedence; without either the teacher's home campus is recorded.
+	CampusID *uint `json:"campus_id"`
+	// KioskID and TerminalID are set by the kiosk and terminal check-ins,
+	// which also put their own campus in CampusID, and ClientIP and
+	// DeviceIdentifier by the handler, never by the client.
+	KioskID          *uint  `json:"-"`
+	TerminalID       *uint  `json:"-"`
+	ClientIP         string `json:"-"`
+	DeviceIdentifier string `json:"-"`
 }
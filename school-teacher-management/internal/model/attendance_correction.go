@@ -0,0 +1,68 @@
+package model
+
+import "time"
+
+const (
+	CorrectionPending  = "pending"
+	CorrectionApproved = "approved"
+	CorrectionRejected = "rejected"
+)
+
+const (
+	ChangeSourceCorrection  = "correction"
+	ChangeSourceAdminEdit   = "admin_edit"
+	ChangeSourceAdminDelete = "admin_delete"
+)
+
+// Actor identifies who made a change. TeacherID is 0 for administrators
+// who are not teachers.
+type Actor struct {
+	TeacherID uint   `json:"teacher_id,omitempty"`
+	Role      string `json:"role"`
+}
+
+// AttendanceCorrection is a teacher's request to fix a missed or wrong punch.
+type AttendanceCorrection struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	TeacherID    uint       `gorm:"not null;index" json:"teacher_id"`
+	Date         time.Time  `gorm:"type:date;not null" json:"date"`
+	AttendanceID *uint      `json:"attendance_id,omitempty"`
+	CheckIn      *time.Time `json:"check_in,omitempty"`
+	CheckOut     *time.Time `json:"check_out,omitempty"`
+	Reason       string     `gorm:"not null" json:"reason"`
+	Status       string     `gorm:"not null;index" json:"status"`
+	RequestedBy  Actor      `gorm:"embedded;embeddedPrefix:requested_by_" json:"requested_by"`
+	DecidedBy    *Actor     `gorm:"embedded;embeddedPrefix:decided_by_" json:"decided_by,omitempty"`
+	DecisionNote string     `json:"decision_note,omitempty"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"`
+	Teacher      Teacher    `gorm:"foreignKey:TeacherID" json:"teacher"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type AttendanceCorrectionInput struct {
+	TeacherID uint       `json:"teacher_id"`
+	Date      string     `json:"date" binding:"required" example:"2026-01-15"`
+	CheckIn   *time.Time `json:"check_in"`
+	CheckOut  *time.Time `json:"check_out"`
+	Reason    string     `json:"reason" binding:"required"`
+}
+
+type CorrectionDecisionInput struct {
+	Note string `json:"note"`
+}
+
+// AttendanceChange records the before and after state of an attendance row
+// for every applied correction and every direct admin edit or delete.
+type AttendanceChange struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	SchoolID     uint      `gorm:"index" json:"school_id"`
+	AttendanceID uint      `gorm:"not null;index" json:"attendance_id"`
+	CorrectionID *uint     `json:"correction_id,omitempty"`
+	Source       string    `gorm:"not null" json:"source"`
+	Actor        Actor     `gorm:"embedded;embeddedPrefix:actor_" json:"actor"`
+	Before       JSON      `json:"before" swaggertype:"object"`
+	After        JSON      `json:"after" swaggertype:"object"`
+	Reason       string    `json:"reason,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
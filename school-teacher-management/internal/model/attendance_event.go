@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+const (
+	EventCheckIn    = "attendance.check_in"
+	EventCheckOut   = "attendance.check_out"
+	EventLeave      = "attendance.leave"
+	EventCorrection = "attendance.correction"
+	// EventAbsent is recorded for each teacher who has not checked in by
+	// the late alert. It has no attendance ID when the day has no record.
+	EventAbsent = "attendance.absent"
+
+	EventAttendanceDeleted = "attendance.deleted"
+)
+
+type AttendanceEvent struct {
+	Type         string           `json:"type"`
+	AttendanceID uint             `json:"attendance_id"`
+	SchoolID     uint             `json:"school_id"`
+	TeacherID    uint             `json:"teacher_id"`
+	TeacherName  string           `json:"teacher_name"`
+	Department   string           `json:"department"`
+	Status       AttendanceStatus `json:"status"`
+	Date         string           `json:"date"`
+	OccurredAt   time.Time        `json:"occurred_at"`
+}
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+const (
+	PeriodOpen   = "open"
+	PeriodLocked = "locked"
+)
+
+// AttendancePeriod is one calendar month of a school's register. A missing
+// row means the month has never been closed and is open.
+type AttendancePeriod struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	SchoolID   uint       `gorm:"uniqueIndex:idx_school_attendance_period" json:"school_id"`
+	Year       int        `gorm:"not null;uniqueIndex:idx_school_attendance_period" json:"year"`
+	Month      int        `gorm:"not null;uniqueIndex:idx_school_attendance_period" json:"month"`
+	Status     string     `gorm:"not null" json:"status"`
+	LockedBy   *Actor     `gorm:"embedded;embeddedPrefix:locked_by_" json:"locked_by,omitempty"`
+	LockedAt   *time.Time `json:"locked_at,omitempty"`
+	ReopenedBy *Actor     `gorm:"embedded;embeddedPrefix:reopened_by_" json:"reopened_by,omitempty"`
+	ReopenedAt *time.Time `json:"reopened_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// AttendanceSnapshot is the register of a period as it stood when the
+// period was closed. Each close adds a new snapshot.
+type AttendanceSnapshot struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	PeriodID  uint            `gorm:"not null;index" json:"period_id"`
+	TakenBy   Actor           `gorm:"embedded;embeddedPrefix:taken_by_" json:"taken_by"`
+	Register  []RegisterEntry `gorm:"type:jsonb;serializer:json" json:"register"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type RegisterEntry struct {
+	AttendanceID uint             `json:"attendance_id"`
+	TeacherID    uint             `json:"teacher_id"`
+	TeacherName  string           `json:"teacher_name"`
+	Date         string           `json:"date"`
+	Status       AttendanceStatus `json:"status"`
+	CheckIn      *time.Time       `json:"check_in,omitempty"`
+	CheckOut     *time.Time       `json:"check_out,omitempty"`
+}
+
+type RegisterChange struct {
+	Before RegisterEntry `json:"before"`
+	After  RegisterEntry `json:"after"`
+}
+
+// PeriodComparison lists how the register differs today from the latest
+// snapshot of the period.
+type PeriodComparison struct {
+	Year       int              `json:"year"`
+	Month      int              `json:"month"`
+	SnapshotID uint             `json:"snapshot_id"`
+	TakenAt    time.Time        `json:"taken_at"`
+	Added      []RegisterEntry  `json:"added"`
+	Removed    []RegisterEntry  `json:"removed"`
+	Changed    []RegisterChange `json:"changed"`
+}
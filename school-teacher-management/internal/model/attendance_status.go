@@ -0,0 +1,65 @@
+package model
+
+// AttendanceStatus says what a teacher's day was. It is kept apart from
+// the punches: a check-in makes an absent day present, but a check-out
+// leaves the status alone.
+type AttendanceStatus string
+
+const (
+	StatusPresent      AttendanceStatus = "present"
+	StatusAbsent       AttendanceStatus = "absent"
+	StatusLeave        AttendanceStatus = "leave"
+	StatusHoliday      AttendanceStatus = "holiday"
+	StatusHalfDay      AttendanceStatus = "half_day"
+	StatusOnDuty       AttendanceStatus = "on_duty"
+	StatusWorkFromHome AttendanceStatus = "work_from_home"
+)
+
+// AttendanceStatuses lists every valid status; the attendances table has a
+// check constraint built from it.
+var AttendanceStatuses = []AttendanceStatus{
+	StatusPresent,
+	StatusAbsent,
+	StatusLeave,
+	StatusHoliday,
+	StatusHalfDay,
+	StatusOnDuty,
+	StatusWorkFromHome,
+}
+
+func (s AttendanceStatus) Valid() bool {
+	for _, status := range AttendanceStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Worked reports whether the teacher worked that day, at school or away,
+// and so may have punches.
+func (s AttendanceStatus) Worked() bool {
+	switch s {
+	case StatusPresent, StatusHalfDay, StatusOnDuty, StatusWorkFromHome:
+		return true
+	}
+	return false
+}
+
+// PunchType is the event a check-in or check-out request records.
+type PunchType string
+
+const (
+	PunchCheckIn  PunchType = "checkIn"
+	PunchCheckOut PunchType = "checkOut"
+)
+
+// UpgradeStatus maps the punch names stored as the status before statuses
+// and punches were separated to present; other values are returned as
+// they are.
+func UpgradeStatus(s AttendanceStatus) AttendanceStatus {
+	if s == AttendanceStatus(PunchCheckIn) || s == AttendanceStatus(PunchCheckOut) {
+		return StatusPresent
+	}
+	return s
+}
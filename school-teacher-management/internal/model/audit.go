@@ -0,0 +1,79 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+const (
+	AuditEntityTeacher    = "teacher"
+	AuditEntityAttendance = "attendance"
+	AuditEntityPeriod     = "attendance_period"
+)
+
+// AuditLog is append-only: a database trigger rejects UPDATE, DELETE and
+// TRUNCATE, and each row's Hash covers its content and the previous row's
+// Hash, so editing or removing a row breaks the chain. The chain spans every
+// school; SchoolID scopes reads only.
+type AuditLog struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	SchoolID   uint      `gorm:"index" json:"school_id"`
+	Actor      Actor     `gorm:"embedded;embeddedPrefix:actor_" json:"actor"`
+	Action     string    `gorm:"not null" json:"action"`
+	EntityType string    `gorm:"not null;index:idx_audit_entity" json:"entity_type"`
+	EntityID   uint      `gorm:"not null;index:idx_audit_entity" json:"entity_id"`
+	Before     JSON      `gorm:"type:text" json:"before" swaggertype:"object"`
+	After      JSON      `gorm:"type:text" json:"after" swaggertype:"object"`
+	RequestID  string    `json:"request_id,omitempty"`
+	CreatedAt  time.Time `gorm:"not null" json:"created_at"`
+	PrevHash   string    `gorm:"not null" json:"prev_hash"`
+	Hash       string    `gorm:"not null;uniqueIndex" json:"hash"`
+}
+
+// ComputeHash hashes everything but ID and Hash itself. Before and After are
+// stored as text rather than jsonb so they read back byte for byte. Entries
+// written before SchoolID existed have none, and hash as they did then.
+func (a *AuditLog) ComputeHash() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%d\n%s\n%s\n%s\n%d\n%s\n%s\n%s\n%s",
+		a.PrevHash,
+		a.Actor.TeacherID,
+		a.Actor.Role,
+		a.Action,
+		a.EntityType,
+		a.EntityID,
+		a.Before,
+		a.After,
+		a.RequestID,
+		a.CreatedAt.UTC().Format(time.RFC3339Nano),
+	)
+	if a.SchoolID != 0 {
+		fmt.Fprintf(h, "\n%d", a.SchoolID)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type AuditFilter struct {
+	EntityType string
+	EntityID   uint
+	ActorID    uint
+	ActorRole  string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+}
+
+type AuditVerification struct {
+	Valid        bool   `json:"valid"`
+	Checked      int    `json:"checked"`
+	FirstInvalid *uint  `json:"first_invalid_id,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Campus is one site of a school. Teachers have a home campus, punches
+// record the campus they were made at, and a campus may have its own
+// working hours and holidays on top of the school's.
+type Campus struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	SchoolID  uint      `gorm:"not null;index" json:"school_id"`
+	Name      string    `gorm:"not null" json:"name"`
+	Address   string    `json:"address,omitempty"`
+	WorkStart string    `json:"work_start,omitempty"`
+	WorkEnd   string    `json:"work_end,omitempty"`
+	Active    bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CampusInput struct {
+	Name      string `json:"name" binding:"required" example:"Junior campus"`
+	Address   string `json:"address"`
+	WorkStart string `json:"work_start" example:"08:00"`
+	WorkEnd   string `json:"work_end" example:"15:00"`
+	Active    *bool  `json:"active"`
+}
+
+// WorkDay returns when the working day starts and ends at the campus: its
+// own hours, else the school's, else start and end. Either may be nil.
+func WorkDay(school *School, campus *Campus, start, end time.Duration) (time.Duration, time.Duration) {
+	if school != nil {
+		start = parseClock(school.WorkStart, start)
+		end = parseClock(school.WorkEnd, end)
+	}
+	if campus != nil {
+		start = parseClock(campus.WorkStart, start)
+		end = parseClock(campus.WorkEnd, end)
+	}
+	return start, end
+}
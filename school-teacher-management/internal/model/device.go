@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+const (
+	BindingPending  = "pending"
+	BindingApproved = "approved"
+	BindingRejected = "rejected"
+	BindingRevoked  = "revoked"
+)
+
+// Device is a phone, tablet or browser that submits punches, identified by
+// the X-Device-ID its app sends. Teacher-owned devices are usable once the
+// owner's binding is approved; shared devices, registered by an
+// administrator, are usable by every teacher.
+type Device struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	SchoolID   uint       `gorm:"uniqueIndex:idx_school_device" json:"school_id"`
+	Identifier string     `gorm:"not null;uniqueIndex:idx_school_device" json:"identifier"`
+	Name       string     `json:"name"`
+	Platform   string     `json:"platform,omitempty"`
+	Shared     bool       `gorm:"not null;default:false" json:"shared"`
+	OwnerID    *uint      `gorm:"index" json:"owner_id,omitempty"`
+	Active     bool       `gorm:"not null;default:true" json:"active"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type DeviceInput struct {
+	Identifier string `json:"identifier" binding:"required"`
+	Name       string `json:"name" binding:"required"`
+	Platform   string `json:"platform"`
+	// Shared may only be set by an administrator.
+	Shared bool `json:"shared"`
+}
+
+// DeviceBinding allows a teacher to punch from a device. A binding is
+// requested when a teacher registers a device or first punches from an
+// unknown one, and waits for approval.
+type DeviceBinding struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	TeacherID uint   `gorm:"not null;uniqueIndex:idx_device_binding" json:"teacher_id"`
+	DeviceID  uint   `gorm:"not null;uniqueIndex:idx_device_binding" json:"device_id"`
+	Status    string `gorm:"not null;index" json:"status"`
+	// Rejecting an approved binding revokes it.
+	DecidedBy *Actor     `gorm:"embedded;embeddedPrefix:decided_by_" json:"decided_by,omitempty"`
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+	Teacher   Teacher    `gorm:"foreignKey:TeacherID" json:"teacher"`
+	Device    Device     `gorm:"foreignKey:DeviceID" json:"device"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"school-teacher-management/internal/geo"
+	"time"
+)
+
+const (
+	GeofenceRadius  = "radius"
+	GeofencePolygon = "polygon"
+)
+
+// Geofence is the area of one campus: a circle around Center, or a polygon.
+// Punches inside it are recorded at CampusID when set.
+type Geofence struct {
+	ID           uint        `gorm:"primaryKey" json:"id"`
+	SchoolID     uint        `gorm:"index" json:"school_id"`
+	Name         string      `gorm:"not null" json:"name"`
+	CampusID     *uint       `json:"campus_id,omitempty"`
+	Type         string      `gorm:"not null" json:"type"`
+	Center       *geo.Point  `gorm:"type:jsonb;serializer:json" json:"center,omitempty"`
+	RadiusMeters float64     `json:"radius_meters,omitempty"`
+	Polygon      []geo.Point `gorm:"type:jsonb;serializer:json" json:"polygon,omitempty"`
+	Active       bool        `gorm:"not null;default:true" json:"active"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+func (g *Geofence) Contains(p geo.Point) bool {
+	switch g.Type {
+	case GeofenceRadius:
+		return g.Center != nil && geo.Distance(*g.Center, p) <= g.RadiusMeters
+	case GeofencePolygon:
+		return geo.InPolygon(p, g.Polygon)
+	}
+	return false
+}
+
+type GeofenceInput struct {
+	Name         string      `json:"name" binding:"required"`
+	CampusID     *uint       `json:"campus_id"`
+	Type         string      `json:"type" binding:"required" example:"radius"`
+	Center       *geo.Point  `json:"center"`
+	RadiusMeters float64     `json:"radius_meters"`
+	Polygon      []geo.Point `json:"polygon"`
+	Active       *bool       `json:"active"`
+}
+
+// PunchLocation is where the device reported itself at a punch. Geofence
+// names the campus it fell in; OutOfFence is set when it matched none, or
+// was missing or too imprecise while geofencing is on.
+type PunchLocation struct {
+	Latitude   *float64 `json:"latitude,omitempty"`
+	Longitude  *float64 `json:"longitude,omitempty"`
+	Accuracy   *float64 `json:"accuracy,omitempty"`
+	Geofence   string   `json:"geofence,omitempty"`
+	OutOfFence bool     `json:"out_of_fence"`
+}
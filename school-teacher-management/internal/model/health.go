@@ -0,0 +1,13 @@
+package model
+
+const (
+	HealthOK          = "ok"
+	HealthUnavailable = "unavailable"
+)
+
+// HealthReport answers the health probes. Checks maps each dependency the
+// readiness probe looks at to "ok" or to what is wrong with it.
+type HealthReport struct {
+	Status string            `json:"status" example:"ok"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
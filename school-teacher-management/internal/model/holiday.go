@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Holiday is a closure of one school, or of one of its campuses, on a
+// weekday. Work on a holiday is overtime and the day is not a working day
+// for payroll. CampusID is 0 for a closure of the whole school.
+type Holiday struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	SchoolID  uint      `gorm:"uniqueIndex:idx_school_campus_holiday" json:"school_id"`
+	CampusID  uint      `gorm:"not null;default:0;uniqueIndex:idx_school_campus_holiday" json:"campus_id,omitempty"`
+	Date      time.Time `gorm:"type:date;not null;uniqueIndex:idx_school_campus_holiday" json:"date"`
+	Name      string    `gorm:"not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type HolidayInput struct {
+	Date string `json:"date" binding:"required" example:"2026-12-25"`
+	Name string `json:"name" binding:"required"`
+	// Only this campus is closed; the whole school when unset.
+	CampusID *uint `json:"campus_id"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// IdempotencyRecord keeps the response to a POST sent with an
+// Idempotency-Key, so a retry gets that response instead of repeating the
+// request. Keys are scoped to the caller, its school and the path. A
+// record without a status code is still being processed.
+type IdempotencyRecord struct {
+	ID     uint   `gorm:"primaryKey"`
+	Caller string `gorm:"not null;uniqueIndex:idx_idempotency_key"`
+	Method string `gorm:"not null;uniqueIndex:idx_idempotency_key"`
+	Path   string `gorm:"not null;uniqueIndex:idx_idempotency_key"`
+	Key    string `gorm:"not null;uniqueIndex:idx_idempotency_key"`
+	// RequestHash tells a retry from a different request reusing the key.
+	RequestHash string `gorm:"not null"`
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time `gorm:"not null;index"`
+	CreatedAt   time.Time
+}
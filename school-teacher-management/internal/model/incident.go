@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// Kinds of suspicious attendance patterns.
+const (
+	IncidentSharedDevice     = "shared_device"
+	IncidentSharedIP         = "shared_ip"
+	IncidentRepeatedTime     = "repeated_time"
+	IncidentImpossibleTravel = "impossible_travel"
+	IncidentHolidayCheckIn   = "holiday_check_in"
+)
+
+const (
+	IncidentOpen      = "open"
+	IncidentResolved  = "resolved"
+	IncidentDismissed = "dismissed"
+)
+
+// Incident is a suspicious attendance pattern flagged by the anomaly scan
+// for review. Related fields name the other teacher or record involved,
+// for example the colleague who punched from the same device. Fingerprint
+// identifies the finding, so rescanning does not flag it twice.
+type Incident struct {
+	ID                  uint       `gorm:"primaryKey" json:"id"`
+	Kind                string     `gorm:"not null;index" json:"kind"`
+	Fingerprint         string     `gorm:"not null;uniqueIndex" json:"-"`
+	TeacherID           uint       `gorm:"not null;index" json:"teacher_id"`
+	RelatedTeacherID    *uint      `json:"related_teacher_id,omitempty"`
+	AttendanceID        *uint      `json:"attendance_id,omitempty"`
+	RelatedAttendanceID *uint      `json:"related_attendance_id,omitempty"`
+	Date                time.Time  `gorm:"type:date;index" json:"date"`
+	Details             string     `json:"details"`
+	Status              string     `gorm:"not null;index" json:"status"`
+	ReviewedBy          *Actor     `gorm:"embedded;embeddedPrefix:reviewed_by_" json:"reviewed_by,omitempty"`
+	ReviewNote          string     `json:"review_note,omitempty"`
+	ReviewedAt          *time.Time `json:"reviewed_at,omitempty"`
+	Teacher             Teacher    `gorm:"foreignKey:TeacherID" json:"teacher"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type IncidentReviewInput struct {
+	Note string `json:"note"`
+}
+
+type IncidentFilter struct {
+	TeacherID  uint
+	Department string
+	Kind       string
+	Status     string
+	From       *time.Time
+	To         *time.Time
+}
+
+type IncidentScanResult struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Flagged int    `json:"flagged"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Kiosk is a registered check-in device. It authenticates with a key that
+// is shown once at registration and stored only as a hash.
+type Kiosk struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	SchoolID  uint      `gorm:"index" json:"school_id"`
+	Name      string    `gorm:"not null" json:"name"`
+	Location  string    `json:"location"`
+	CampusID  *uint     `json:"campus_id,omitempty"`
+	KeyHash   string    `gorm:"not null;uniqueIndex" json:"-"`
+	Active    bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type KioskInput struct {
+	Name     string `json:"name" binding:"required"`
+	Location string `json:"location"`
+	CampusID *uint  `json:"campus_id"`
+}
+
+// KioskRegistration carries the kiosk key; it cannot be retrieved again.
+type KioskRegistration struct {
+	Kiosk Kiosk  `json:"kiosk"`
+	Key   string `json:"key"`
+}
+
+// KioskToken is shown by the kiosk as a QR code until ExpiresAt.
+type KioskToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type KioskCheckInInput struct {
+	Token  string    `json:"token" binding:"required"`
+	Status PunchType `json:"status" binding:"required,oneof=checkIn checkOut" example:"checkIn"`
+}
@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+const (
+	LeavePending  = "pending"
+	LeaveApproved = "approved"
+	LeaveRejected = "rejected"
+)
+
+const (
+	LeaveGeneral = "general"
+	// LeaveCompOff is paid from compensatory-off credits when approved.
+	LeaveCompOff = "comp_off"
+)
+
+type LeaveRequest struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	TeacherID    uint       `gorm:"not null;index" json:"teacher_id"`
+	Type         string     `gorm:"not null;default:general" json:"type"`
+	StartDate    time.Time  `gorm:"type:date;not null" json:"start_date"`
+	EndDate      time.Time  `gorm:"type:date;not null" json:"end_date"`
+	Reason       string     `json:"reason"`
+	Status       string     `gorm:"not null;index" json:"status"`
+	DecidedBy    *uint      `json:"decided_by,omitempty"`
+	DecisionNote string     `json:"decision_note,omitempty"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"`
+	Teacher      Teacher    `gorm:"foreignKey:TeacherID" json:"teacher"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Days counts the calendar days the leave covers, inclusive.
+func (l *LeaveRequest) Days() int {
+	return int(l.EndDate.Sub(l.StartDate).Hours()/24) + 1
+}
+
+type LeaveRequestInput struct {
+	TeacherID uint   `json:"teacher_id"`
+	Type      string `json:"type" example:"general"`
+	StartDate string `json:"start_date" binding:"required" example:"2026-01-15"`
+	EndDate   string `json:"end_date" binding:"required" example:"2026-01-16"`
+	Reason    string `json:"reason" binding:"required"`
+}
+
+type LeaveDecisionInput struct {
+	Note string `json:"note"`
+}
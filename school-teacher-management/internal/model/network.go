@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// CampusNetwork is an address range of a campus network. Direct punches
+// from outside every range of the teacher's school are flagged or
+// rejected, per the school's network policy. SchoolID is the campus's
+// school.
+type CampusNetwork struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	SchoolID    uint      `gorm:"index" json:"school_id"`
+	CampusID    uint      `gorm:"index" json:"campus_id"`
+	Campus      *Campus   `gorm:"foreignKey:CampusID" json:"-"`
+	CIDR        string    `gorm:"not null" json:"cidr"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type CampusNetworkInput struct {
+	CampusID    uint   `json:"campus_id" binding:"required"`
+	CIDR        string `json:"cidr" binding:"required" example:"10.20.0.0/16"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+const (
+	NotifyLateAlert        = "late_alert"
+	NotifyCheckoutReminder = "checkout_reminder"
+	NotifyDailyDigest      = "daily_digest"
+)
+
+// NotificationPreference is stored per teacher; teachers without a row get
+// DefaultNotificationPreference.
+type NotificationPreference struct {
+	TeacherID         uint      `gorm:"primaryKey" json:"teacher_id"`
+	LateAlerts        bool      `json:"late_alerts"`
+	CheckoutReminders bool      `json:"checkout_reminders"`
+	LeaveUpdates      bool      `json:"leave_updates"`
+	DailyDigest       bool      `json:"daily_digest"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func DefaultNotificationPreference(teacherID uint) NotificationPreference {
+	return NotificationPreference{
+		TeacherID:         teacherID,
+		LateAlerts:        true,
+		CheckoutReminders: true,
+		LeaveUpdates:      true,
+	}
+}
+
+type NotificationPreferenceRequest struct {
+	LateAlerts        bool `json:"late_alerts"`
+	CheckoutReminders bool `json:"checkout_reminders"`
+	LeaveUpdates      bool `json:"leave_updates"`
+	DailyDigest       bool `json:"daily_digest"`
+}
+
+// NotificationLog records scheduled notifications already sent so that a
+// restart, or a second instance, does not send them again.
+type NotificationLog struct {
+	ID          uint      `gorm:"primaryKey"`
+	Kind        string    `gorm:"not null;uniqueIndex:idx_notification_once"`
+	RecipientID uint      `gorm:"not null;uniqueIndex:idx_notification_once"`
+	Date        time.Time `gorm:"type:date;not null;uniqueIndex:idx_notification_once"`
+	CreatedAt   time.Time
+}
@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+const (
+	OvertimePending  = "pending"
+	OvertimeApproved = "approved"
+	OvertimeRejected = "rejected"
+)
+
+const (
+	OvertimeAfterHours = "after_hours"
+	OvertimeWeekend    = "weekend"
+	OvertimeHoliday    = "holiday"
+)
+
+// Approved overtime is either paid through payroll or credited as
+// compensatory-off leave.
+const (
+	CompensationPaid    = "paid"
+	CompensationCompOff = "comp_off"
+)
+
+// OvertimeRecord is detected when a session runs past the scheduled end of
+// the day, or falls on a weekend or holiday, and waits for approval.
+type OvertimeRecord struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	TeacherID    uint       `gorm:"not null;index" json:"teacher_id"`
+	AttendanceID uint       `gorm:"not null;uniqueIndex" json:"attendance_id"`
+	Date         time.Time  `gorm:"type:date;not null" json:"date"`
+	Kind         string     `gorm:"not null" json:"kind"`
+	Minutes      int        `gorm:"not null" json:"minutes"`
+	Status       string     `gorm:"not null;index" json:"status"`
+	Compensation string     `json:"compensation,omitempty"`
+	DecidedBy    *Actor     `gorm:"embedded;embeddedPrefix:decided_by_" json:"decided_by,omitempty"`
+	DecisionNote string     `json:"decision_note,omitempty"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"`
+	Teacher      Teacher    `gorm:"foreignKey:TeacherID" json:"teacher"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type OvertimeDecisionInput struct {
+	// Compensation applies to approvals: comp_off (default) or paid.
+	Compensation string `json:"compensation" example:"comp_off"`
+	Note         string `json:"note"`
+}
+
+// CompOffCredit is compensatory-off leave earned by approved overtime, in
+// half-day steps, usable until ExpiresOn.
+type CompOffCredit struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TeacherID  uint      `gorm:"not null;index" json:"teacher_id"`
+	OvertimeID uint      `gorm:"not null;uniqueIndex" json:"overtime_id"`
+	Days       float64   `gorm:"not null" json:"days"`
+	UsedDays   float64   `gorm:"not null;default:0" json:"used_days"`
+	EarnedOn   time.Time `gorm:"type:date;not null" json:"earned_on"`
+	ExpiresOn  time.Time `gorm:"type:date;not null;index" json:"expires_on"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (c *CompOffCredit) Remaining() float64 {
+	return c.Days - c.UsedDays
+}
+
+type CompOffBalance struct {
+	TeacherID     uint            `json:"teacher_id"`
+	AvailableDays float64         `json:"available_days"`
+	Credits       []CompOffCredit `json:"credits"`
+}
@@ -0,0 +1,31 @@
+package model
+
+// PayrollLine is one teacher's attendance summary for a pay period.
+type PayrollLine struct {
+	TeacherID   uint   `json:"teacher_id"`
+	SchoolID    uint   `json:"school_id"`
+	CampusID    uint   `json:"campus_id,omitempty"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	Email       string `json:"email"`
+	Department  string `json:"department"`
+	WorkingDays int    `json:"working_days"`
+	PresentDays int    `json:"present_days"`
+	LeaveDays   int    `json:"leave_days"`
+	AbsentDays  int    `json:"absent_days"`
+	// HalfDays are present days worked short of the policy's hours;
+	// MarkedHalfDays have the half_day status and are paid half.
+	HalfDays       int     `json:"half_days"`
+	MarkedHalfDays int     `json:"marked_half_days"`
+	LOPDays        float64 `json:"lop_days"`
+	PayableDays    float64 `json:"payable_days"`
+	OvertimeHours  float64 `json:"overtime_hours"`
+	LateArrivals   int     `json:"late_arrivals"`
+	LateDeductions int     `json:"late_deductions"`
+}
+
+type PayrollReport struct {
+	From  string        `json:"from"`
+	To    string        `json:"to"`
+	Lines []PayrollLine `json:"lines"`
+}
@@ -0,0 +1,23 @@
+package model
+
+// Problem is the RFC 7807 body of every error response, served as
+// application/problem+json. Code is stable and meant for programs; Detail
+// is for people and may change.
+type Problem struct {
+	Type      string       `json:"type" example:"about:blank"`
+	Title     string       `json:"title" example:"Conflict"`
+	Status    int          `json:"status" example:"409"`
+	Detail    string       `json:"detail,omitempty" example:"attendance period is closed"`
+	Instance  string       `json:"instance,omitempty" example:"/api/v1/attendance"`
+	Code      string       `json:"code" example:"period_locked"`
+	RequestID string       `json:"request_id,omitempty"`
+	TraceID   string       `json:"trace_id,omitempty" example:"4bf92f3577b34da6a3ce929d0e0e4736"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes one invalid field of a request.
+type FieldError struct {
+	Field   string `json:"field" example:"email"`
+	Code    string `json:"code" example:"required"`
+	Message string `json:"message" example:"is required"`
+}
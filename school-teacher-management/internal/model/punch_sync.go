@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+// Outcomes of a synced offline punch.
+const (
+	PunchApplied   = "applied"
+	PunchIgnored   = "ignored"
+	PunchDuplicate = "duplicate"
+	PunchRejected  = "rejected"
+)
+
+// OfflinePunch records a punch received through the offline sync, keyed by
+// the idempotency key the device gave it, so a batch retried after a
+// dropped response is not applied twice. Rejected punches are not kept and
+// may be sent again.
+type OfflinePunch struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	TeacherID      uint      `gorm:"not null;uniqueIndex:idx_offline_punch_key" json:"teacher_id"`
+	IdempotencyKey string    `gorm:"not null;uniqueIndex:idx_offline_punch_key" json:"idempotency_key"`
+	Status         PunchType `gorm:"not null" json:"status"`
+	// PunchedAt is the device's timestamp, RecordedAt the time applied
+	// after clock-skew correction.
+	PunchedAt    time.Time `json:"punched_at"`
+	RecordedAt   time.Time `json:"recorded_at"`
+	DeviceID     *uint     `json:"device_id,omitempty"`
+	Result       string    `gorm:"not null" json:"result"`
+	Message      string    `json:"message,omitempty"`
+	AttendanceID *uint     `json:"attendance_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type QueuedPunch struct {
+	IdempotencyKey string    `json:"idempotency_key" binding:"required,max=100"`
+	Status         PunchType `json:"status" binding:"required,oneof=checkIn checkOut"`
+	PunchedAt      time.Time `json:"punched_at" binding:"required" example:"2026-10-18T08:55:00+05:30"`
+}
+
+type PunchSyncInput struct {
+	// SentAt is the device clock when the batch was sent; the difference
+	// to the server clock is the device's clock skew.
+	SentAt  time.Time     `json:"sent_at" binding:"required" example:"2026-10-18T10:02:00+05:30"`
+	Punches []QueuedPunch `json:"punches" binding:"required,min=1,max=500,dive"`
+}
+
+type PunchResult struct {
+	IdempotencyKey string     `json:"idempotency_key"`
+	Status         PunchType  `json:"status"`
+	Result         string     `json:"result"`
+	Message        string     `json:"message,omitempty"`
+	RecordedAt     *time.Time `json:"recorded_at,omitempty"`
+	AttendanceID   *uint      `json:"attendance_id,omitempty"`
+}
+
+type PunchSyncReport struct {
+	// ClockSkewSeconds is how far the device clock was behind the server
+	// (negative when ahead); Corrected reports whether punch times were
+	// shifted by it.
+	ClockSkewSeconds int64         `json:"clock_skew_seconds"`
+	Corrected        bool          `json:"corrected"`
+	Results          []PunchResult `json:"results"`
+}
@@ -0,0 +1,90 @@
+package model
+
+import "time"
+
+// School is one tenant of a multi-school deployment. Teachers, attendance,
+// holidays and attendance periods belong to a school, and requests are
+// scoped to the school of the caller's token or the subdomain. Timezone,
+// working hours and the geofence and network policies override the
+// deployment-wide settings when set.
+type School struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	Slug      string `gorm:"not null;uniqueIndex" json:"slug"`
+	Name      string `gorm:"not null" json:"name"`
+	Timezone  string `json:"timezone,omitempty"`
+	WorkStart string `json:"work_start,omitempty"`
+	WorkEnd   string `json:"work_end,omitempty"`
+	// GeofencePolicy and NetworkPolicy are off, flag or reject, as
+	// GEOFENCE_POLICY and NETWORK_POLICY.
+	GeofencePolicy string    `json:"geofence_policy,omitempty"`
+	NetworkPolicy  string    `json:"network_policy,omitempty"`
+	Active         bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Location returns the school's timezone, or the server's when unset.
+func (s *School) Location() *time.Location {
+	if s != nil && s.Timezone != "" {
+		if loc, err := time.LoadLocation(s.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// Today returns the school's current calendar date, in the form attendance
+// dates are stored. Schools without a timezone keep the UTC date.
+func (s *School) Today(now time.Time) time.Time {
+	if s == nil || s.Timezone == "" {
+		return now.Truncate(24 * time.Hour)
+	}
+	local := now.In(s.Location())
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// parseClock returns the time of day of an HH:MM value, or def when value
+// is unset.
+func parseClock(value string, def time.Duration) time.Duration {
+	if value == "" {
+		return def
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return def
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+}
+
+type SchoolInput struct {
+	Slug      string `json:"slug" binding:"required" example:"st-marys"`
+	Name      string `json:"name" binding:"required"`
+	Timezone  string `json:"timezone" example:"Europe/London"`
+	WorkStart string `json:"work_start" example:"08:30"`
+	WorkEnd   string `json:"work_end" example:"16:30"`
+	// Empty policies follow the deployment-wide settings.
+	GeofencePolicy string `json:"geofence_policy" binding:"omitempty,oneof=off flag reject" example:"reject"`
+	NetworkPolicy  string `json:"network_policy" binding:"omitempty,oneof=off flag reject" example:"flag"`
+	Active         *bool  `json:"active"`
+}
+
+// SchoolSummary is one school's line in the trust-level report.
+type SchoolSummary struct {
+	SchoolID     uint   `json:"school_id"`
+	Name         string `json:"name"`
+	Teachers     int    `json:"teachers"`
+	WorkingDays  int    `json:"working_days"`
+	PresentDays  int    `json:"present_days"`
+	LeaveDays    int    `json:"leave_days"`
+	AbsentDays   int    `json:"absent_days"`
+	LateArrivals int    `json:"late_arrivals"`
+	// Attendance is the percentage of present days among present and
+	// absent ones.
+	Attendance float64 `json:"attendance_rate"`
+}
+
+type TrustReport struct {
+	From    string          `json:"from"`
+	To      string          `json:"to"`
+	Schools []SchoolSummary `json:"schools"`
+}
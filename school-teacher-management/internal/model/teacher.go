@@ -2,21 +2,46 @@ package model
 
 import "time"
 
+const (
+	EventTeacherCreated = "teacher.created"
+	EventTeacherUpdated = "teacher.updated"
+)
+
 type Teacher struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Email     string    `json:"email"`
-	Subject   string    `json:"subject"`
-	Phone     string    `json:"phone"`
+	ID               uint    `gorm:"primaryKey" json:"id"`
+	SchoolID         uint    `gorm:"uniqueIndex:idx_school_employee_code" json:"school_id"`
+	FirstName        string  `json:"first_name"`
+	LastName         string  `json:"last_name"`
+	Email            string  `json:"email"`
+	EmployeeCode     *string `gorm:"uniqueIndex:idx_school_employee_code" json:"employee_code,omitempty"`
+	Subject          string  `json:"subject"`
+	Department       string  `json:"department"`
+	HeadOfDepartment bool    `json:"head_of_department"`
+	HomeCampusID     *uint   `gorm:"index" json:"home_campus_id,omitempty"`
+	Phone            string  `json:"phone"`
+	// Version is bumped by every update and served as the ETag.
+	Version   uint      `gorm:"not null;default:1" json:"version"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type TeacherRequest struct {
-	FirstName string `json:"first_name" binding:"required"`
-	LastName  string `json:"last_name" binding:"required"`
-	Email     string `json:"email" binding:"required,email"`
-	Subject   string `json:"subject"`
-	Phone     string `json:"phone"`
+	FirstName        string  `json:"first_name" binding:"required"`
+	LastName         string  `json:"last_name" binding:"required"`
+	Email            string  `json:"email" binding:"required,email"`
+	EmployeeCode     *string `json:"employee_code"`
+	Subject          string  `json:"subject"`
+	Department       string  `json:"department"`
+	HeadOfDepartment bool    `json:"head_of_department"`
+	HomeCampusID     *uint   `json:"home_campus_id"`
+	Phone            string  `json:"phone"`
+}
+
+// HomeCampus returns the ID of the teacher's home campus, or 0 when none is
+// set.
+func (t *Teacher) HomeCampus() uint {
+	if t.HomeCampusID == nil {
+		return 0
+	}
+	return *t.HomeCampusID
 }
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// Terminal is a shared check-in device, such as a staff-room tablet, where
+// teachers identify themselves with their employee code and PIN.
+type Terminal struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	SchoolID  uint      `gorm:"index" json:"school_id"`
+	Name      string    `gorm:"not null" json:"name"`
+	Location  string    `json:"location"`
+	CampusID  *uint     `json:"campus_id,omitempty"`
+	KeyHash   string    `gorm:"not null;uniqueIndex" json:"-"`
+	Active    bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TerminalInput struct {
+	Name     string `json:"name" binding:"required"`
+	Location string `json:"location"`
+	CampusID *uint  `json:"campus_id"`
+}
+
+// TerminalRegistration carries the terminal key; it cannot be retrieved
+// again.
+type TerminalRegistration struct {
+	Terminal Terminal `json:"terminal"`
+	Key      string   `json:"key"`
+}
+
+// TeacherPIN holds a teacher's terminal PIN as a bcrypt hash, and the
+// failed attempts since the last success.
+type TeacherPIN struct {
+	TeacherID      uint       `gorm:"primaryKey" json:"teacher_id"`
+	PINHash        string     `gorm:"not null" json:"-"`
+	FailedAttempts int        `gorm:"not null;default:0" json:"failed_attempts"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type PINInput struct {
+	PIN string `json:"pin" binding:"required,numeric,min=4,max=8" example:"4821"`
+}
+
+type TerminalCheckInInput struct {
+	EmployeeCode string    `json:"employee_code" binding:"required"`
+	PIN          string    `json:"pin" binding:"required"`
+	Status       PunchType `json:"status" binding:"required,oneof=checkIn checkOut" example:"checkIn"`
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryDead      = "dead"
+)
+
+// WebhookSubscription receives the events of its own school only.
+type WebhookSubscription struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	SchoolID   uint      `gorm:"index" json:"school_id"`
+	URL        string    `gorm:"not null" json:"url"`
+	EventTypes []string  `gorm:"type:jsonb;serializer:json" json:"event_types"`
+	Secret     string    `gorm:"not null" json:"-"`
+	Active     bool      `gorm:"default:true" json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Subscribes reports whether the subscription wants events of this type.
+// "*" subscribes to everything.
+func (w *WebhookSubscription) Subscribes(eventType string) bool {
+	for _, t := range w.EventTypes {
+		if t == "*" || t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookRequest struct {
+	URL        string   `json:"url" binding:"required,url"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,oneof=* attendance.check_in attendance.check_out attendance.leave attendance.correction attendance.absent attendance.deleted teacher.created teacher.updated" example:"attendance.check_in,attendance.absent"`
+	Secret     string   `json:"secret" binding:"required,min=16"`
+	Active     *bool    `json:"active"`
+}
+
+// OutboxEvent is written in the same transaction as the change it
+// describes and later fanned out to webhook subscriptions.
+type OutboxEvent struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	SchoolID    uint       `gorm:"index" json:"school_id"`
+	EventType   string     `gorm:"not null;index" json:"event_type"`
+	Payload     string     `gorm:"type:jsonb;not null" json:"payload"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ProcessedAt *time.Time `gorm:"index" json:"processed_at,omitempty"`
+}
+
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	SchoolID       uint       `gorm:"index" json:"school_id"`
+	SubscriptionID uint       `gorm:"not null;index" json:"subscription_id"`
+	OutboxEventID  uint       `gorm:"not null;index" json:"outbox_event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `gorm:"not null;index" json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time  `gorm:"index" json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// WebhookPayload is the JSON body posted to subscribers.
+type WebhookPayload struct {
+	ID         uint            `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data" swaggertype:"object"`
+}
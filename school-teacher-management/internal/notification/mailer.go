@@ -0,0 +1,46 @@
+package notification
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"school-teacher-management/internal/config"
+)
+
+type Mailer interface {
+	Send(to []string, subject, body string) error
+}
+
+type SMTPMailer struct {
+	Config config.SMTPConfig
+}
+
+func NewSMTPMailer(cfg config.SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{Config: cfg}
+}
+
+func (m *SMTPMailer) Send(to []string, subject, body string) error {
+	if len(to) == 0 {
+		return nil
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", m.Config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	// A local sink takes mail without authentication.
+	var auth smtp.Auth
+	if m.Config.Username != "" {
+		auth = smtp.PlainAuth("", m.Config.Username, m.Config.Password, m.Config.Host)
+	}
+
+	addr := m.Config.Host + ":" + m.Config.Port
+	return smtp.SendMail(addr, auth, m.Config.From, to, []byte(msg.String()))
+}
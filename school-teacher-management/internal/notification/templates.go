@@ -0,0 +1,95 @@
+package notification
+
+import (
+	"embed"
+	"strings"
+	"text/template"
+)
+
+const (
+	TemplateLateAlert        = "late_alert"
+	TemplateCheckoutReminder = "checkout_reminder"
+	TemplateLeaveRequested   = "leave_requested"
+	TemplateLeaveDecided     = "leave_decided"
+	TemplateDailyDigest      = "daily_digest"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// Each template file defines a "subject" and a "body" block.
+var templates = map[string]*template.Template{}
+
+func init() {
+	for _, name := range []string{
+		TemplateLateAlert,
+		TemplateCheckoutReminder,
+		TemplateLeaveRequested,
+		TemplateLeaveDecided,
+		TemplateDailyDigest,
+	} {
+		templates[name] = template.Must(template.ParseFS(templateFS, "templates/"+name+".tmpl"))
+	}
+}
+
+func Render(name string, data interface{}) (subject, body string, err error) {
+	tmpl := templates[name]
+
+	var s, b strings.Builder
+	if err := tmpl.ExecuteTemplate(&s, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&b, "body", data); err != nil {
+		return "", "", err
+	}
+
+	return strings.TrimSpace(s.String()), strings.TrimSpace(b.String()) + "\n", nil
+}
+
+type LateAlertData struct {
+	RecipientName string
+	Department    string
+	Date          string
+	AsOf          string
+	WorkStart     string
+	NoShows       []string
+	Late          []string
+}
+
+type CheckoutReminderData struct {
+	RecipientName string
+	Date          string
+	CheckIn       string
+}
+
+type LeaveRequestedData struct {
+	RecipientName string
+	TeacherName   string
+	Department    string
+	LeaveID       uint
+	StartDate     string
+	EndDate       string
+	Days          int
+	Reason        string
+}
+
+type LeaveDecidedData struct {
+	RecipientName string
+	LeaveID       uint
+	StartDate     string
+	EndDate       string
+	Status        string
+	Note          string
+}
+
+type DailyDigestData struct {
+	RecipientName   string
+	Department      string
+	Date            string
+	Total           int
+	Present         int
+	NoShows         []string
+	Late            []string
+	MissingCheckOut []string
+	OnLeave         []string
+}
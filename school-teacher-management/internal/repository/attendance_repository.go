@@ -1,12 +1,20 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"school-teacher-management/internal/model"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrDayTaken is returned by CreateDay when the teacher already has a
+// record for the day.
+var ErrDayTaken = errors.New("teacher already has attendance for the day")
+
 type AttendanceRepository struct {
 	DB *gorm.DB
 }
@@ -15,13 +23,79 @@ func NewAttendanceRepository(db *gorm.DB) *AttendanceRepository {
 	return &AttendanceRepository{DB: db}
 }
 
-func (r *AttendanceRepository) Create(att *model.Attendance) error {
-	return r.DB.Create(att).Error
+// WithTx returns a repository bound to an open transaction.
+func (r *AttendanceRepository) WithTx(tx *gorm.DB) *AttendanceRepository {
+	return &AttendanceRepository{DB: tx}
+}
+
+func (r *AttendanceRepository) WithContext(ctx context.Context) *AttendanceRepository {
+	return &AttendanceRepository{DB: r.DB.WithContext(ctx)}
+}
+
+// EnsureStatusCheck rewrites statuses stored before punches and statuses
+// were separated, then installs the check constraint that keeps the status
+// column to model.AttendanceStatuses. It is safe to run on every start.
+func (r *AttendanceRepository) EnsureStatusCheck() error {
+	statuses := make([]string, len(model.AttendanceStatuses))
+	for i, status := range model.AttendanceStatuses {
+		statuses[i] = "'" + string(status) + "'"
+	}
+	valid := strings.Join(statuses, ", ")
+
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+UPDATE attendances
+SET status = CASE WHEN check_in IS NULL THEN ? ELSE ? END
+WHERE status IS NULL OR status NOT IN (`+valid+`)`,
+			model.StatusAbsent, model.StatusPresent).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+ALTER TABLE attendances ALTER COLUMN status SET NOT NULL;
+ALTER TABLE attendances DROP CONSTRAINT IF EXISTS chk_attendances_status;
+ALTER TABLE attendances ADD CONSTRAINT chk_attendances_status CHECK (status IN (` + valid + `));
+`).Error
+	})
+}
+
+// EnsureUniqueDay installs the unique index that keeps one record per
+// teacher and day. Days that still have several records, left by
+// concurrent check-ins made before the index existed, are returned
+// instead and the index is not installed: which punches to keep is for an
+// administrator to decide. It is safe to run on every start.
+func (r *AttendanceRepository) EnsureUniqueDay() ([]model.DuplicateDay, error) {
+	if r.DB.Migrator().HasIndex(&model.Attendance{}, "idx_attendance_teacher_date") {
+		return nil, nil
+	}
+
+	var duplicates []model.DuplicateDay
+	err := r.DB.Raw(`
+SELECT teacher_id, date, string_agg(id::text, ', ' ORDER BY id) AS ids
+FROM attendances
+GROUP BY teacher_id, date
+HAVING count(*) > 1
+ORDER BY teacher_id, date`).Scan(&duplicates).Error
+	if err != nil || len(duplicates) > 0 {
+		return duplicates, err
+	}
+
+	return nil, r.DB.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_attendance_teacher_date ON attendances (teacher_id, date)`).Error
 }
 
-func (r *AttendanceRepository) GetAll() ([]model.Attendance, error) {
+func (r *AttendanceRepository) GetAll(deviceID, campusID uint) ([]model.Attendance, error) {
 	var list []model.Attendance
-	err := r.DB.Preload("Teacher").Find(&list).Error
+	db := r.DB.Preload("Teacher")
+
+	if deviceID != 0 {
+		db = db.Where("check_in_device_id = ? OR check_out_device_id = ?", deviceID, deviceID)
+	}
+	if campusID != 0 {
+		db = db.Where("check_in_campus_id = ? OR check_out_campus_id = ?", campusID, campusID)
+	}
+
+	err := db.Find(&list).Error
 	return list, err
 }
 
@@ -31,8 +105,10 @@ func (r *AttendanceRepository) GetByID(id uint) (*model.Attendance, error) {
 	return &att, err
 }
 
-func (r *AttendanceRepository) Update(att *model.Attendance) error {
-	return r.DB.Save(att).Error
+// Update writes the fields of att that differ from before, the row as it
+// was read. It fails with ErrStaleVersion when the row changed since.
+func (r *AttendanceRepository) Update(before, att *model.Attendance) error {
+	return updateChanged(r.DB, before, att, before.Version)
 }
 
 func (r *AttendanceRepository) Delete(id uint) error {
@@ -55,6 +131,39 @@ func (r *AttendanceRepository) FindByTeacherAndDate(
 		First(attendance).Error
 }
 
+// LockByTeacherAndDate is FindByTeacherAndDate holding a row lock on the
+// record until the transaction ends.
+func (r *AttendanceRepository) LockByTeacherAndDate(
+	teacherID uint,
+	date time.Time,
+	attendance *model.Attendance,
+) error {
+	return r.DB.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("teacher_id = ? AND date = ?", teacherID, date).
+		First(attendance).Error
+}
+
+// CreateDay inserts att as the teacher's record for its date, or fails
+// with ErrDayTaken when the day already has one. An insert by a
+// transaction still open waits for it to end, so the record it created is
+// seen once it commits.
+func (r *AttendanceRepository) CreateDay(att *model.Attendance) error {
+	result := r.DB.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "teacher_id"}, {Name: "date"}},
+			DoNothing: true,
+		}).
+		Create(att)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDayTaken
+	}
+	return nil
+}
+
 // FindByTeacherAndMonth finds all attendance for a teacher in a given month/year
 func (r *AttendanceRepository) FindByTeacherAndMonth(
 	teacherID uint,
@@ -75,20 +184,56 @@ func (r *AttendanceRepository) FindByMonthAndDate(
 	month time.Month,
 	year int,
 	date time.Time,
+	campusID uint,
 ) ([]model.Attendance, error) {
 
 	var list []model.Attendance
 
-	err := r.DB.
+	db := r.DB.
 		Preload("Teacher").
 		Where(
 			"EXTRACT(MONTH FROM date) = ? AND EXTRACT(YEAR FROM date) = ? AND DATE(date) = DATE(?)",
 			int(month),
 			year,
 			date,
-		).
+		)
+	if campusID != 0 {
+		db = db.Where("check_in_campus_id = ? OR check_out_campus_id = ?", campusID, campusID)
+	}
+
+	err := db.Find(&list).Error
+
+	return list, err
+}
+
+// FindByMonth returns the whole register of one month, in date order.
+func (r *AttendanceRepository) FindByMonth(year int, month time.Month) ([]model.Attendance, error) {
+	var list []model.Attendance
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	err := r.DB.
+		Preload("Teacher").
+		Where("date >= ? AND date < ?", start, start.AddDate(0, 1, 0)).
+		Order("date, teacher_id").
 		Find(&list).Error
+	return list, err
+}
 
+// FindBetween returns attendance dated from start to end, inclusive.
+func (r *AttendanceRepository) FindBetween(start, end time.Time) ([]model.Attendance, error) {
+	var list []model.Attendance
+	err := r.DB.
+		Where("date >= ? AND date <= ?", start, end).
+		Order("date, teacher_id").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *AttendanceRepository) FindByDate(date time.Time) ([]model.Attendance, error) {
+	var list []model.Attendance
+	err := r.DB.
+		Preload("Teacher").
+		Where("date = ?", date).
+		Find(&list).Error
 	return list, err
 }
 
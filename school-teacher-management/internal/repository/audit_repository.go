@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"school-teacher-management/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// auditLockKey serialises appends so that each row sees its predecessor.
+const auditLockKey = 7_300_030
+
+type AuditRepository struct {
+	DB *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{DB: db}
+}
+
+func (r *AuditRepository) WithTx(tx *gorm.DB) *AuditRepository {
+	return &AuditRepository{DB: tx}
+}
+
+func (r *AuditRepository) WithContext(ctx context.Context) *AuditRepository {
+	return &AuditRepository{DB: r.DB.WithContext(ctx)}
+}
+
+// EnsureAppendOnly installs the trigger that rejects changes to existing
+// audit rows. It is safe to run on every start.
+func (r *AuditRepository) EnsureAppendOnly() error {
+	return r.DB.Exec(`
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_logs_no_update ON audit_logs;
+CREATE TRIGGER audit_logs_no_update
+	BEFORE UPDATE OR DELETE ON audit_logs
+	FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
+
+DROP TRIGGER IF EXISTS audit_logs_no_truncate ON audit_logs;
+CREATE TRIGGER audit_logs_no_truncate
+	BEFORE TRUNCATE ON audit_logs
+	FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_append_only();
+`).Error
+}
+
+// Append links the entry to the current chain head and inserts it. It must
+// run inside the transaction that makes the audited change; the advisory
+// lock is held until that transaction ends. The head is read with raw SQL
+// so the tenant plugin does not scope it to the request's school.
+func (r *AuditRepository) Append(entry *model.AuditLog) error {
+	if err := r.DB.Exec("SELECT pg_advisory_xact_lock(?)", auditLockKey).Error; err != nil {
+		return err
+	}
+
+	var prevHash string
+	if err := r.DB.Raw("SELECT hash FROM audit_logs ORDER BY id DESC LIMIT 1").Scan(&prevHash).Error; err != nil {
+		return err
+	}
+
+	entry.PrevHash = prevHash
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	entry.Hash = entry.ComputeHash()
+
+	return r.DB.Create(entry).Error
+}
+
+func (r *AuditRepository) Find(filter model.AuditFilter) ([]model.AuditLog, error) {
+	var list []model.AuditLog
+	db := r.DB.Model(&model.AuditLog{})
+
+	if filter.EntityType != "" {
+		db = db.Where("entity_type = ?", filter.EntityType)
+	}
+
+	if filter.EntityID != 0 {
+		db = db.Where("entity_id = ?", filter.EntityID)
+	}
+
+	if filter.ActorID != 0 {
+		db = db.Where("actor_teacher_id = ?", filter.ActorID)
+	}
+
+	if filter.ActorRole != "" {
+		db = db.Where("actor_role = ?", filter.ActorRole)
+	}
+
+	if filter.From != nil {
+		db = db.Where("created_at >= ?", *filter.From)
+	}
+
+	if filter.To != nil {
+		db = db.Where("created_at < ?", *filter.To)
+	}
+
+	err := db.Order("id DESC").Limit(filter.Limit).Find(&list).Error
+	return list, err
+}
+
+// FindAfter returns entries in chain order, for verification.
+func (r *AuditRepository) FindAfter(id uint, limit int) ([]model.AuditLog, error) {
+	var list []model.AuditLog
+	err := r.DB.Where("id > ?", id).Order("id").Limit(limit).Find(&list).Error
+	return list, err
+}
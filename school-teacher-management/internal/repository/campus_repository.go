@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"school-teacher-management/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type CampusRepository struct {
+	DB *gorm.DB
+}
+
+func NewCampusRepository(db *gorm.DB) *CampusRepository {
+	return &CampusRepository{DB: db}
+}
+
+func (r *CampusRepository) WithTx(tx *gorm.DB) *CampusRepository {
+	return &CampusRepository{DB: tx}
+}
+
+func (r *CampusRepository) WithContext(ctx context.Context) *CampusRepository {
+	return &CampusRepository{DB: r.DB.WithContext(ctx)}
+}
+
+func (r *CampusRepository) Create(campus *model.Campus) error {
+	return r.DB.Create(campus).Error
+}
+
+func (r *CampusRepository) Update(campus *model.Campus) error {
+	return r.DB.Save(campus).Error
+}
+
+func (r *CampusRepository) GetAll() ([]model.Campus, error) {
+	var list []model.Campus
+	err := r.DB.Order("school_id, name").Find(&list).Error
+	return list, err
+}
+
+func (r *CampusRepository) GetByID(id uint) (*model.Campus, error) {
+	var campus model.Campus
+	err := r.DB.First(&campus, id).Error
+	return &campus, err
+}
+
+// ForTeacher returns the teacher's home campus.
+func (r *CampusRepository) ForTeacher(teacherID uint) (*model.Campus, error) {
+	var campus model.Campus
+	err := r.DB.
+		Joins("JOIN teachers ON teachers.home_campus_id = campuses.id").
+		Where("teachers.id = ?", teacherID).
+		First(&campus).Error
+	return &campus, err
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"school-teacher-management/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CorrectionRepository struct {
+	DB *gorm.DB
+}
+
+func NewCorrectionRepository(db *gorm.DB) *CorrectionRepository {
+	return &CorrectionRepository{DB: db}
+}
+
+func (r *CorrectionRepository) WithTx(tx *gorm.DB) *CorrectionRepository {
+	return &CorrectionRepository{DB: tx}
+}
+
+func (r *CorrectionRepository) WithContext(ctx context.Context) *CorrectionRepository {
+	return &CorrectionRepository{DB: r.DB.WithContext(ctx)}
+}
+
+func (r *CorrectionRepository) Create(correction *model.AttendanceCorrection) error {
+	return r.DB.Create(correction).Error
+}
+
+func (r *CorrectionRepository) Update(correction *model.AttendanceCorrection) error {
+	return r.DB.Omit("Teacher").Save(correction).Error
+}
+
+func (r *CorrectionRepository) GetByID(id uint) (*model.AttendanceCorrection, error) {
+	var correction model.AttendanceCorrection
+	err := r.DB.Preload("Teacher").First(&correction, id).Error
+	return &correction, err
+}
+
+func (r *CorrectionRepository) Find(teacherID uint, department string, status string) ([]model.AttendanceCorrection, error) {
+	var list []model.AttendanceCorrection
+	db := r.DB.Preload("Teacher")
+
+	if teacherID != 0 {
+		db = db.Where("attendance_corrections.teacher_id = ?", teacherID)
+	}
+
+	if department != "" {
+		db = db.Joins("JOIN teachers ON teachers.id = attendance_corrections.teacher_id").
+			Where("teachers.department = ?", department)
+	}
+
+	if status != "" {
+		db = db.Where("attendance_corrections.status = ?", status)
+	}
+
+	err := db.Order("attendance_corrections.id DESC").Find(&list).Error
+	return list, err
+}
+
+func (r *CorrectionRepository) CountPending(teacherID uint, date time.Time) (int64, error) {
+	var count int64
+	err := r.DB.
+		Model(&model.AttendanceCorrection{}).
+		Where("teacher_id = ? AND date = ? AND status = ?", teacherID, date, model.CorrectionPending).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *CorrectionRepository) AddChange(change *model.AttendanceChange) error {
+	return r.DB.Create(change).Error
+}
+
+func (r *CorrectionRepository) FindChanges(attendanceID uint) ([]model.AttendanceChange, error) {
+	var list []model.AttendanceChange
+	err := r.DB.
+		Where("attendance_id = ?", attendanceID).
+		Order("id").
+		Find(&list).Error
+	return list, err
+}
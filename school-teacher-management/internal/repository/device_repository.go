@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"school-teacher-management/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DeviceRepository struct {
+	DB *gorm.DB
+}
+
+func NewDeviceRepository(db *gorm.DB) *DeviceRepository {
+	return &DeviceRepository{DB: db}
+}
+
+func (r *DeviceRepository) WithTx(tx *gorm.DB) *DeviceRepository {
+	return &DeviceRepository{DB: tx}
+}
+
+func (r *DeviceRepository) WithContext(ctx context.Context) *DeviceRepository {
+	return &DeviceRepository{DB: r.DB.WithContext(ctx)}
+}
+
+func (r *DeviceRepository) Create(device *model.Device) error {
+	return r.DB.Create(device).Error
+}
+
+func (r *DeviceRepository) Update(device *model.Device) error {
+	return r.DB.Save(device).Error
+}
+
+func (r *DeviceRepository) GetByID(id uint) (*model.Device, error) {
+	var device model.Device
+	err := r.DB.First(&device, id).Error
+	return &device, err
+}
+
+// FindOrCreate returns the device with the identifier, creating it from
+// device when it is new.
+func (r *DeviceRepository) FindOrCreate(device *model.Device) error {
+	return r.DB.
+		Where(model.Device{Identifier: device.Identifier}).
+		FirstOrCreate(device).Error
+}
+
+// Find returns every device, or those owned by or bound to teacherID.
+func (r *DeviceRepository) Find(teacherID uint) ([]model.Device, error) {
+	var list []model.Device
+	db := r.DB
+
+	if teacherID != 0 {
+		db = db.Where("owner_id = ? OR id IN (?)", teacherID,
+			r.DB.Model(&model.DeviceBinding{}).Select("device_id").Where("teacher_id = ?", teacherID))
+	}
+
+	err := db.Order("id").Find(&list).Error
+	return list, err
+}
+
+func (r *DeviceRepository) Touch(id uint, at time.Time) error {
+	return r.DB.Model(&model.Device{}).Where("id = ?", id).UpdateColumn("last_seen_at", at).Error
+}
+
+// RequestBinding returns the teacher's binding to the device, creating a
+// pending one if there is none.
+func (r *DeviceRepository) RequestBinding(teacherID, deviceID uint) (*model.DeviceBinding, error) {
+	binding := model.DeviceBinding{TeacherID: teacherID, DeviceID: deviceID, Status: model.BindingPending}
+	err := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&binding).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.DB.
+		Where("teacher_id = ? AND device_id = ?", teacherID, deviceID).
+		First(&binding).Error
+	return &binding, err
+}
+
+func (r *DeviceRepository) GetBinding(id uint) (*model.DeviceBinding, error) {
+	var binding model.DeviceBinding
+	err := r.DB.Preload("Teacher").Preload("Device").First(&binding, id).Error
+	return &binding, err
+}
+
+func (r *DeviceRepository) UpdateBinding(binding *model.DeviceBinding) error {
+	return r.DB.Omit("Teacher", "Device").Save(binding).Error
+}
+
+func (r *DeviceRepository) FindBindings(teacherID uint, department string, status string) ([]model.DeviceBinding, error) {
+	var list []model.DeviceBinding
+	db := r.DB.Preload("Teacher").Preload("Device")
+
+	if teacherID != 0 {
+		db = db.Where("device_bindings.teacher_id = ?", teacherID)
+	}
+
+	if department != "" {
+		db = db.Joins("JOIN teachers ON teachers.id = device_bindings.teacher_id").
+			Where("teachers.department = ?", department)
+	}
+
+	if status != "" {
+		db = db.Where("device_bindings.status = ?", status)
+	}
+
+	err := db.Order("device_bindings.id DESC").Find(&list).Error
+	return list, err
+}
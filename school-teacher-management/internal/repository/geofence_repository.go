@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"school-teacher-management/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type GeofenceRepository struct {
+	DB *gorm.DB
+}
+
+func NewGeofenceRepository(db *gorm.DB) *GeofenceRepository {
+	return &GeofenceRepository{DB: db}
+}
+
+func (r *GeofenceRepository) WithContext(ctx context.Context) *GeofenceRepository {
+	return &GeofenceRepository{DB: r.DB.WithContext(ctx)}
+}
+
+func (r *GeofenceRepository) Create(fence *model.Geofence) error {
+	return r.DB.Create(fence).Error
+}
+
+func (r *GeofenceRepository) Update(fence *model.Geofence) error {
+	return r.DB.Save(fence).Error
+}
+
+func (r *GeofenceRepository) Delete(id uint) error {
+	result := r.DB.Delete(&model.Geofence{}, id)
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return result.Error
+}
+
+func (r *GeofenceRepository) GetByID(id uint) (*model.Geofence, error) {
+	var fence model.Geofence
+	err := r.DB.First(&fence, id).Error
+	return &fence, err
+}
+
+func (r *GeofenceRepository) GetAll() ([]model.Geofence, error) {
+	var list []model.Geofence
+	err := r.DB.Order("id").Find(&list).Error
+	return list, err
+}
+
+// FindActive returns the active geofences of the school, including those
+// of no campus.
+func (r *GeofenceRepository) FindActive(schoolID uint) ([]model.Geofence, error) {
+	var list []model.Geofence
+	err := r.DB.
+		Where("active = ? AND school_id = ?", true, schoolID).
+		Order("id").
+		Find(&list).Error
+	return list, err
+}
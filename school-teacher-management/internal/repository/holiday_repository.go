@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"school-teacher-management/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type HolidayRepository struct {
+	DB *gorm.DB
+}
+
+func NewHolidayRepository(db *gorm.DB) *HolidayRepository {
+	return &HolidayRepository{DB: db}
+}
+
+func (r *HolidayRepository) WithTx(tx *gorm.DB) *HolidayRepository {
+	return &HolidayRepository{DB: tx}
+}
+
+func (r *HolidayRepository) WithContext(ctx context.Context) *HolidayRepository {
+	return &HolidayRepository{DB: r.DB.WithContext(ctx)}
+}
+
+func (r *HolidayRepository) Create(holiday *model.Holiday) error {
+	return r.DB.Create(holiday).Error
+}
+
+func (r *HolidayRepository) Delete(id uint) error {
+	result := r.DB.Delete(&model.Holiday{}, id)
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return result.Error
+}
+
+func (r *HolidayRepository) FindByYear(year int, campusID uint) ([]model.Holiday, error) {
+	var list []model.Holiday
+	db := r.DB
+
+	if campusID != 0 {
+		db = db.Where("campus_id IN (0, ?)", campusID)
+	}
+
+	if year != 0 {
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		db = db.Where("date >= ? AND date < ?", start, start.AddDate(1, 0, 0))
+	}
+
+	err := db.Order("date").Find(&list).Error
+	return list, err
+}
+
+// FindBetween returns holidays from start to end, inclusive. Unscoped
+// callers get those of every school.
+func (r *HolidayRepository) FindBetween(start, end time.Time) ([]model.Holiday, error) {
+	var list []model.Holiday
+	err := r.DB.
+		Where("date >= ? AND date <= ?", start, end).
+		Order("date").
+		Find(&list).Error
+	return list, err
+}
+
+// IsHoliday reports whether the school, or the campus when campusID is set,
+// is closed on the date.
+func (r *HolidayRepository) IsHoliday(schoolID, campusID uint, date time.Time) (bool, error) {
+	var count int64
+	err := r.DB.Model(&model.Holiday{}).
+		Where("school_id = ? AND campus_id IN (0, ?) AND date = ?", schoolID, campusID, date).
+		Count(&count).Error
+	return count > 0, err
+}
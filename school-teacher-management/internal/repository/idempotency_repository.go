@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"school-teacher-management/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository struct {
+	DB *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{DB: db}
+}
+
+func (r *IdempotencyRepository) WithContext(ctx context.Context) *IdempotencyRepository {
+	return &IdempotencyRepository{DB: r.DB.WithContext(ctx)}
+}
+
+// Reserve stores the record unless its key is already taken, and reports
+// whether it was stored.
+func (r *IdempotencyRepository) Reserve(record *model.IdempotencyRecord) (bool, error) {
+	result := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *IdempotencyRepository) Find(caller, method, path, key string) (*model.IdempotencyRecord, error) {
+	var record model.IdempotencyRecord
+	err := r.DB.Where("caller = ? AND method = ? AND path = ? AND key = ?", caller, method, path, key).
+		First(&record).Error
+	return &record, err
+}
+
+// Complete stores the response of a reserved record.
+func (r *IdempotencyRepository) Complete(record *model.IdempotencyRecord) error {
+	return r.DB.Model(record).Updates(map[string]interface{}{
+		"status_code":  record.StatusCode,
+		"content_type": record.ContentType,
+		"body":         record.Body,
+	}).Error
+}
+
+func (r *IdempotencyRepository) Delete(id uint) error {
+	return r.DB.Delete(&model.IdempotencyRecord{}, id).Error
+}
+
+// DeleteExpired removes the records that expired before now and returns
+// how many there were.
+func (r *IdempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.DB.Where("expires_at < ?", now).Delete(&model.IdempotencyRecord{})
+	return result.RowsAffected, result.Error
+}
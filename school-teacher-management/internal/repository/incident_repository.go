@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"school-teacher-management/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IncidentRepository struct {
+	DB *gorm.DB
+}
+
+func NewIncidentRepository(db *gorm.DB) *IncidentRepository {
+	return &IncidentRepository{DB: db}
+}
+
+func (r *IncidentRepository) WithContext(ctx context.Context) *IncidentRepository {
+	return &IncidentRepository{DB: r.DB.WithContext(ctx)}
+}
+
+// Flag stores the incident unless one with the same fingerprint exists,
+// and reports whether it was stored.
+func (r *IncidentRepository) Flag(incident *model.Incident) (bool, error) {
+	result := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(incident)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *IncidentRepository) GetByID(id uint) (*model.Incident, error) {
+	var incident model.Incident
+	err := r.DB.Preload("Teacher").First(&incident, id).Error
+	return &incident, err
+}
+
+func (r *IncidentRepository) Update(incident *model.Incident) error {
+	return r.DB.Omit("Teacher").Save(incident).Error
+}
+
+func (r *IncidentRepository) Find(filter model.IncidentFilter) ([]model.Incident, error) {
+	var list []model.Incident
+	db := r.DB.Preload("Teacher")
+
+	if filter.TeacherID != 0 {
+		db = db.Where("incidents.teacher_id = ?", filter.TeacherID)
+	}
+
+	if filter.Department != "" {
+		db = db.Joins("JOIN teachers ON teachers.id = incidents.teacher_id").
+			Where("teachers.department = ?", filter.Department)
+	}
+
+	if filter.Kind != "" {
+		db = db.Where("incidents.kind = ?", filter.Kind)
+	}
+
+	if filter.Status != "" {
+		db = db.Where("incidents.status = ?", filter.Status)
+	}
+
+	if filter.From != nil {
+		db = db.Where("incidents.date >= ?", *filter.From)
+	}
+
+	if filter.To != nil {
+		db = db.Where("incidents.date <= ?", *filter.To)
+	}
+
+	err := db.Order("incidents.date DESC, incidents.id DESC").Find(&list).Error
+	return list, err
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"school-teacher-management/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type KioskRepository struct {
+	DB *gorm.DB
+}
+
+func NewKioskRepository(db *gorm.DB) *KioskRepository {
+	return &KioskRepository{DB: db}
+}
+
+func (r *KioskRepository) WithContext(ctx context.Context) *KioskRepository {
+	return &KioskRepository{DB: r.DB.WithContext(ctx)}
+}
+
+func (r *KioskRepository) Create(kiosk *model.Kiosk) error {
+	return r.DB.Create(kiosk).Error
+}
+
+func (r *KioskRepository) Update(kiosk *model.Kiosk) error {
+	return r.DB.Save(kiosk).Error
+}
+
+func (r *KioskRepository) GetAll() ([]model.Kiosk, error) {
+	var list []model.Kiosk
+	err := r.DB.Order("id").Find(&list).Error
+	return list, err
+}
+
+func (r *KioskRepository) GetByID(id uint) (*model.Kiosk, error) {
+	var kiosk model.Kiosk
+	err := r.DB.First(&kiosk, id).Error
+	return &kiosk, err
+}
+
+func (r *KioskRepository) GetByKeyHash(hash string) (*model.Kiosk, error) {
+	var kiosk model.Kiosk
+	err := r.DB.Where("key_hash = ?", hash).First(&kiosk).Error
+	return &kiosk, err
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"school-teacher-management/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LeaveRepository struct {
+	DB *gorm.DB
+}
+
+func NewLeaveRepository(db *gorm.DB) *LeaveRepository {
+	return &LeaveRepository{DB: db}
+}
+
+func (r *LeaveRepository) WithTx(tx *gorm.DB) *LeaveRepository {
+	return &LeaveRepository{DB: tx}
+}
+
+func (r *LeaveRepository) WithContext(ctx context.Context) *LeaveRepository {
+	return &LeaveRepository{DB: r.DB.WithContext(ctx)}
+}
+
+func (r *LeaveRepository) Create(leave *model.LeaveRequest) error {
+	return r.DB.Create(leave).Error
+}
+
+func (r *LeaveRepository) Update(leave *model.LeaveRequest) error {
+	return r.DB.Save(leave).Error
+}
+
+func (r *LeaveRepository) GetByID(id uint) (*model.LeaveRequest, error) {
+	var leave model.LeaveRequest
+	err := r.DB.Preload("Teacher").First(&leave, id).Error
+	return &leave, err
+}
+
+func (r *LeaveRepository) Find(teacherID uint, department string, status string) ([]model.LeaveRequest, error) {
+	var list []model.LeaveRequest
+	db := r.DB.Preload("Teacher")
+
+	if teacherID != 0 {
+		db = db.Where("leave_requests.teacher_id = ?", teacherID)
+	}
+
+	if department != "" {
+		db = db.Joins("JOIN teachers ON teachers.id = leave_requests.teacher_id").
+			Where("teachers.department = ?", department)
+	}
+
+	if status != "" {
+		db = db.Where("leave_requests.status = ?", status)
+	}
+
+	err := db.Order("leave_requests.start_date DESC").Find(&list).Error
+	return list, err
+}
+
+// FindApprovedOn returns approved leave covering the given date.
+func (r *LeaveRepository) FindApprovedOn(date time.Time) ([]model.LeaveRequest, error) {
+	var list []model.LeaveRequest
+	err := r.DB.
+		Where("status = ? AND start_date <= ? AND end_date >= ?", model.LeaveApproved, date, date).
+		Find(&list).Error
+	return list, err
+}
+
+// FindApprovedBetween returns approved leave overlapping the given range.
+func (r *LeaveRepository) FindApprovedBetween(start, end time.Time) ([]model.LeaveRequest, error) {
+	var list []model.LeaveRequest
+	err := r.DB.
+		Where("status = ? AND start_date <= ? AND end_date >= ?", model.LeaveApproved, end, start).
+		Find(&list).Error
+	return list, err
+}
+
+// FindOverlapping returns pending or approved leave of a teacher that
+// overlaps the given range.
+func (r *LeaveRepository) FindOverlapping(teacherID uint, start, end time.Time) ([]model.LeaveRequest, error) {
+	var list []model.LeaveRequest
+	err := r.DB.
+		Where("teacher_id = ? AND status IN ? AND start_date <= ? AND end_date >= ?",
+			teacherID, []string{model.LeavePending, model.LeaveApproved}, end, start).
+		Find(&list).Error
+	return list, err
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"school-teacher-management/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type NetworkRepository struct {
+	DB *gorm.DB
+}
+
+func NewNetworkRepository(db *gorm.DB) *NetworkRepository {
+	return &NetworkRepository{DB: db}
+}
+
+func (r *NetworkRepository) WithContext(ctx context.Context) *NetworkRepository {
+	return &NetworkRepository{DB: r.DB.WithContext(ctx)}
+}
+
+// EnsureCampusLinks moves ranges from the free-text campus column they had
+// before to the campus of that name, and drops the column. A range whose
+// name matches no campus, or campuses of several schools, is left without
+// one and applies to no school; the count of those is returned.
+func (r *NetworkRepository) EnsureCampusLinks() (int64, error) {
+	if !r.DB.Migrator().HasColumn(&model.CampusNetwork{}, "campus") {
+		return 0, nil
+	}
+
+	var unlinked int64
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+UPDATE campus_networks n
+SET campus_id = c.id, school_id = c.school_id
+FROM campuses c
+WHERE n.campus_id IS NULL
+	AND c.name = n.campus
+	AND (SELECT count(*) FROM campuses d WHERE d.name = n.campus) = 1`).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Raw("SELECT count(*) FROM campus_networks WHERE campus_id IS NULL").Scan(&unlinked).Error; err != nil {
+			return err
+		}
+		return tx.Exec("ALTER TABLE campus_networks DROP COLUMN campus").Error
+	})
+	return unlinked, err
+}
+
+func (r *NetworkRepository) Create(network *model.CampusNetwork) error {
+	return r.DB.Create(network).Error
+}
+
+func (r *NetworkRepository) Delete(id uint) error {
+	result := r.DB.Delete(&model.CampusNetwork{}, id)
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return result.Error
+}
+
+func (r *NetworkRepository) GetAll() ([]model.CampusNetwork, error) {
+	var list []model.CampusNetwork
+	err := r.DB.Order("campus_id, id").Find(&list).Error
+	return list, err
+}
+
+// ForSchool returns the ranges of the school's campuses.
+func (r *NetworkRepository) ForSchool(schoolID uint) ([]model.CampusNetwork, error) {
+	var list []model.CampusNetwork
+	err := r.DB.Where("school_id = ?", schoolID).Order("campus_id, id").Find(&list).Error
+	return list, err
+}
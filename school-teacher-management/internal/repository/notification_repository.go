@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"school-teacher-management/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository struct {
+	DB *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{DB: db}
+}
+
+func (r *NotificationRepository) WithTx(tx *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{DB: tx}
+}
+
+func (r *NotificationRepository) WithContext(ctx context.Context) *NotificationRepository {
+	return &NotificationRepository{DB: r.DB.WithContext(ctx)}
+}
+
+func (r *NotificationRepository) GetPreference(teacherID uint) (*model.NotificationPreference, error) {
+	var pref model.NotificationPreference
+	err := r.DB.First(&pref, "teacher_id = ?", teacherID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		pref = model.DefaultNotificationPreference(teacherID)
+		return &pref, nil
+	}
+	return &pref, err
+}
+
+func (r *NotificationRepository) SavePreference(pref *model.NotificationPreference) error {
+	return r.DB.Save(pref).Error
+}
+
+// ClaimSend records that a notification is being sent and reports whether
+// this caller is the first to do so.
+func (r *NotificationRepository) ClaimSend(kind string, recipientID uint, date time.Time) (bool, error) {
+	result := r.DB.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.NotificationLog{Kind: kind, RecipientID: recipientID, Date: date})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *NotificationRepository) ReleaseSend(kind string, recipientID uint, date time.Time) error {
+	return r.DB.
+		Where("kind = ? AND recipient_id = ? AND date = ?", kind, recipientID, date).
+		Delete(&model.NotificationLog{}).Error
+}
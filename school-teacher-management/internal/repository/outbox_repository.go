@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"encoding/json"
+	"school-teacher-management/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository struct {
+	DB *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{DB: db}
+}
+
+func (r *OutboxRepository) WithTx(tx *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{DB: tx}
+}
+
+// Add records an event of a school; call it on a repository bound to the
+// transaction that makes the change so both commit or neither does.
+func (r *OutboxRepository) Add(schoolID uint, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return r.DB.Create(&model.OutboxEvent{
+		SchoolID:  schoolID,
+		EventType: eventType,
+		Payload:   string(payload),
+	}).Error
+}
+
+// LockUnprocessed must run inside a transaction. Rows locked by another
+// instance are skipped.
+func (r *OutboxRepository) LockUnprocessed(limit int) ([]model.OutboxEvent, error) {
+	var list []model.OutboxEvent
+	err := r.DB.
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("processed_at IS NULL").
+		Order("id").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+func (r *OutboxRepository) MarkProcessed(ids []uint) error {
+	return r.DB.
+		Model(&model.OutboxEvent{}).
+		Where("id IN ?", ids).
+		Update("processed_at", time.Now()).Error
+}
+
+func (r *OutboxRepository) GetByID(id uint) (*model.OutboxEvent, error) {
+	var event model.OutboxEvent
+	err := r.DB.First(&event, id).Error
+	return &event, err
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"school-teacher-management/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OvertimeRepository struct {
+	DB *gorm.DB
+}
+
+func NewOvertimeRepository(db *gorm.DB) *OvertimeRepository {
+	return &OvertimeRepository{DB: db}
+}
+
+func (r *OvertimeRepository) WithTx(tx *gorm.DB) *OvertimeRepository {
+	return &OvertimeRepository{DB: tx}
+}
+
+func (r *OvertimeRepository) WithContext(ctx context.Context) *OvertimeRepository {
+	return &OvertimeRepository{DB: r.DB.WithContext(ctx)}
+}
+
+// SavePending records the overtime of an attendance row, replacing an
+// earlier pending figure. Decided records are left alone.
+func (r *OvertimeRepository) SavePending(record *model.OvertimeRecord) error {
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "attendance_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"date", "kind", "minutes", "updated_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: "overtime_records.status", Value: model.OvertimePending},
+		}},
+	}).Create(record).Error
+}
+
+// DeletePending drops undecided overtime of an attendance row that no
+// longer qualifies or no longer exists.
+func (r *OvertimeRepository) DeletePending(attendanceID uint) error {
+	return r.DB.
+		Where("attendance_id = ? AND status = ?", attendanceID, model.OvertimePending).
+		Delete(&model.OvertimeRecord{}).Error
+}
+
+func (r *OvertimeRepository) Update(record *model.OvertimeRecord) error {
+	return r.DB.Omit("Teacher").Save(record).Error
+}
+
+func (r *OvertimeRepository) GetByID(id uint) (*model.OvertimeRecord, error) {
+	var record model.OvertimeRecord
+	err := r.DB.Preload("Teacher").First(&record, id).Error
+	return &record, err
+}
+
+func (r *OvertimeRepository) Find(teacherID uint, department string, status string) ([]model.OvertimeRecord, error) {
+	var list []model.OvertimeRecord
+	db := r.DB.Preload("Teacher")
+
+	if teacherID != 0 {
+		db = db.Where("overtime_records.teacher_id = ?", teacherID)
+	}
+
+	if department != "" {
+		db = db.Joins("JOIN teachers ON teachers.id = overtime_records.teacher_id").
+			Where("teachers.department = ?", department)
+	}
+
+	if status != "" {
+		db = db.Where("overtime_records.status = ?", status)
+	}
+
+	err := db.Order("overtime_records.date DESC, overtime_records.id DESC").Find(&list).Error
+	return list, err
+}
+
+// FindPaidBetween returns overtime approved for payment, dated from start
+// to end inclusive.
+func (r *OvertimeRepository) FindPaidBetween(start, end time.Time) ([]model.OvertimeRecord, error) {
+	var list []model.OvertimeRecord
+	err := r.DB.
+		Where("status = ? AND compensation = ? AND date >= ? AND date <= ?",
+			model.OvertimeApproved, model.CompensationPaid, start, end).
+		Find(&list).Error
+	return list, err
+}
+
+func (r *OvertimeRepository) AddCredit(credit *model.CompOffCredit) error {
+	return r.DB.Create(credit).Error
+}
+
+// FindActiveCredits returns unexpired credits with days left, soonest
+// expiry first. lock takes row locks for spending them.
+func (r *OvertimeRepository) FindActiveCredits(teacherID uint, on time.Time, lock bool) ([]model.CompOffCredit, error) {
+	var list []model.CompOffCredit
+	db := r.DB
+
+	if lock {
+		db = db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	err := db.
+		Where("teacher_id = ? AND expires_on >= ? AND used_days < days", teacherID, on).
+		Order("expires_on, id").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *OvertimeRepository) UpdateCredit(credit *model.CompOffCredit) error {
+	return r.DB.Save(credit).Error
+}
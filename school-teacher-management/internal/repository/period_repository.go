@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"school-teacher-management/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// periodLockKey namespaces the per-month advisory locks: attendance writes
+// take the month's lock shared, closing a period takes it exclusively.
+const periodLockKey = 7_300_031
+
+type PeriodRepository struct {
+	DB *gorm.DB
+}
+
+func NewPeriodRepository(db *gorm.DB) *PeriodRepository {
+	return &PeriodRepository{DB: db}
+}
+
+func (r *PeriodRepository) WithTx(tx *gorm.DB) *PeriodRepository {
+	return &PeriodRepository{DB: tx}
+}
+
+func (r *PeriodRepository) WithContext(ctx context.Context) *PeriodRepository {
+	return &PeriodRepository{DB: r.DB.WithContext(ctx)}
+}
+
+// LockShared and LockExclusive must run inside a transaction; the lock is
+// released when it ends. A hash collision between two schools' months only
+// serialises them needlessly.
+func (r *PeriodRepository) LockShared(schoolID uint, year int, month time.Month) error {
+	return r.DB.Exec("SELECT pg_advisory_xact_lock_shared(?, hashtext(?))", periodLockKey, periodLockName(schoolID, year, month)).Error
+}
+
+func (r *PeriodRepository) LockExclusive(schoolID uint, year int, month time.Month) error {
+	return r.DB.Exec("SELECT pg_advisory_xact_lock(?, hashtext(?))", periodLockKey, periodLockName(schoolID, year, month)).Error
+}
+
+func periodLockName(schoolID uint, year int, month time.Month) string {
+	return fmt.Sprintf("%d/%d-%02d", schoolID, year, int(month))
+}
+
+// Get returns the school's period, or an open one that has not been saved
+// yet.
+func (r *PeriodRepository) Get(schoolID uint, year int, month time.Month) (*model.AttendancePeriod, error) {
+	period := model.AttendancePeriod{SchoolID: schoolID, Year: year, Month: int(month), Status: model.PeriodOpen}
+	err := r.DB.
+		Where("school_id = ? AND year = ? AND month = ?", schoolID, year, int(month)).
+		Limit(1).
+		Find(&period).Error
+	return &period, err
+}
+
+func (r *PeriodRepository) Find(year int) ([]model.AttendancePeriod, error) {
+	var list []model.AttendancePeriod
+	db := r.DB
+
+	if year != 0 {
+		db = db.Where("year = ?", year)
+	}
+
+	err := db.Order("year DESC, month DESC").Find(&list).Error
+	return list, err
+}
+
+func (r *PeriodRepository) Save(period *model.AttendancePeriod) error {
+	return r.DB.Save(period).Error
+}
+
+func (r *PeriodRepository) AddSnapshot(snapshot *model.AttendanceSnapshot) error {
+	return r.DB.Create(snapshot).Error
+}
+
+func (r *PeriodRepository) LatestSnapshot(periodID uint) (*model.AttendanceSnapshot, error) {
+	var snapshot model.AttendanceSnapshot
+	err := r.DB.
+		Where("period_id = ?", periodID).
+		Order("id DESC").
+		First(&snapshot).Error
+	return &snapshot, err
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"school-teacher-management/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PunchRepository struct {
+	DB *gorm.DB
+}
+
+func NewPunchRepository(db *gorm.DB) *PunchRepository {
+	return &PunchRepository{DB: db}
+}
+
+func (r *PunchRepository) WithTx(tx *gorm.DB) *PunchRepository {
+	return &PunchRepository{DB: tx}
+}
+
+func (r *PunchRepository) WithContext(ctx context.Context) *PunchRepository {
+	return &PunchRepository{DB: r.DB.WithContext(ctx)}
+}
+
+func (r *PunchRepository) Find(teacherID uint, key string) (*model.OfflinePunch, error) {
+	var punch model.OfflinePunch
+	err := r.DB.Where("teacher_id = ? AND idempotency_key = ?", teacherID, key).First(&punch).Error
+	return &punch, err
+}
+
+// Record stores the punch unless its idempotency key was already used,
+// and reports whether it was stored.
+func (r *PunchRepository) Record(punch *model.OfflinePunch) (bool, error) {
+	result := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(punch)
+	return result.RowsAffected == 1, result.Error
+}
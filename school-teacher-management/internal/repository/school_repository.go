@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"school-teacher-management/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// DefaultSchoolSlug names the school that holds the data of a deployment
+// from before schools existed.
+const DefaultSchoolSlug = "default"
+
+type SchoolRepository struct {
+	DB *gorm.DB
+}
+
+func NewSchoolRepository(db *gorm.DB) *SchoolRepository {
+	return &SchoolRepository{DB: db}
+}
+
+func (r *SchoolRepository) WithTx(tx *gorm.DB) *SchoolRepository {
+	return &SchoolRepository{DB: tx}
+}
+
+func (r *SchoolRepository) WithContext(ctx context.Context) *SchoolRepository {
+	return &SchoolRepository{DB: r.DB.WithContext(ctx)}
+}
+
+// EnsureDefault creates the default school when there is none yet and
+// moves rows without a school into it. Kiosks, terminals and geofences of
+// a campus, and devices of an owner, take that campus's or owner's school
+// instead. It also drops the unique indexes that the per-school and
+// per-campus ones replaced. It is safe to run on every start.
+func (r *SchoolRepository) EnsureDefault() (*model.School, error) {
+	school := model.School{Slug: DefaultSchoolSlug, Name: "Default school", Active: true}
+	if err := r.DB.Where(model.School{Slug: DefaultSchoolSlug}).FirstOrCreate(&school).Error; err != nil {
+		return nil, err
+	}
+
+	for _, table := range []string{"teachers", "attendances", "attendance_changes", "holidays", "attendance_periods", "webhook_subscriptions", "webhook_deliveries", "outbox_events"} {
+		err := r.DB.Exec("UPDATE "+table+" SET school_id = ? WHERE school_id IS NULL OR school_id = 0", school.ID).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, table := range []string{"kiosks", "terminals", "geofences"} {
+		err := r.DB.Exec("UPDATE " + table + " t SET school_id = c.school_id FROM campuses c WHERE (t.school_id IS NULL OR t.school_id = 0) AND c.id = t.campus_id").Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := r.DB.Exec("UPDATE devices d SET school_id = t.school_id FROM teachers t WHERE (d.school_id IS NULL OR d.school_id = 0) AND t.id = d.owner_id").Error
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range []string{"kiosks", "terminals", "geofences", "devices"} {
+		err := r.DB.Exec("UPDATE "+table+" SET school_id = ? WHERE school_id IS NULL OR school_id = 0", school.ID).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, index := range []string{"idx_teachers_employee_code", "idx_holidays_date", "idx_attendance_period", "idx_school_holiday", "idx_devices_identifier"} {
+		if err := r.DB.Exec("DROP INDEX IF EXISTS " + index).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return &school, nil
+}
+
+func (r *SchoolRepository) Create(school *model.School) error {
+	return r.DB.Create(school).Error
+}
+
+func (r *SchoolRepository) Update(school *model.School) error {
+	return r.DB.Save(school).Error
+}
+
+func (r *SchoolRepository) GetAll() ([]model.School, error) {
+	var list []model.School
+	err := r.DB.Order("id").Find(&list).Error
+	return list, err
+}
+
+func (r *SchoolRepository) GetByID(id uint) (*model.School, error) {
+	var school model.School
+	err := r.DB.First(&school, id).Error
+	return &school, err
+}
+
+func (r *SchoolRepository) GetBySlug(slug string) (*model.School, error) {
+	var school model.School
+	err := r.DB.Where("slug = ?", slug).First(&school).Error
+	return &school, err
+}
+
+// ForTeacher returns the school the teacher belongs to.
+func (r *SchoolRepository) ForTeacher(teacherID uint) (*model.School, error) {
+	var school model.School
+	err := r.DB.
+		Joins("JOIN teachers ON teachers.school_id = schools.id").
+		Where("teachers.id = ?", teacherID).
+		First(&school).Error
+	return &school, err
+}
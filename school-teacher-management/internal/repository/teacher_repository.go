@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"school-teacher-management/internal/model"
 
 	"gorm.io/gorm"
@@ -14,12 +15,22 @@ func NewTeacherRepository(db *gorm.DB) *TeacherRepository {
 	return &TeacherRepository{DB: db}
 }
 
+func (r *TeacherRepository) WithTx(tx *gorm.DB) *TeacherRepository {
+	return &TeacherRepository{DB: tx}
+}
+
+func (r *TeacherRepository) WithContext(ctx context.Context) *TeacherRepository {
+	return &TeacherRepository{DB: r.DB.WithContext(ctx)}
+}
+
 func (r *TeacherRepository) Create(teacher *model.Teacher) error {
 	return r.DB.Create(teacher).Error
 }
 
-func (r *TeacherRepository) Update(teacher *model.Teacher) error {
-	return r.DB.Save(teacher).Error
+// Update writes the fields of teacher that differ from before, the row as
+// it was read. It fails with ErrStaleVersion when the row changed since.
+func (r *TeacherRepository) Update(before, teacher *model.Teacher) error {
+	return updateChanged(r.DB, before, teacher, before.Version)
 }
 
 func (r *TeacherRepository) GetByID(id uint) (*model.Teacher, error) {
@@ -28,6 +39,26 @@ func (r *TeacherRepository) GetByID(id uint) (*model.Teacher, error) {
 	return &teacher, err
 }
 
+func (r *TeacherRepository) GetByEmployeeCode(code string) (*model.Teacher, error) {
+	var teacher model.Teacher
+	err := r.DB.Where("employee_code = ?", code).First(&teacher).Error
+	return &teacher, err
+}
+
+func (r *TeacherRepository) GetAll() ([]model.Teacher, error) {
+	var teachers []model.Teacher
+	err := r.DB.Order("id").Find(&teachers).Error
+	return teachers, err
+}
+
+func (r *TeacherRepository) FindDepartmentHeads(department string) ([]model.Teacher, error) {
+	var teachers []model.Teacher
+	err := r.DB.
+		Where("department = ? AND head_of_department = ?", department, true).
+		Find(&teachers).Error
+	return teachers, err
+}
+
 func (r *TeacherRepository) SearchAllFields(q string, subject string) ([]model.Teacher, error) {
 	var teachers []model.Teacher
 	db := r.DB.Model(&model.Teacher{})
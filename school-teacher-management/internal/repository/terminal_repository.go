@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"school-teacher-management/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TerminalRepository struct {
+	DB *gorm.DB
+}
+
+func NewTerminalRepository(db *gorm.DB) *TerminalRepository {
+	return &TerminalRepository{DB: db}
+}
+
+func (r *TerminalRepository) WithTx(tx *gorm.DB) *TerminalRepository {
+	return &TerminalRepository{DB: tx}
+}
+
+func (r *TerminalRepository) WithContext(ctx context.Context) *TerminalRepository {
+	return &TerminalRepository{DB: r.DB.WithContext(ctx)}
+}
+
+func (r *TerminalRepository) Create(terminal *model.Terminal) error {
+	return r.DB.Create(terminal).Error
+}
+
+func (r *TerminalRepository) Update(terminal *model.Terminal) error {
+	return r.DB.Save(terminal).Error
+}
+
+func (r *TerminalRepository) GetAll() ([]model.Terminal, error) {
+	var list []model.Terminal
+	err := r.DB.Order("id").Find(&list).Error
+	return list, err
+}
+
+func (r *TerminalRepository) GetByID(id uint) (*model.Terminal, error) {
+	var terminal model.Terminal
+	err := r.DB.First(&terminal, id).Error
+	return &terminal, err
+}
+
+func (r *TerminalRepository) GetByKeyHash(hash string) (*model.Terminal, error) {
+	var terminal model.Terminal
+	err := r.DB.Where("key_hash = ?", hash).First(&terminal).Error
+	return &terminal, err
+}
+
+// SavePIN replaces the teacher's PIN and clears any lockout.
+func (r *TerminalRepository) SavePIN(pin *model.TeacherPIN) error {
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "teacher_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"pin_hash", "failed_attempts", "locked_until", "updated_at"}),
+	}).Create(pin).Error
+}
+
+// LockPIN returns the teacher's PIN row locked for update.
+func (r *TerminalRepository) LockPIN(teacherID uint) (*model.TeacherPIN, error) {
+	var pin model.TeacherPIN
+	err := r.DB.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&pin, "teacher_id = ?", teacherID).Error
+	return &pin, err
+}
+
+func (r *TerminalRepository) RecordPINResult(teacherID uint, failedAttempts int, lockedUntil *time.Time) error {
+	return r.DB.Model(&model.TeacherPIN{}).
+		Where("teacher_id = ?", teacherID).
+		Updates(map[string]interface{}{
+			"failed_attempts": failedAttempts,
+			"locked_until":    lockedUntil,
+		}).Error
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// ErrStaleVersion is returned by the updates of versioned rows when the
+// row was changed after it was read.
+var ErrStaleVersion = errors.New("record was changed after it was read")
+
+// updateChanged writes the columns in which after differs from before and
+// bumps the version column, provided the row still has version. Nothing is
+// written when nothing changed. after is then reloaded, so it carries the
+// stored version and update time.
+func updateChanged(db *gorm.DB, before, after interface{}, version uint) error {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(after); err != nil {
+		return err
+	}
+
+	ctx := db.Statement.Context
+	beforeValue := reflect.Indirect(reflect.ValueOf(before))
+	afterValue := reflect.Indirect(reflect.ValueOf(after))
+
+	changes := map[string]interface{}{}
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" || field.PrimaryKey || field.DBName == "version" ||
+			field.AutoCreateTime != 0 || field.AutoUpdateTime != 0 {
+			continue
+		}
+
+		old, _ := field.ValueOf(ctx, beforeValue)
+		value, _ := field.ValueOf(ctx, afterValue)
+		if !reflect.DeepEqual(old, value) {
+			changes[field.DBName] = value
+		}
+	}
+
+	id, _ := stmt.Schema.PrioritizedPrimaryField.ValueOf(ctx, afterValue)
+	if len(changes) == 0 {
+		return db.First(after, id).Error
+	}
+	changes["version"] = gorm.Expr("version + 1")
+
+	// A fresh model receives the assigned values, so after keeps its own
+	// until it is reloaded.
+	result := db.Model(reflect.New(afterValue.Type()).Interface()).
+		Where("id = ? AND version = ?", id, version).
+		Updates(changes)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStaleVersion
+	}
+
+	return db.First(after, id).Error
+}
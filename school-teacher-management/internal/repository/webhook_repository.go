@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"school-teacher-management/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository struct {
+	DB *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{DB: db}
+}
+
+func (r *WebhookRepository) WithTx(tx *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{DB: tx}
+}
+
+func (r *WebhookRepository) WithContext(ctx context.Context) *WebhookRepository {
+	return &WebhookRepository{DB: r.DB.WithContext(ctx)}
+}
+
+func (r *WebhookRepository) Create(sub *model.WebhookSubscription) error {
+	return r.DB.Create(sub).Error
+}
+
+func (r *WebhookRepository) Update(sub *model.WebhookSubscription) error {
+	return r.DB.Save(sub).Error
+}
+
+func (r *WebhookRepository) GetAll() ([]model.WebhookSubscription, error) {
+	var list []model.WebhookSubscription
+	err := r.DB.Order("id").Find(&list).Error
+	return list, err
+}
+
+func (r *WebhookRepository) GetActive() ([]model.WebhookSubscription, error) {
+	var list []model.WebhookSubscription
+	err := r.DB.Where("active = ?", true).Find(&list).Error
+	return list, err
+}
+
+func (r *WebhookRepository) GetByID(id uint) (*model.WebhookSubscription, error) {
+	var sub model.WebhookSubscription
+	err := r.DB.First(&sub, id).Error
+	return &sub, err
+}
+
+func (r *WebhookRepository) Delete(id uint) error {
+	result := r.DB.Delete(&model.WebhookSubscription{}, id)
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return result.Error
+}
+
+func (r *WebhookRepository) CreateDeliveries(deliveries []model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.DB.Create(&deliveries).Error
+}
+
+// LeaseDueDeliveries claims pending deliveries whose next attempt is due by
+// pushing their next attempt past the lease, so that other instances leave
+// them alone while this one sends them.
+func (r *WebhookRepository) LeaseDueDeliveries(limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	var list []model.WebhookDelivery
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.DeliveryPending, time.Now()).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&list).Error
+		if err != nil || len(list) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(list))
+		for _, d := range list {
+			ids = append(ids, d.ID)
+		}
+
+		return tx.
+			Model(&model.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", time.Now().Add(lease)).Error
+	})
+
+	return list, err
+}
+
+func (r *WebhookRepository) UpdateDelivery(delivery *model.WebhookDelivery) error {
+	return r.DB.Save(delivery).Error
+}
+
+func (r *WebhookRepository) GetDelivery(id uint) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	err := r.DB.First(&delivery, id).Error
+	return &delivery, err
+}
+
+func (r *WebhookRepository) FindDeliveries(subscriptionID uint, status string) ([]model.WebhookDelivery, error) {
+	var list []model.WebhookDelivery
+	db := r.DB.Model(&model.WebhookDelivery{})
+
+	if subscriptionID != 0 {
+		db = db.Where("subscription_id = ?", subscriptionID)
+	}
+
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+
+	err := db.Order("id DESC").Limit(500).Find(&list).Error
+	return list, err
+}
@@ -0,0 +1,53 @@
+// Package requestctx carries per-request values from the HTTP layer into
+// services through context.Context.
+package requestctx
+
+import (
+	"context"
+
+	"school-teacher-management/internal/model"
+)
+
+type key int
+
+const (
+	actorKey key = iota
+	requestIDKey
+	schoolKey
+)
+
+func WithActor(ctx context.Context, actor model.Actor) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// Actor returns the authenticated caller, or an empty actor for anonymous
+// requests and background jobs.
+func Actor(ctx context.Context) model.Actor {
+	actor, _ := ctx.Value(actorKey).(model.Actor)
+	return actor
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithSchool scopes the request to one school. A schoolID of 0 removes the
+// scope, for trust-level administrators.
+func WithSchool(ctx context.Context, schoolID uint) context.Context {
+	return context.WithValue(ctx, schoolKey, schoolID)
+}
+
+// School returns the school the request is scoped to, if any. Background
+// jobs and trust-level administrators work across all schools.
+func School(ctx context.Context) (uint, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	id, _ := ctx.Value(schoolKey).(uint)
+	return id, id != 0
+}
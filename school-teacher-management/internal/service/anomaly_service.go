@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/config"
+	"school-teacher-management/internal/geo"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/requestctx"
+	"school-teacher-management/internal/tracing"
+	"sort"
+	"time"
+)
+
+var ErrIncidentNotFound = apperror.NotFound("incident_not_found", "incident not found")
+
+// Located punches closer than this are never impossible travel; it keeps
+// GPS jitter on one campus from being flagged.
+const minTravelMeters = 1000
+
+type AnomalyService struct {
+	Repo       *repository.IncidentRepository
+	Attendance *repository.AttendanceRepository
+	Devices    *repository.DeviceRepository
+	Holidays   *repository.HolidayRepository
+	Networks   *repository.NetworkRepository
+	Policy     config.AnomalyPolicy
+}
+
+func NewAnomalyService(
+	repo *repository.IncidentRepository,
+	attendance *repository.AttendanceRepository,
+	devices *repository.DeviceRepository,
+	holidays *repository.HolidayRepository,
+	networks *repository.NetworkRepository,
+	policy config.AnomalyPolicy,
+) *AnomalyService {
+	return &AnomalyService{
+		Repo:       repo,
+		Attendance: attendance,
+		Devices:    devices,
+		Holidays:   holidays,
+		Networks:   networks,
+		Policy:     policy,
+	}
+}
+
+// Run scans the last Lookback days every Interval until ctx is done.
+func (s *AnomalyService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Policy.Interval)
+	defer ticker.Stop()
+
+	for {
+		today := time.Now().Truncate(24 * time.Hour)
+		if _, err := s.Scan(ctx, today.AddDate(0, 0, -s.Policy.Lookback), today); err != nil {
+			slog.ErrorContext(ctx, "anomaly scan", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scannedPunch is one check-in or check-out of an attendance record.
+type scannedPunch struct {
+	att      *model.Attendance
+	at       time.Time
+	kind     string
+	deviceID *uint
+	ip       string
+	location *model.PunchLocation
+	kiosk    bool
+}
+
+// Scan flags suspicious patterns in attendance dated from start to end and
+// returns the number of new incidents. Findings flagged by an earlier scan
+// are not flagged again. A request scoped to a school scans only that
+// school.
+func (s *AnomalyService) Scan(ctx context.Context, start, end time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "AnomalyService.Scan")
+	defer span.End()
+
+	list, err := s.Attendance.WithContext(ctx).FindBetween(start, end)
+	if err != nil {
+		return 0, err
+	}
+
+	devices, err := s.Devices.WithContext(ctx).Find(0)
+	if err != nil {
+		return 0, err
+	}
+	shared := map[uint]bool{}
+	for _, d := range devices {
+		shared[d.ID] = d.Shared
+	}
+
+	holidays, err := s.Holidays.WithContext(ctx).FindBetween(start, end)
+	if err != nil {
+		return 0, err
+	}
+
+	networks, err := s.Networks.WithContext(ctx).GetAll()
+	if err != nil {
+		return 0, err
+	}
+	campusNets := campusNetworks{}
+	for _, n := range networks {
+		if prefix, err := netip.ParsePrefix(n.CIDR); err == nil {
+			campusNets[n.SchoolID] = append(campusNets[n.SchoolID], prefix)
+		}
+	}
+
+	var punches []scannedPunch
+	for i := range list {
+		att := &list[i]
+		// Terminal punches come from one shared machine by design.
+		if att.CheckIn != nil && att.CheckInTerminalID == nil {
+			punches = append(punches, scannedPunch{att, *att.CheckIn, "check-in", att.CheckInDeviceID, att.CheckInIP, att.CheckInLocation, att.CheckInKioskID != nil})
+		}
+		if att.CheckOut != nil && att.CheckOutTerminalID == nil {
+			punches = append(punches, scannedPunch{att, *att.CheckOut, "check-out", att.CheckOutDeviceID, att.CheckOutIP, att.CheckOutLocation, att.CheckOutKioskID != nil})
+		}
+	}
+	sort.SliceStable(punches, func(i, j int) bool {
+		return punches[i].at.Before(punches[j].at)
+	})
+
+	var incidents []model.Incident
+	incidents = append(incidents, s.sharedSources(punches, shared, campusNets)...)
+	incidents = append(incidents, s.repeatedTimes(list)...)
+	incidents = append(incidents, s.impossibleTravel(punches)...)
+	incidents = append(incidents, holidayCheckIns(list, holidays)...)
+
+	flagged := 0
+	for i := range incidents {
+		incidents[i].Status = model.IncidentOpen
+		stored, err := s.Repo.WithContext(ctx).Flag(&incidents[i])
+		if err != nil {
+			return flagged, err
+		}
+		if stored {
+			flagged++
+		}
+	}
+	return flagged, nil
+}
+
+// campusNetworks are the parsed campus network ranges of each school.
+type campusNetworks map[uint][]netip.Prefix
+
+// contains reports whether ip is inside a campus network of the school.
+func (n campusNetworks) contains(schoolID uint, ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range n[schoolID] {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedSources flags punches by different teachers from the same
+// non-shared device, or the same address, within SameSourceWindow. The
+// later punch is the suspect one. Kiosk punches are made in front of the
+// kiosk, and teachers on a campus network share its address, so neither
+// is compared.
+func (s *AnomalyService) sharedSources(punches []scannedPunch, shared map[uint]bool, nets campusNetworks) []model.Incident {
+	var incidents []model.Incident
+
+	for j := range punches {
+		b := punches[j]
+		if b.kiosk {
+			continue
+		}
+		for i := j - 1; i >= 0 && b.at.Sub(punches[i].at) <= s.Policy.SameSourceWindow; i-- {
+			a := punches[i]
+			if a.att.TeacherID == b.att.TeacherID || a.kiosk {
+				continue
+			}
+
+			gap := b.at.Sub(a.at).Round(time.Second)
+			related := func(kind, source string) model.Incident {
+				return model.Incident{
+					Kind:                kind,
+					Fingerprint:         fmt.Sprintf("%s:%d:%s:%d:%s", kind, a.att.ID, a.kind, b.att.ID, b.kind),
+					TeacherID:           b.att.TeacherID,
+					RelatedTeacherID:    &a.att.TeacherID,
+					AttendanceID:        &b.att.ID,
+					RelatedAttendanceID: &a.att.ID,
+					Date:                b.att.Date,
+					Details: fmt.Sprintf("%s from %s %s after a %s by teacher %d",
+						b.kind, source, gap, a.kind, a.att.TeacherID),
+				}
+			}
+
+			if a.deviceID != nil && b.deviceID != nil && *a.deviceID == *b.deviceID && !shared[*a.deviceID] {
+				incidents = append(incidents, related(model.IncidentSharedDevice, fmt.Sprintf("device %d", *a.deviceID)))
+			}
+			if a.ip != "" && a.ip == b.ip && !nets.contains(b.att.SchoolID, b.ip) {
+				incidents = append(incidents, related(model.IncidentSharedIP, "address "+a.ip))
+			}
+		}
+	}
+	return incidents
+}
+
+// repeatedTimes flags teachers checking in at the same second of the day
+// on RepeatedDays or more days, which points to scripted or proxy punches.
+func (s *AnomalyService) repeatedTimes(list []model.Attendance) []model.Incident {
+	type key struct {
+		teacherID uint
+		clock     string
+	}
+	days := map[key][]*model.Attendance{}
+	var order []key
+
+	for i := range list {
+		att := &list[i]
+		if att.CheckIn == nil {
+			continue
+		}
+		k := key{att.TeacherID, att.CheckIn.Local().Format("15:04:05")}
+		if days[k] == nil {
+			order = append(order, k)
+		}
+		days[k] = append(days[k], att)
+	}
+
+	var incidents []model.Incident
+	for _, k := range order {
+		matches := days[k]
+		if len(matches) < s.Policy.RepeatedDays {
+			continue
+		}
+		latest := matches[len(matches)-1]
+		incidents = append(incidents, model.Incident{
+			Kind:         model.IncidentRepeatedTime,
+			Fingerprint:  fmt.Sprintf("%s:%d:%s:%d", model.IncidentRepeatedTime, k.teacherID, k.clock, latest.ID),
+			TeacherID:    k.teacherID,
+			AttendanceID: &latest.ID,
+			Date:         latest.Date,
+			Details: fmt.Sprintf("checked in at exactly %s on %d days, from %s to %s",
+				k.clock, len(matches), matches[0].Date.Format("2006-01-02"), latest.Date.Format("2006-01-02")),
+		})
+	}
+	return incidents
+}
+
+// impossibleTravel flags consecutive located punches of a teacher that are
+// further apart than a trip at MaxTravelKmh allows in the time between.
+func (s *AnomalyService) impossibleTravel(punches []scannedPunch) []model.Incident {
+	var incidents []model.Incident
+	last := map[uint]scannedPunch{}
+
+	for _, b := range punches {
+		if b.location == nil || b.location.Latitude == nil || b.location.Longitude == nil {
+			continue
+		}
+		a, ok := last[b.att.TeacherID]
+		last[b.att.TeacherID] = b
+		if !ok {
+			continue
+		}
+
+		meters := geo.Distance(
+			geo.Point{Lat: *a.location.Latitude, Lng: *a.location.Longitude},
+			geo.Point{Lat: *b.location.Latitude, Lng: *b.location.Longitude},
+		)
+		if meters < minTravelMeters {
+			continue
+		}
+
+		hours := b.at.Sub(a.at).Hours()
+		if hours > 0 && meters/1000/hours <= s.Policy.MaxTravelKmh {
+			continue
+		}
+
+		incidents = append(incidents, model.Incident{
+			Kind:                model.IncidentImpossibleTravel,
+			Fingerprint:         fmt.Sprintf("%s:%d:%s:%d:%s", model.IncidentImpossibleTravel, a.att.ID, a.kind, b.att.ID, b.kind),
+			TeacherID:           b.att.TeacherID,
+			AttendanceID:        &b.att.ID,
+			RelatedAttendanceID: &a.att.ID,
+			Date:                b.att.Date,
+			Details: fmt.Sprintf("%s at %s is %.1f km from the %s at %s only %s earlier",
+				b.kind, placeName(b.location), meters/1000, a.kind, placeName(a.location), b.at.Sub(a.at).Round(time.Second)),
+		})
+	}
+	return incidents
+}
+
+func placeName(location *model.PunchLocation) string {
+	if location.Geofence != "" {
+		return location.Geofence
+	}
+	return fmt.Sprintf("%.5f,%.5f", *location.Latitude, *location.Longitude)
+}
+
+// holidayCheckIns flags check-ins on a holiday of the whole school, or of
+// the campus checked in at.
+func holidayCheckIns(list []model.Attendance, holidays []model.Holiday) []model.Incident {
+	type schoolDay struct {
+		schoolID uint
+		campusID uint
+		date     string
+	}
+
+	names := map[schoolDay]string{}
+	for _, h := range holidays {
+		names[schoolDay{h.SchoolID, h.CampusID, h.Date.Format("2006-01-02")}] = h.Name
+	}
+
+	var incidents []model.Incident
+	for i := range list {
+		att := &list[i]
+		if att.CheckIn == nil {
+			continue
+		}
+		date := att.Date.Format("2006-01-02")
+		name, ok := names[schoolDay{att.SchoolID, 0, date}]
+		if !ok && att.CheckInCampusID != nil {
+			name, ok = names[schoolDay{att.SchoolID, *att.CheckInCampusID, date}]
+		}
+		if !ok {
+			continue
+		}
+		incidents = append(incidents, model.Incident{
+			Kind:         model.IncidentHolidayCheckIn,
+			Fingerprint:  fmt.Sprintf("%s:%d", model.IncidentHolidayCheckIn, att.ID),
+			TeacherID:    att.TeacherID,
+			AttendanceID: &att.ID,
+			Date:         att.Date,
+			Details:      "checked in on the holiday " + name,
+		})
+	}
+	return incidents
+}
+
+func (s *AnomalyService) GetIncident(ctx context.Context, id uint) (*model.Incident, error) {
+	ctx, span := tracing.Start(ctx, "AnomalyService.GetIncident")
+	defer span.End()
+
+	v, err := s.Repo.WithContext(ctx).GetByID(id)
+	return v, notFound(err, ErrIncidentNotFound)
+}
+
+func (s *AnomalyService) GetIncidents(ctx context.Context, filter model.IncidentFilter) ([]model.Incident, error) {
+	ctx, span := tracing.Start(ctx, "AnomalyService.GetIncidents")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).Find(filter)
+}
+
+// ReviewIncident closes an open incident as resolved (the suspicion was
+// confirmed and dealt with) or dismissed (a false positive).
+func (s *AnomalyService) ReviewIncident(ctx context.Context, id uint, status string, note string) (*model.Incident, error) {
+	ctx, span := tracing.Start(ctx, "AnomalyService.ReviewIncident")
+	defer span.End()
+
+	actor := requestctx.Actor(ctx)
+
+	repo := s.Repo.WithContext(ctx)
+
+	incident, err := repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if incident.Status != model.IncidentOpen {
+		return nil, apperror.Conflict("incident_already_reviewed", "incident has already been "+incident.Status)
+	}
+
+	if actor.TeacherID != 0 && actor.TeacherID == incident.TeacherID {
+		return nil, apperror.Forbidden("own_incident", "cannot review an incident about yourself")
+	}
+
+	now := time.Now()
+	incident.Status = status
+	incident.ReviewedBy = &actor
+	incident.ReviewNote = note
+	incident.ReviewedAt = &now
+
+	if err := repo.Update(incident); err != nil {
+		return nil, err
+	}
+	return incident, nil
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/requestctx"
+	"school-teacher-management/internal/tracing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrCorrectionNotFound = apperror.NotFound("correction_not_found", "correction not found")
+
+// RequestCorrection records a teacher's request to fix the punches of one
+// day. Nothing changes until an approver accepts it. The date and the
+// corrected times are taken in the school's timezone.
+func (s *AttendanceService) RequestCorrection(ctx context.Context, input *model.AttendanceCorrectionInput) (*model.AttendanceCorrection, error) {
+	ctx, span := tracing.Start(ctx, "AttendanceService.RequestCorrection")
+	defer span.End()
+
+	date, err := time.Parse("2006-01-02", input.Date)
+	if err != nil {
+		return nil, apperror.Field("date", "format", "must be YYYY-MM-DD")
+	}
+
+	if input.CheckIn == nil && input.CheckOut == nil {
+		return nil, apperror.Validation("punch_required", "check_in or check_out is required")
+	}
+
+	school, err := s.Schools.WithContext(ctx).ForTeacher(input.TeacherID)
+	if err != nil {
+		return nil, ErrTeacherNotFound
+	}
+
+	if date.After(school.Today(time.Now())) {
+		return nil, apperror.Rule("future_date", "cannot correct a future date")
+	}
+
+	for _, t := range []*time.Time{input.CheckIn, input.CheckOut} {
+		if t != nil && !school.Today(*t).Equal(date) {
+			return nil, apperror.Rule("punch_outside_date", "corrected times must fall on the corrected date")
+		}
+	}
+
+	// Checked again when the correction is approved.
+	if err := s.ensureOpen(s.Repo.DB.WithContext(ctx), school.ID, date); err != nil {
+		return nil, err
+	}
+
+	pending, err := s.Corrections.WithContext(ctx).CountPending(input.TeacherID, date)
+	if err != nil {
+		return nil, err
+	}
+	if pending > 0 {
+		return nil, apperror.Conflict("correction_pending", "a correction for this date is already pending")
+	}
+
+	correction := &model.AttendanceCorrection{
+		TeacherID:   input.TeacherID,
+		Date:        date,
+		CheckIn:     input.CheckIn,
+		CheckOut:    input.CheckOut,
+		Reason:      input.Reason,
+		Status:      model.CorrectionPending,
+		RequestedBy: requestctx.Actor(ctx),
+	}
+
+	var existing model.Attendance
+	if err := s.Repo.WithContext(ctx).FindByTeacherAndDate(input.TeacherID, date, &existing); err == nil {
+		correction.AttendanceID = &existing.ID
+		if err := checkPunchOrder(&existing, correction); err != nil {
+			return nil, err
+		}
+	} else if input.CheckIn == nil {
+		return nil, apperror.Rule("check_in_required", "no attendance for this date, check_in is required")
+	}
+
+	if err := s.Corrections.WithContext(ctx).Create(correction); err != nil {
+		return nil, err
+	}
+
+	return s.Corrections.WithContext(ctx).GetByID(correction.ID)
+}
+
+func (s *AttendanceService) GetCorrection(ctx context.Context, id uint) (*model.AttendanceCorrection, error) {
+	ctx, span := tracing.Start(ctx, "AttendanceService.GetCorrection")
+	defer span.End()
+
+	correction, err := s.Corrections.WithContext(ctx).GetByID(id)
+	return correction, notFound(err, ErrCorrectionNotFound)
+}
+
+func (s *AttendanceService) GetCorrections(ctx context.Context, teacherID uint, department string, status string) ([]model.AttendanceCorrection, error) {
+	ctx, span := tracing.Start(ctx, "AttendanceService.GetCorrections")
+	defer span.End()
+
+	return s.Corrections.WithContext(ctx).Find(teacherID, department, status)
+}
+
+func (s *AttendanceService) GetAttendanceHistory(ctx context.Context, attendanceID uint) ([]model.AttendanceChange, error) {
+	ctx, span := tracing.Start(ctx, "AttendanceService.GetAttendanceHistory")
+	defer span.End()
+
+	return s.Corrections.WithContext(ctx).FindChanges(attendanceID)
+}
+
+// RejectCorrection closes a pending request without touching attendance.
+func (s *AttendanceService) RejectCorrection(ctx context.Context, id uint, note string) (*model.AttendanceCorrection, error) {
+	ctx, span := tracing.Start(ctx, "AttendanceService.RejectCorrection")
+	defer span.End()
+
+	actor := requestctx.Actor(ctx)
+
+	correction, err := s.pendingCorrection(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	decide(correction, model.CorrectionRejected, actor, note)
+	if err := s.Corrections.WithContext(ctx).Update(correction); err != nil {
+		return nil, err
+	}
+	return correction, nil
+}
+
+// ApproveCorrection applies the requested punches to the day's attendance,
+// creating the row for a missed check-in, and records the change.
+func (s *AttendanceService) ApproveCorrection(ctx context.Context, id uint, note string) (*model.AttendanceCorrection, error) {
+	ctx, span := tracing.Start(ctx, "AttendanceService.ApproveCorrection")
+	defer span.End()
+
+	actor := requestctx.Actor(ctx)
+
+	correction, err := s.pendingCorrection(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	school, err := s.Schools.WithContext(ctx).ForTeacher(correction.TeacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	var event model.AttendanceEvent
+	err = s.lockDay(ctx, correction.TeacherID, correction.Date, func(tx *gorm.DB, existing *model.Attendance) error {
+		att := &model.Attendance{SchoolID: school.ID, TeacherID: correction.TeacherID, Date: correction.Date}
+		var before *model.Attendance
+		if existing != nil {
+			snapshot := *existing
+			before = &snapshot
+			att = existing
+			if err := checkPunchOrder(att, correction); err != nil {
+				return err
+			}
+		}
+
+		if correction.CheckIn != nil {
+			if err := checkIn(att); err != nil {
+				return err
+			}
+			att.CheckIn = correction.CheckIn
+		}
+		if correction.CheckOut != nil {
+			att.CheckOut = correction.CheckOut
+		}
+		if err := checkPunches(att); err != nil {
+			return err
+		}
+
+		decide(correction, model.CorrectionApproved, actor, note)
+
+		var err error
+		event, err = s.commitTx(ctx, tx, model.EventCorrection, before, att, func(tx *gorm.DB) error {
+			repo := s.Repo.WithTx(tx)
+			if before == nil {
+				if err := repo.CreateDay(att); err != nil {
+					return err
+				}
+			} else if err := repo.Update(before, att); err != nil {
+				return err
+			}
+
+			correction.AttendanceID = &att.ID
+			if err := s.Corrections.WithTx(tx).Update(correction); err != nil {
+				return err
+			}
+
+			return s.recordChange(ctx, tx, model.ChangeSourceCorrection, before, att, &correction.ID, correction.Reason)
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, event)
+	return correction, nil
+}
+
+func (s *AttendanceService) pendingCorrection(ctx context.Context, id uint, actor model.Actor) (*model.AttendanceCorrection, error) {
+	correction, err := s.Corrections.WithContext(ctx).GetByID(id)
+	if err != nil {
+		return nil, notFound(err, ErrCorrectionNotFound)
+	}
+
+	if correction.Status != model.CorrectionPending {
+		return nil, apperror.Conflict("correction_already_decided", "correction has already been "+correction.Status)
+	}
+
+	if actor.TeacherID != 0 && actor.TeacherID == correction.TeacherID {
+		return nil, apperror.Forbidden("own_correction", "cannot decide your own correction")
+	}
+
+	return correction, nil
+}
+
+func decide(correction *model.AttendanceCorrection, status string, actor model.Actor, note string) {
+	now := time.Now()
+	correction.Status = status
+	correction.DecidedBy = &actor
+	correction.DecisionNote = note
+	correction.DecidedAt = &now
+}
+
+// checkPunchOrder rejects a correction that would leave check-out before
+// check-in once merged with the punches already recorded.
+func checkPunchOrder(existing *model.Attendance, correction *model.AttendanceCorrection) error {
+	checkIn, checkOut := existing.CheckIn, existing.CheckOut
+	if correction.CheckIn != nil {
+		checkIn = correction.CheckIn
+	}
+	if correction.CheckOut != nil {
+		checkOut = correction.CheckOut
+	}
+
+	if checkOut != nil && (checkIn == nil || !checkOut.After(*checkIn)) {
+		return apperror.Rule("punch_order", "check_out must be after check_in")
+	}
+	return nil
+}
+
+type attendanceSnapshot struct {
+	ID        uint                   `json:"id"`
+	TeacherID uint                   `json:"teacher_id"`
+	Date      string                 `json:"date"`
+	Status    model.AttendanceStatus `json:"status"`
+	CheckIn   *time.Time             `json:"check_in"`
+	CheckOut  *time.Time             `json:"check_out"`
+}
+
+func snapshotAttendance(att *model.Attendance) interface{} {
+	if att == nil {
+		return nil
+	}
+	return attendanceSnapshot{
+		ID:        att.ID,
+		TeacherID: att.TeacherID,
+		Date:      att.Date.Format("2006-01-02"),
+		Status:    att.Status,
+		CheckIn:   att.CheckIn,
+		CheckOut:  att.CheckOut,
+	}
+}
+
+// recordChange stores the before and after values of an attendance row; a
+// nil before means the row was created, a nil after that it was deleted.
+func (s *AttendanceService) recordChange(
+	ctx context.Context,
+	tx *gorm.DB,
+	source string,
+	before, after *model.Attendance,
+	correctionID *uint,
+	reason string,
+) error {
+	beforeJSON, err := model.NewJSON(snapshotAttendance(before))
+	if err != nil {
+		return err
+	}
+
+	afterJSON, err := model.NewJSON(snapshotAttendance(after))
+	if err != nil {
+		return err
+	}
+
+	row := after
+	if row == nil {
+		row = before
+	}
+
+	return s.Corrections.WithTx(tx).AddChange(&model.AttendanceChange{
+		SchoolID:     row.SchoolID,
+		AttendanceID: row.ID,
+		CorrectionID: correctionID,
+		Source:       source,
+		Actor:        requestctx.Actor(ctx),
+		Before:       beforeJSON,
+		After:        afterJSON,
+		Reason:       reason,
+	})
+}
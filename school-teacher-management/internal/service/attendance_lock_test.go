@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	lockDayQuery   = `SELECT \* FROM "attendances" WHERE teacher_id = \$1 AND date = \$2 .*FOR UPDATE`
+	createDayQuery = `INSERT INTO "attendances" .* ON CONFLICT \("teacher_id","date"\) DO NOTHING`
+)
+
+// newMockAttendanceService returns a service whose repository talks to a
+// mocked database, so lockDay can be run without Postgres.
+func newMockAttendanceService(t *testing.T) (*AttendanceService, sqlmock.Sqlmock) {
+	t.Helper()
+
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	return &AttendanceService{Repo: repository.NewAttendanceRepository(db)}, mock
+}
+
+// expectDayTaken expects a transaction that finds no record for the day
+// and then loses the insert to a concurrent one.
+func expectDayTaken(mock sqlmock.Sqlmock) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockDayQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(createDayQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+}
+
+// createDay is the lockDay callback of a check-in: it creates the day's
+// record when there is none. It records the records it was given.
+func createDay(s *AttendanceService, day time.Time, seen *[]*model.Attendance) func(*gorm.DB, *model.Attendance) error {
+	return func(tx *gorm.DB, existing *model.Attendance) error {
+		*seen = append(*seen, existing)
+		if existing != nil {
+			return nil
+		}
+		return s.Repo.WithTx(tx).CreateDay(&model.Attendance{TeacherID: 1, Date: day, Status: model.StatusPresent})
+	}
+}
+
+// TestLockDayRetriesTakenDay has a concurrent request create the day's
+// record first. The punch is applied again, to that record.
+func TestLockDayRetriesTakenDay(t *testing.T) {
+	s, mock := newMockAttendanceService(t)
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	expectDayTaken(mock)
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockDayQuery).WillReturnRows(sqlmock.NewRows([]string{"id", "teacher_id", "date"}).AddRow(7, 1, day))
+	mock.ExpectCommit()
+
+	var seen []*model.Attendance
+	if err := s.lockDay(context.Background(), 1, day, createDay(s, day, &seen)); err != nil {
+		t.Fatalf("lockDay: %v", err)
+	}
+
+	if len(seen) != 2 || seen[0] != nil || seen[1] == nil || seen[1].ID != 7 {
+		t.Errorf("callback saw %v, want no record and then record 7", seen)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestLockDayContended loses the insert on the retry too, which is
+// reported as ErrDayContended rather than the repository error.
+func TestLockDayContended(t *testing.T) {
+	s, mock := newMockAttendanceService(t)
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	expectDayTaken(mock)
+	expectDayTaken(mock)
+
+	var seen []*model.Attendance
+	err := s.lockDay(context.Background(), 1, day, createDay(s, day, &seen))
+	if !errors.Is(err, ErrDayContended) {
+		t.Fatalf("lockDay: got %v, want ErrDayContended", err)
+	}
+
+	if len(seen) != 2 {
+		t.Errorf("callback ran %d times, want 2", len(seen))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -1,95 +1,483 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"log/slog"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/events"
 	"school-teacher-management/internal/metrics"
 	"school-teacher-management/internal/model"
 	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/tracing"
 	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAttendanceNotFound = apperror.NotFound("attendance_not_found", "attendance not found")
+	// ErrDayContended is returned when the day's record kept changing under
+	// a punch, even after it was retried.
+	ErrDayContended = apperror.Conflict("attendance_day_contended", "attendance for the day is being recorded by another request; retry")
 )
 
 type AttendanceService struct {
-	Repo *repository.AttendanceRepository
+	Repo        *repository.AttendanceRepository
+	Corrections *repository.CorrectionRepository
+	Outbox      *repository.OutboxRepository
+	Periods     *repository.PeriodRepository
+	Schools     *repository.SchoolRepository
+	Campuses    *repository.CampusRepository
+	Overtime    *OvertimeService
+	Geofences   *GeofenceService
+	Networks    *NetworkService
+	Devices     *DeviceService
+	Audit       *AuditService
+	Events      events.Bus
 }
 
-func NewAttendanceService(repo *repository.AttendanceRepository) *AttendanceService {
-	return &AttendanceService{Repo: repo}
+func NewAttendanceService(
+	repo *repository.AttendanceRepository,
+	corrections *repository.CorrectionRepository,
+	outbox *repository.OutboxRepository,
+	periods *repository.PeriodRepository,
+	schools *repository.SchoolRepository,
+	campuses *repository.CampusRepository,
+	overtime *OvertimeService,
+	geofences *GeofenceService,
+	networks *NetworkService,
+	devices *DeviceService,
+	audit *AuditService,
+	bus events.Bus,
+) *AttendanceService {
+	return &AttendanceService{
+		Repo:        repo,
+		Corrections: corrections,
+		Outbox:      outbox,
+		Periods:     periods,
+		Schools:     schools,
+		Campuses:    campuses,
+		Overtime:    overtime,
+		Geofences:   geofences,
+		Networks:    networks,
+		Devices:     devices,
+		Audit:       audit,
+		Events:      bus,
+	}
 }
 
-func (s *AttendanceService) CreateAttendance(att *model.Attendance) error {
-	return s.Repo.Create(att)
-}
+// GetAttendances returns every record, or with deviceID or campusID set
+// those punched in or out from that device or at that campus.
+func (s *AttendanceService) GetAttendances(ctx context.Context, deviceID, campusID uint) ([]model.Attendance, error) {
+	ctx, span := tracing.Start(ctx, "AttendanceService.GetAttendances")
+	defer span.End()
 
-func (s *AttendanceService) GetAttendances() ([]model.Attendance, error) {
-	return s.Repo.GetAll()
+	return s.Repo.WithContext(ctx).GetAll(deviceID, campusID)
 }
 
-func (s *AttendanceService) GetAttendance(id uint) (*model.Attendance, error) {
-	return s.Repo.GetByID(id)
+func (s *AttendanceService) GetAttendance(ctx context.Context, id uint) (*model.Attendance, error) {
+	ctx, span := tracing.Start(ctx, "AttendanceService.GetAttendance")
+	defer span.End()
+
+	att, err := s.Repo.WithContext(ctx).GetByID(id)
+	return att, notFound(err, ErrAttendanceNotFound)
 }
 
-func (s *AttendanceService) UpdateAttendance(att *model.Attendance) error {
-	return s.Repo.Update(att)
+// UpdateAttendance is the administrator's direct edit; the previous and new
+// values are kept in the attendance history. A non-zero version must match
+// the stored one. Only the changed fields are written.
+func (s *AttendanceService) UpdateAttendance(ctx context.Context, att *model.Attendance, version uint) error {
+	ctx, span := tracing.Start(ctx, "AttendanceService.UpdateAttendance")
+	defer span.End()
+
+	before, err := s.Repo.WithContext(ctx).GetByID(att.ID)
+	if err != nil {
+		return notFound(err, ErrAttendanceNotFound)
+	}
+	if version != 0 && version != before.Version {
+		return ErrVersionMismatch
+	}
+	att.SchoolID = before.SchoolID
+
+	// The status moves through the state machine from the stored one.
+	status := att.Status
+	att.Status = before.Status
+	if err := transition(att, status); err != nil {
+		return err
+	}
+	if err := checkPunches(att); err != nil {
+		return err
+	}
+
+	eventType := model.EventCorrection
+	if att.Status == model.StatusLeave {
+		eventType = model.EventLeave
+	}
+
+	return s.commit(ctx, eventType, before, att, func(tx *gorm.DB) error {
+		if err := s.Repo.WithTx(tx).Update(before, att); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, tx, model.ChangeSourceAdminEdit, before, att, nil, "")
+	})
 }
 
-func (s *AttendanceService) DeleteAttendance(id uint) error {
-	return s.Repo.Delete(id)
+func (s *AttendanceService) DeleteAttendance(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "AttendanceService.DeleteAttendance")
+	defer span.End()
+
+	att, err := s.Repo.WithContext(ctx).GetByID(id)
+	if err != nil {
+		return notFound(err, ErrAttendanceNotFound)
+	}
+
+	event := newAttendanceEvent(model.EventAttendanceDeleted, att)
+
+	err = s.Repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.ensureOpen(tx, att.SchoolID, att.Date); err != nil {
+			return err
+		}
+		if err := s.Repo.WithTx(tx).Delete(id); err != nil {
+			return err
+		}
+		if err := s.recordChange(ctx, tx, model.ChangeSourceAdminDelete, att, nil, nil, ""); err != nil {
+			return err
+		}
+		if err := s.Overtime.Repo.WithTx(tx).DeletePending(id); err != nil {
+			return err
+		}
+		if err := s.Audit.Record(ctx, tx, att.SchoolID, model.AuditDelete, model.AuditEntityAttendance, id, snapshotAttendance(att), nil); err != nil {
+			return err
+		}
+		return s.Outbox.WithTx(tx).Add(event.SchoolID, event.Type, event)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publish(ctx, event)
+	return nil
 }
 
-func (s *AttendanceService) MarkAttendance(input *model.AttendanceRequest) error {
-	var existing model.Attendance
+// MarkAttendance records a check-in or check-out on the teacher's record
+// for today. The record is read and written under its row lock, so
+// concurrent punches of one teacher are applied one after the other to a
+// single record.
+func (s *AttendanceService) MarkAttendance(ctx context.Context, input *model.AttendanceRequest) error {
+	ctx, span := tracing.Start(ctx, "AttendanceService.MarkAttendance")
+	defer span.End()
 
-	today := time.Now().Truncate(24 * time.Hour)
+	if input.Status != model.PunchCheckIn && input.Status != model.PunchCheckOut {
+		return apperror.Field("status", "oneof", "must be one of: checkIn checkOut")
+	}
 
-	// Check if attendance already exists for today
-	err := s.Repo.FindByTeacherAndDate(
-		input.TeacherID,
-		today,
-		&existing,
-	)
+	// The day is the school's calendar day, not the server's.
+	school, err := s.Schools.WithContext(ctx).ForTeacher(input.TeacherID)
+	if err != nil {
+		return notFound(err, ErrTeacherNotFound)
+	}
 
 	now := time.Now()
+	today := school.Today(now)
 
-	if err != nil { // No record exists → allow only CHECK-IN
-		if input.Status != "checkIn" {
-			return errors.New("check-in required before check-out")
+	// Kiosks and terminals are fixed on site; only direct punches are
+	// checked against the campus geofences and networks.
+	var location *model.PunchLocation
+	var fence *model.Geofence
+	offNetwork := false
+	if input.KioskID == nil && input.TerminalID == nil {
+		var checkErr error
+		location, fence, checkErr = s.Geofences.Locate(ctx, school, input.Latitude, input.Longitude, input.Accuracy)
+		if checkErr != nil {
+			return checkErr
 		}
-
-		attendance := model.Attendance{
-			TeacherID: input.TeacherID,
-			Date:      today,
-			Status:    input.Status,
-			CheckIn:   &now,
+		offNetwork, checkErr = s.Networks.Check(ctx, school, input.ClientIP)
+		if checkErr != nil {
+			return checkErr
 		}
-		return s.Repo.Create(&attendance)
 	}
 
-	// Record exists
-	if input.Status == "checkIn" {
-		return errors.New("already checked in for today")
+	campusID, campusErr := s.punchCampus(ctx, input, fence, school.ID)
+	if campusErr != nil {
+		return campusErr
+	}
+
+	// Kiosk codes are scanned with the teacher's own device, so only
+	// terminal punches skip the device check.
+	var deviceID *uint
+	unapproved := false
+	if input.TerminalID == nil {
+		var checkErr error
+		deviceID, unapproved, checkErr = s.Devices.Resolve(ctx, input.TeacherID, input.DeviceIdentifier)
+		if checkErr != nil {
+			return checkErr
+		}
 	}
 
-	if input.Status == "checkOut" {
+	var event model.AttendanceEvent
+	err = s.lockDay(ctx, input.TeacherID, today, func(tx *gorm.DB, existing *model.Attendance) error {
+		if input.Status == model.PunchCheckIn {
+			if existing != nil && existing.CheckIn != nil {
+				return apperror.Conflict("already_checked_in", "already checked in for today")
+			}
+
+			// A day marked absent or on leave beforehand becomes present.
+			attendance := &model.Attendance{SchoolID: school.ID, TeacherID: input.TeacherID, Date: today}
+			var before *model.Attendance
+			if existing != nil {
+				snapshot := *existing
+				before = &snapshot
+				attendance = existing
+			}
+			if err := checkIn(attendance); err != nil {
+				return err
+			}
+
+			attendance.CheckIn = &now
+			attendance.CheckInKioskID = input.KioskID
+			attendance.CheckInTerminalID = input.TerminalID
+			attendance.CheckInLocation = location
+			attendance.CheckInIP = input.ClientIP
+			attendance.CheckInOffNetwork = offNetwork
+			attendance.CheckInDeviceID = deviceID
+			attendance.CheckInDeviceUnapproved = unapproved
+			attendance.CheckInCampusID = campusID
+
+			var err error
+			event, err = s.commitTx(ctx, tx, model.EventCheckIn, before, attendance, func(tx *gorm.DB) error {
+				if before == nil {
+					return s.Repo.WithTx(tx).CreateDay(attendance)
+				}
+				return s.Repo.WithTx(tx).Update(before, attendance)
+			})
+			return err
+		}
+
+		if existing == nil {
+			return apperror.Rule("check_in_required", "check-in required before check-out")
+		}
 		if existing.CheckIn == nil {
 			metrics.AttendanceCheckInTotal.Inc()
-			return errors.New("cannot checkout without check-in")
+			return apperror.Rule("check_in_required", "cannot checkout without check-in")
 		}
 		if existing.CheckOut != nil {
 			metrics.AttendanceCheckOutTotal.Inc()
-			return errors.New("already checked out")
+			return apperror.Conflict("already_checked_out", "already checked out")
 		}
 
+		before := *existing
 		existing.CheckOut = &now
-		existing.Status = input.Status
-		return s.Repo.Update(&existing)
+		existing.CheckOutKioskID = input.KioskID
+		existing.CheckOutTerminalID = input.TerminalID
+		existing.CheckOutLocation = location
+		existing.CheckOutIP = input.ClientIP
+		existing.CheckOutOffNetwork = offNetwork
+		existing.CheckOutDeviceID = deviceID
+		existing.CheckOutDeviceUnapproved = unapproved
+		existing.CheckOutCampusID = campusID
+
+		var err error
+		event, err = s.commitTx(ctx, tx, model.EventCheckOut, &before, existing, func(tx *gorm.DB) error {
+			return s.Repo.WithTx(tx).Update(&before, existing)
+		})
+		return err
+	})
+	if err != nil {
+		return err
 	}
 
-	return errors.New("invalid status value")
+	s.publish(ctx, event)
+	return nil
 }
 
-func (s *AttendanceService) GetAttendanceByTeacherMonth(teacherID uint, month time.Month, year int) (*model.AttendanceResponse, error) {
+// lockDay runs fn in a transaction holding the row lock on the teacher's
+// record for day. fn is given the record, or nil when the day has none
+// yet. When a concurrent request creates the day's record first, fn's
+// CreateDay fails with repository.ErrDayTaken and fn runs once more, in a
+// new transaction, on the record that request created. Should that fail
+// too, ErrDayContended is returned.
+func (s *AttendanceService) lockDay(
+	ctx context.Context,
+	teacherID uint,
+	day time.Time,
+	fn func(tx *gorm.DB, existing *model.Attendance) error,
+) error {
+	for attempt := 1; ; attempt++ {
+		err := s.Repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var existing model.Attendance
+			err := s.Repo.WithTx(tx).LockByTeacherAndDate(teacherID, day, &existing)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fn(tx, nil)
+			}
+			if err != nil {
+				return err
+			}
+			return fn(tx, &existing)
+		})
+		if errors.Is(err, repository.ErrDayTaken) {
+			if attempt < 2 {
+				continue
+			}
+			return ErrDayContended
+		}
+		return stale(err)
+	}
+}
+
+// punchCampus picks the campus a punch is recorded at: the kiosk's or
+// terminal's, else the campus of the matched geofence, else the one the
+// teacher chose, else the teacher's home campus. It is nil when none
+// applies. A kiosk, terminal or geofence of another school's campus fails
+// with ErrCampusOtherSchool.
+func (s *AttendanceService) punchCampus(
+	ctx context.Context,
+	input *model.AttendanceRequest,
+	fence *model.Geofence,
+	schoolID uint,
+) (*uint, error) {
+	if input.KioskID != nil || input.TerminalID != nil {
+		return input.CampusID, s.checkSchoolCampus(ctx, input.CampusID, schoolID)
+	}
+	if fence != nil && fence.CampusID != nil {
+		return fence.CampusID, s.checkSchoolCampus(ctx, fence.CampusID, schoolID)
+	}
+	if input.CampusID != nil {
+		if err := checkCampus(s.Campuses.WithContext(ctx), input.CampusID, schoolID); err != nil {
+			return nil, err
+		}
+		return input.CampusID, nil
+	}
+
+	home, err := s.Campuses.WithContext(ctx).ForTeacher(input.TeacherID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &home.ID, nil
+}
+
+// checkSchoolCampus fails with ErrCampusOtherSchool unless id is unset or
+// names a campus of schoolID.
+func (s *AttendanceService) checkSchoolCampus(ctx context.Context, id *uint, schoolID uint) error {
+	if id == nil {
+		return nil
+	}
+	campus, err := s.Campuses.WithContext(ctx).GetByID(*id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && campus.SchoolID != schoolID) {
+		return ErrCampusOtherSchool
+	}
+	return err
+}
+
+// commit runs write in a transaction together with the audit entry and the
+// outbox record for the resulting event, so webhooks and the audit log see
+// exactly the changes that were committed. before is nil when write creates
+// the row. Writes into a locked period are refused, as are writes over a
+// row changed since it was read, and the overtime of the saved row is
+// brought up to date. Stream subscribers are notified once the transaction
+// is done.
+func (s *AttendanceService) commit(
+	ctx context.Context,
+	eventType string,
+	before *model.Attendance,
+	att *model.Attendance,
+	write func(tx *gorm.DB) error,
+) error {
+	var event model.AttendanceEvent
+
+	err := s.Repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		event, err = s.commitTx(ctx, tx, eventType, before, att, write)
+		return err
+	})
+	if err != nil {
+		return stale(err)
+	}
+
+	s.publish(ctx, event)
+	return nil
+}
+
+// commitTx is commit within the open transaction tx. It returns the event
+// to publish once tx is committed.
+func (s *AttendanceService) commitTx(
+	ctx context.Context,
+	tx *gorm.DB,
+	eventType string,
+	before *model.Attendance,
+	att *model.Attendance,
+	write func(tx *gorm.DB) error,
+) (model.AttendanceEvent, error) {
+	dates := []time.Time{att.Date}
+	if before != nil {
+		dates = append(dates, before.Date)
+	}
+	if err := s.ensureOpen(tx, att.SchoolID, dates...); err != nil {
+		return model.AttendanceEvent{}, err
+	}
+
+	if err := write(tx); err != nil {
+		return model.AttendanceEvent{}, err
+	}
+
+	saved, err := s.Repo.WithTx(tx).GetByID(att.ID)
+	if err != nil {
+		return model.AttendanceEvent{}, err
+	}
+
+	if err := s.Overtime.Detect(tx, saved); err != nil {
+		return model.AttendanceEvent{}, err
+	}
+
+	action := model.AuditUpdate
+	if before == nil {
+		action = model.AuditCreate
+	}
+	err = s.Audit.Record(ctx, tx, saved.SchoolID, action, model.AuditEntityAttendance, saved.ID,
+		snapshotAttendance(before), snapshotAttendance(saved))
+	if err != nil {
+		return model.AttendanceEvent{}, err
+	}
+
+	event := newAttendanceEvent(eventType, saved)
+	return event, s.Outbox.WithTx(tx).Add(event.SchoolID, eventType, event)
+}
+
+// publish notifies stream subscribers. The attendance write has already
+// been committed, so a failure here is logged rather than returned.
+func (s *AttendanceService) publish(ctx context.Context, event model.AttendanceEvent) {
+	if s.Events == nil {
+		return
+	}
+
+	if err := s.Events.Publish(context.WithoutCancel(ctx), event); err != nil {
+		slog.ErrorContext(ctx, "publish attendance event", "error", err)
+	}
+}
+
+func newAttendanceEvent(eventType string, att *model.Attendance) model.AttendanceEvent {
+	return model.AttendanceEvent{
+		Type:         eventType,
+		AttendanceID: att.ID,
+		SchoolID:     att.SchoolID,
+		TeacherID:    att.TeacherID,
+		TeacherName:  att.Teacher.FirstName + " " + att.Teacher.LastName,
+		Department:   att.Teacher.Department,
+		Status:       att.Status,
+		Date:         att.Date.Format("02-01-2006"),
+		OccurredAt:   time.Now(),
+	}
+}
+
+func (s *AttendanceService) GetAttendanceByTeacherMonth(ctx context.Context, teacherID uint, month time.Month, year int) (*model.AttendanceResponse, error) {
+	ctx, span := tracing.Start(ctx, "AttendanceService.GetAttendanceByTeacherMonth")
+	defer span.End()
+
 	// Get filtered attendance
-	attList, err := s.Repo.FindByTeacherAndMonth(teacherID, month, year)
+	attList, err := s.Repo.WithContext(ctx).FindByTeacherAndMonth(teacherID, month, year)
 	if err != nil {
 		return nil, err
 	}
@@ -98,11 +486,15 @@ func (s *AttendanceService) GetAttendanceByTeacherMonth(teacherID uint, month ti
 	result := []model.AttendanceDTO{}
 	for _, att := range attList {
 		dto := model.AttendanceDTO{
-			TeacherID:   att.Teacher.ID,
-			TeacherName: att.Teacher.FirstName + " " + att.Teacher.LastName,
-			CheckIn:     att.CheckIn,
-			CheckOut:    att.CheckOut,
-			Date:        att.Date.Format("02-01-2006"),
+			TeacherID:        att.Teacher.ID,
+			TeacherName:      att.Teacher.FirstName + " " + att.Teacher.LastName,
+			CheckIn:          att.CheckIn,
+			CheckOut:         att.CheckOut,
+			Date:             att.Date.Format("02-01-2006"),
+			CheckInDeviceID:  att.CheckInDeviceID,
+			CheckOutDeviceID: att.CheckOutDeviceID,
+			CheckInCampusID:  att.CheckInCampusID,
+			CheckOutCampusID: att.CheckOutCampusID,
 		}
 		result = append(result, dto)
 	}
@@ -121,12 +513,14 @@ func (s *AttendanceService) GetAttendanceByTeacherMonth(teacherID uint, month ti
 	return resp, nil
 }
 
-func (s *AttendanceService) GetAttendanceByMonthAndDate(date time.Time) (*model.AttendanceResponse, error) {
+func (s *AttendanceService) GetAttendanceByMonthAndDate(ctx context.Context, date time.Time, campusID uint) (*model.AttendanceResponse, error) {
+	ctx, span := tracing.Start(ctx, "AttendanceService.GetAttendanceByMonthAndDate")
+	defer span.End()
 
 	month := date.Month()
 	year := date.Year()
 
-	attList, err := s.Repo.FindByMonthAndDate(month, year, date)
+	attList, err := s.Repo.WithContext(ctx).FindByMonthAndDate(month, year, date, campusID)
 	if err != nil {
 		return nil, err
 	}
@@ -135,11 +529,15 @@ func (s *AttendanceService) GetAttendanceByMonthAndDate(date time.Time) (*model.
 
 	for _, att := range attList {
 		dto := model.AttendanceDTO{
-			TeacherID:   att.Teacher.ID,
-			TeacherName: att.Teacher.FirstName + " " + att.Teacher.LastName,
-			CheckIn:     att.CheckIn,
-			CheckOut:    att.CheckOut,
-			Date:        att.Date.Format("02-01-2006"),
+			TeacherID:        att.Teacher.ID,
+			TeacherName:      att.Teacher.FirstName + " " + att.Teacher.LastName,
+			CheckIn:          att.CheckIn,
+			CheckOut:         att.CheckOut,
+			Date:             att.Date.Format("02-01-2006"),
+			CheckInDeviceID:  att.CheckInDeviceID,
+			CheckOutDeviceID: att.CheckOutDeviceID,
+			CheckInCampusID:  att.CheckInCampusID,
+			CheckOutCampusID: att.CheckOutCampusID,
 		}
 		result = append(result, dto)
 	}
@@ -0,0 +1,223 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/config"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/service"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testDSNEnv names the database the concurrency tests run against. They
+// create their own school and teachers in it, remove them again, and are
+// skipped when it is unset; point it at a throwaway database.
+const testDSNEnv = "TEST_DATABASE_DSN"
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	err = db.AutoMigrate(
+		&model.School{},
+		&model.Campus{},
+		&model.Teacher{},
+		&model.Attendance{},
+		&model.OutboxEvent{},
+		&model.AttendanceChange{},
+		&model.AuditLog{},
+		&model.AttendancePeriod{},
+		&model.Holiday{},
+		&model.OvertimeRecord{},
+		&model.Geofence{},
+		&model.CampusNetwork{},
+		&model.Device{},
+		&model.DeviceBinding{},
+	)
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	repo := repository.NewAttendanceRepository(db)
+	if err := repo.EnsureStatusCheck(); err != nil {
+		t.Fatalf("status check: %v", err)
+	}
+	duplicates, err := repo.EnsureUniqueDay()
+	if err != nil {
+		t.Fatalf("unique day index: %v", err)
+	}
+	if len(duplicates) > 0 {
+		t.Fatalf("unique day index: %d days have duplicate records", len(duplicates))
+	}
+
+	return db
+}
+
+func newTestAttendanceService(db *gorm.DB) *service.AttendanceService {
+	schools := repository.NewSchoolRepository(db)
+	campuses := repository.NewCampusRepository(db)
+	audit := service.NewAuditService(repository.NewAuditRepository(db))
+
+	return service.NewAttendanceService(
+		repository.NewAttendanceRepository(db),
+		repository.NewCorrectionRepository(db),
+		repository.NewOutboxRepository(db),
+		repository.NewPeriodRepository(db),
+		schools,
+		campuses,
+		service.NewOvertimeService(
+			repository.NewOvertimeRepository(db),
+			repository.NewHolidayRepository(db),
+			schools,
+			campuses,
+			config.OvertimePolicy{},
+		),
+		service.NewGeofenceService(
+			repository.NewGeofenceRepository(db),
+			campuses,
+			config.GeofencePolicy{Mode: config.GeofenceOff},
+		),
+		service.NewNetworkService(repository.NewNetworkRepository(db), campuses, config.NetworkOff),
+		service.NewDeviceService(repository.NewDeviceRepository(db), config.DeviceOff),
+		audit,
+		nil,
+	)
+}
+
+// removeSchool deletes the school and the rows the test wrote for it. Audit
+// entries stay where the table is append-only.
+func removeSchool(t *testing.T, db *gorm.DB, schoolID uint) {
+	for _, table := range []string{"attendance_changes", "outbox_events", "attendances", "teachers", "audit_logs"} {
+		if err := db.Exec("DELETE FROM "+table+" WHERE school_id = ?", schoolID).Error; err != nil {
+			t.Logf("clean up %s: %v", table, err)
+		}
+	}
+	if err := db.Exec("DELETE FROM schools WHERE id = ?", schoolID).Error; err != nil {
+		t.Logf("clean up schools: %v", err)
+	}
+}
+
+// punchOutcome counts the punches that succeeded; the others must have
+// been refused with one of the errors a client can act on.
+type punchOutcome struct {
+	mu        sync.Mutex
+	checkIns  int
+	checkOuts int
+}
+
+func (o *punchOutcome) record(t *testing.T, status model.PunchType, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err == nil {
+		if status == model.PunchCheckIn {
+			o.checkIns++
+		} else {
+			o.checkOuts++
+		}
+		return
+	}
+
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || (appErr.Kind != apperror.KindConflict && appErr.Kind != apperror.KindRule) {
+		t.Errorf("%s: unexpected error: %v", status, err)
+	}
+}
+
+// TestMarkAttendanceConcurrent punches every teacher in and out from many
+// goroutines at once. Each teacher must end the day with a single record,
+// checked in once and checked out at most once.
+func TestMarkAttendanceConcurrent(t *testing.T) {
+	db := openTestDB(t)
+	svc := newTestAttendanceService(db)
+
+	suffix := time.Now().UnixNano()
+	school := &model.School{Slug: fmt.Sprintf("concurrency-%d", suffix), Name: "Concurrency test", Active: true}
+	if err := db.Create(school).Error; err != nil {
+		t.Fatalf("create school: %v", err)
+	}
+	t.Cleanup(func() { removeSchool(t, db, school.ID) })
+
+	const teachers = 5
+	const punchesPerTeacher = 20
+
+	ids := make([]uint, 0, teachers)
+	for i := 0; i < teachers; i++ {
+		teacher := &model.Teacher{SchoolID: school.ID, FirstName: "Teacher", LastName: fmt.Sprint(i)}
+		if err := db.Create(teacher).Error; err != nil {
+			t.Fatalf("create teacher: %v", err)
+		}
+		ids = append(ids, teacher.ID)
+	}
+
+	outcomes := make(map[uint]*punchOutcome, teachers)
+	for _, id := range ids {
+		outcomes[id] = &punchOutcome{}
+	}
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		for i := 0; i < punchesPerTeacher; i++ {
+			status := model.PunchCheckIn
+			if i%2 == 1 {
+				status = model.PunchCheckOut
+			}
+
+			wg.Add(1)
+			go func(id uint, status model.PunchType) {
+				defer wg.Done()
+				<-start
+				err := svc.MarkAttendance(context.Background(), &model.AttendanceRequest{TeacherID: id, Status: status})
+				outcomes[id].record(t, status, err)
+			}(id, status)
+		}
+	}
+	close(start)
+	wg.Wait()
+
+	today := school.Today(time.Now())
+	for _, id := range ids {
+		var rows []model.Attendance
+		if err := db.Where("teacher_id = ?", id).Find(&rows).Error; err != nil {
+			t.Fatalf("load attendance: %v", err)
+		}
+
+		if len(rows) != 1 {
+			t.Errorf("teacher %d: %d attendance records, want 1", id, len(rows))
+			continue
+		}
+		att := rows[0]
+		outcome := outcomes[id]
+
+		if !att.Date.Equal(today) {
+			t.Errorf("teacher %d: record dated %s, want %s", id, att.Date, today)
+		}
+		if outcome.checkIns != 1 || att.CheckIn == nil {
+			t.Errorf("teacher %d: %d check-ins succeeded, want 1 recorded", id, outcome.checkIns)
+		}
+		if outcome.checkOuts > 1 || (outcome.checkOuts == 1) != (att.CheckOut != nil) {
+			t.Errorf("teacher %d: %d check-outs succeeded, check-out recorded: %v", id, outcome.checkOuts, att.CheckOut != nil)
+		}
+	}
+}
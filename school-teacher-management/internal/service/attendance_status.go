@@ -0,0 +1,61 @@
+package service
+
+import (
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/model"
+)
+
+// statusTransitions lists the statuses a day may move to from each status.
+// A day that was worked cannot become a holiday, and a holiday can only be
+// worked or, once the holiday is withdrawn, marked absent.
+var statusTransitions = map[model.AttendanceStatus][]model.AttendanceStatus{
+	model.StatusPresent:      {model.StatusAbsent, model.StatusLeave, model.StatusHalfDay, model.StatusOnDuty, model.StatusWorkFromHome},
+	model.StatusAbsent:       {model.StatusPresent, model.StatusLeave, model.StatusHoliday, model.StatusHalfDay, model.StatusOnDuty, model.StatusWorkFromHome},
+	model.StatusLeave:        {model.StatusPresent, model.StatusAbsent, model.StatusHalfDay},
+	model.StatusHoliday:      {model.StatusPresent, model.StatusAbsent},
+	model.StatusHalfDay:      {model.StatusPresent, model.StatusAbsent, model.StatusLeave},
+	model.StatusOnDuty:       {model.StatusPresent, model.StatusAbsent},
+	model.StatusWorkFromHome: {model.StatusPresent, model.StatusAbsent},
+}
+
+var errInvalidStatus = apperror.Field("status", "oneof", "must be one of: present absent leave holiday half_day on_duty work_from_home")
+
+// transition moves att to status, refusing moves the state machine does not
+// allow. Moving to the current status is a no-op.
+func transition(att *model.Attendance, status model.AttendanceStatus) error {
+	if !status.Valid() {
+		return errInvalidStatus
+	}
+	if att.Status == status {
+		return nil
+	}
+
+	for _, next := range statusTransitions[att.Status] {
+		if next == status {
+			att.Status = status
+			return nil
+		}
+	}
+	return apperror.Rule("invalid_status_transition", "cannot change attendance from "+string(att.Status)+" to "+string(status))
+}
+
+// checkIn marks the day worked when a check-in is recorded on it; a day
+// already marked as worked keeps its status.
+func checkIn(att *model.Attendance) error {
+	if att.Status == "" {
+		att.Status = model.StatusPresent
+		return nil
+	}
+	if att.Status.Worked() {
+		return nil
+	}
+	return transition(att, model.StatusPresent)
+}
+
+// checkPunches refuses punches on a day that was not worked.
+func checkPunches(att *model.Attendance) error {
+	if !att.Status.Worked() && (att.CheckIn != nil || att.CheckOut != nil) {
+		return apperror.Rule("punches_not_allowed", "a day marked "+string(att.Status)+" cannot have check-in or check-out times")
+	}
+	return nil
+}
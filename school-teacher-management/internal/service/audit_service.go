@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/requestctx"
+	"school-teacher-management/internal/tracing"
+
+	"gorm.io/gorm"
+)
+
+const auditVerifyBatch = 1000
+
+var ErrAuditChainScoped = apperror.Forbidden("audit_chain_scoped", "the audit chain spans every school; verify it without a school scope")
+
+type AuditService struct {
+	Repo *repository.AuditRepository
+}
+
+func NewAuditService(repo *repository.AuditRepository) *AuditService {
+	return &AuditService{Repo: repo}
+}
+
+// Record appends an audit entry for a record of schoolID inside tx. before
+// is nil for creates and after is nil for deletes.
+func (s *AuditService) Record(
+	ctx context.Context,
+	tx *gorm.DB,
+	schoolID uint,
+	action string,
+	entityType string,
+	entityID uint,
+	before, after interface{},
+) error {
+	ctx, span := tracing.Start(ctx, "AuditService.Record")
+	defer span.End()
+
+	beforeJSON, err := model.NewJSON(before)
+	if err != nil {
+		return err
+	}
+
+	afterJSON, err := model.NewJSON(after)
+	if err != nil {
+		return err
+	}
+
+	return s.Repo.WithTx(tx).Append(&model.AuditLog{
+		SchoolID:   schoolID,
+		Actor:      requestctx.Actor(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		RequestID:  requestctx.RequestID(ctx),
+	})
+}
+
+// Find returns the entries of the request's school. Unscoped trust-level
+// administrators also see the entries written before entries had a school.
+func (s *AuditService) Find(ctx context.Context, filter model.AuditFilter) ([]model.AuditLog, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.Find")
+	defer span.End()
+
+	if filter.Limit <= 0 || filter.Limit > 1000 {
+		filter.Limit = 100
+	}
+	return s.Repo.WithContext(ctx).Find(filter)
+}
+
+// Verify walks the whole chain and reports the first entry whose hash or
+// link to its predecessor does not match. The chain links the entries of
+// every school, so a request scoped to one school cannot verify it.
+func (s *AuditService) Verify(ctx context.Context) (*model.AuditVerification, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.Verify")
+	defer span.End()
+
+	if _, scoped := requestctx.School(ctx); scoped {
+		return nil, ErrAuditChainScoped
+	}
+
+	result := &model.AuditVerification{Valid: true}
+
+	var lastID uint
+	prevHash := ""
+
+	for {
+		batch, err := s.Repo.WithContext(ctx).FindAfter(lastID, auditVerifyBatch)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range batch {
+			entry := &batch[i]
+			result.Checked++
+
+			reason := ""
+			switch {
+			case entry.PrevHash != prevHash:
+				reason = fmt.Sprintf("entry %d does not link to the entry before it", entry.ID)
+			case entry.ComputeHash() != entry.Hash:
+				reason = fmt.Sprintf("entry %d content does not match its hash", entry.ID)
+			}
+
+			if reason != "" {
+				id := entry.ID
+				result.Valid = false
+				result.FirstInvalid = &id
+				result.Reason = reason
+				return result, nil
+			}
+
+			prevHash = entry.Hash
+			lastID = entry.ID
+		}
+
+		if len(batch) < auditVerifyBatch {
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/tracing"
+	"time"
+)
+
+var (
+	ErrUnknownCampus = apperror.Validation("unknown_campus", "unknown or inactive campus")
+	// ErrCampusOtherSchool refuses a punch at a campus of another school.
+	ErrCampusOtherSchool = apperror.Forbidden("campus_other_school", "punch is at a campus of another school")
+	ErrCampusNotFound    = apperror.NotFound("campus_not_found", "campus not found")
+)
+
+type CampusService struct {
+	Repo *repository.CampusRepository
+}
+
+func NewCampusService(repo *repository.CampusRepository) *CampusService {
+	return &CampusService{Repo: repo}
+}
+
+// CreateCampus adds a campus to the school the request is scoped to.
+func (s *CampusService) CreateCampus(ctx context.Context, input *model.CampusInput) (*model.Campus, error) {
+	ctx, span := tracing.Start(ctx, "CampusService.CreateCampus")
+	defer span.End()
+
+	schoolID, err := currentSchool(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	campus := &model.Campus{SchoolID: schoolID, Active: true}
+	if err := applyCampusInput(campus, input); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.WithContext(ctx).Create(campus); err != nil {
+		return nil, err
+	}
+	return campus, nil
+}
+
+func (s *CampusService) UpdateCampus(ctx context.Context, id uint, input *model.CampusInput) (*model.Campus, error) {
+	ctx, span := tracing.Start(ctx, "CampusService.UpdateCampus")
+	defer span.End()
+
+	repo := s.Repo.WithContext(ctx)
+
+	campus, err := repo.GetByID(id)
+	if err != nil {
+		return nil, notFound(err, ErrCampusNotFound)
+	}
+	if err := applyCampusInput(campus, input); err != nil {
+		return nil, err
+	}
+	if err := repo.Update(campus); err != nil {
+		return nil, err
+	}
+	return campus, nil
+}
+
+func applyCampusInput(campus *model.Campus, input *model.CampusInput) error {
+	for _, clock := range []string{input.WorkStart, input.WorkEnd} {
+		if clock == "" {
+			continue
+		}
+		if _, err := time.Parse("15:04", clock); err != nil {
+			return apperror.Validation("invalid_work_hours", "work_start and work_end must be HH:MM")
+		}
+	}
+
+	campus.Name = input.Name
+	campus.Address = input.Address
+	campus.WorkStart = input.WorkStart
+	campus.WorkEnd = input.WorkEnd
+	if input.Active != nil {
+		campus.Active = *input.Active
+	}
+	return nil
+}
+
+func (s *CampusService) GetCampuses(ctx context.Context) ([]model.Campus, error) {
+	ctx, span := tracing.Start(ctx, "CampusService.GetCampuses")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).GetAll()
+}
+
+func (s *CampusService) GetCampus(ctx context.Context, id uint) (*model.Campus, error) {
+	ctx, span := tracing.Start(ctx, "CampusService.GetCampus")
+	defer span.End()
+
+	v, err := s.Repo.WithContext(ctx).GetByID(id)
+	return v, notFound(err, ErrCampusNotFound)
+}
+
+// checkCampus fails with ErrUnknownCampus unless id is unset or names an
+// active campus visible to repo, of schoolID when that is set.
+func checkCampus(repo *repository.CampusRepository, id *uint, schoolID uint) error {
+	if id == nil {
+		return nil
+	}
+	campus, err := repo.GetByID(*id)
+	if err != nil || !campus.Active || (schoolID != 0 && campus.SchoolID != schoolID) {
+		return ErrUnknownCampus
+	}
+	return nil
+}
+
+// checkOwnCampus is checkCampus against the school the request is scoped
+// to, for kiosks, terminals and geofences, which belong to their campus's
+// school. A request that is not scoped to a school cannot name a campus.
+func checkOwnCampus(ctx context.Context, repo *repository.CampusRepository, id *uint) error {
+	if id == nil {
+		return nil
+	}
+	schoolID, err := currentSchool(ctx)
+	if err != nil {
+		return err
+	}
+	return checkCampus(repo.WithContext(ctx), id, schoolID)
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/config"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/requestctx"
+	"school-teacher-management/internal/tracing"
+	"time"
+)
+
+var (
+	ErrDeviceNotApproved = apperror.Forbidden("device_not_approved", "check-in is only accepted from an approved device")
+	ErrBindingNotFound   = apperror.NotFound("device_binding_not_found", "device binding not found")
+	ErrDeviceNotFound    = apperror.NotFound("device_not_found", "device not found")
+)
+
+type DeviceService struct {
+	Repo   *repository.DeviceRepository
+	Policy string
+}
+
+func NewDeviceService(repo *repository.DeviceRepository, policy string) *DeviceService {
+	return &DeviceService{Repo: repo, Policy: policy}
+}
+
+// RegisterDevice records a device. A shared device is usable by every
+// teacher at once; any other device is owned by teacherID and needs its
+// binding approved before punches from it are trusted.
+func (s *DeviceService) RegisterDevice(ctx context.Context, teacherID uint, input *model.DeviceInput) (*model.Device, error) {
+	ctx, span := tracing.Start(ctx, "DeviceService.RegisterDevice")
+	defer span.End()
+
+	device := &model.Device{
+		Identifier: input.Identifier,
+		Name:       input.Name,
+		Platform:   input.Platform,
+		Shared:     input.Shared,
+		Active:     true,
+	}
+	if !input.Shared {
+		device.OwnerID = &teacherID
+	}
+
+	if err := s.Repo.WithContext(ctx).FindOrCreate(device); err != nil {
+		return nil, err
+	}
+
+	if !device.Shared {
+		if _, err := s.Repo.WithContext(ctx).RequestBinding(teacherID, device.ID); err != nil {
+			return nil, err
+		}
+	}
+	return device, nil
+}
+
+func (s *DeviceService) GetDevices(ctx context.Context, teacherID uint) ([]model.Device, error) {
+	ctx, span := tracing.Start(ctx, "DeviceService.GetDevices")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).Find(teacherID)
+}
+
+func (s *DeviceService) DeactivateDevice(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "DeviceService.DeactivateDevice")
+	defer span.End()
+
+	repo := s.Repo.WithContext(ctx)
+	device, err := repo.GetByID(id)
+	if err != nil {
+		return notFound(err, ErrDeviceNotFound)
+	}
+	device.Active = false
+	return repo.Update(device)
+}
+
+func (s *DeviceService) GetBinding(ctx context.Context, id uint) (*model.DeviceBinding, error) {
+	ctx, span := tracing.Start(ctx, "DeviceService.GetBinding")
+	defer span.End()
+
+	v, err := s.Repo.WithContext(ctx).GetBinding(id)
+	return v, notFound(err, ErrBindingNotFound)
+}
+
+func (s *DeviceService) GetBindings(ctx context.Context, teacherID uint, department string, status string) ([]model.DeviceBinding, error) {
+	ctx, span := tracing.Start(ctx, "DeviceService.GetBindings")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).FindBindings(teacherID, department, status)
+}
+
+// DecideBinding approves a binding, or rejects it; rejecting an approved
+// binding revokes it.
+func (s *DeviceService) DecideBinding(ctx context.Context, id uint, approve bool) (*model.DeviceBinding, error) {
+	ctx, span := tracing.Start(ctx, "DeviceService.DecideBinding")
+	defer span.End()
+
+	actor := requestctx.Actor(ctx)
+
+	repo := s.Repo.WithContext(ctx)
+
+	binding, err := repo.GetBinding(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if actor.TeacherID != 0 && actor.TeacherID == binding.TeacherID {
+		return nil, apperror.Forbidden("own_device", "cannot decide your own device")
+	}
+
+	status := model.BindingRejected
+	switch {
+	case approve:
+		status = model.BindingApproved
+	case binding.Status == model.BindingApproved:
+		status = model.BindingRevoked
+	}
+	if binding.Status == status {
+		return nil, apperror.Conflict("binding_already_decided", "device binding is already "+status)
+	}
+
+	now := time.Now()
+	binding.Status = status
+	binding.DecidedBy = &actor
+	binding.DecidedAt = &now
+
+	if err := repo.UpdateBinding(binding); err != nil {
+		return nil, err
+	}
+	return binding, nil
+}
+
+// Resolve returns the ID of the device a punch came from and whether the
+// teacher is not approved to use it. An unknown identifier is registered
+// to the teacher with a pending binding, so it shows up for approval.
+// Under the reject policy an unapproved device fails with
+// ErrDeviceNotApproved.
+func (s *DeviceService) Resolve(ctx context.Context, teacherID uint, identifier string) (*uint, bool, error) {
+	ctx, span := tracing.Start(ctx, "DeviceService.Resolve")
+	defer span.End()
+
+	if s.Policy == config.DeviceOff {
+		return nil, false, nil
+	}
+
+	var deviceID *uint
+	approved := false
+
+	if identifier != "" {
+		device := &model.Device{
+			Identifier: identifier,
+			OwnerID:    &teacherID,
+			Active:     true,
+		}
+		if err := s.Repo.WithContext(ctx).FindOrCreate(device); err != nil {
+			return nil, false, err
+		}
+		deviceID = &device.ID
+
+		if err := s.Repo.WithContext(ctx).Touch(device.ID, time.Now()); err != nil {
+			return nil, false, err
+		}
+
+		switch {
+		case !device.Active:
+		case device.Shared:
+			approved = true
+		default:
+			binding, err := s.Repo.WithContext(ctx).RequestBinding(teacherID, device.ID)
+			if err != nil {
+				return nil, false, err
+			}
+			approved = binding.Status == model.BindingApproved
+		}
+	}
+
+	if !approved && s.Policy == config.DeviceReject {
+		return deviceID, true, ErrDeviceNotApproved
+	}
+	return deviceID, !approved, nil
+}
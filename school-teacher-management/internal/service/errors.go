@@ -0,0 +1,31 @@
+package service
+
+import (
+	"errors"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// ErrVersionMismatch is returned when a conditional update names a version
+// other than the stored one, or the row changed while it was updated.
+var ErrVersionMismatch = apperror.PreconditionFailed("version_mismatch", "record has been changed; fetch it again and retry")
+
+// notFound returns missing in place of gorm.ErrRecordNotFound, so callers
+// learn which record was missing; other errors are returned unchanged.
+func notFound(err error, missing error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return missing
+	}
+	return err
+}
+
+// stale returns ErrVersionMismatch in place of repository.ErrStaleVersion;
+// other errors are returned unchanged.
+func stale(err error) error {
+	if errors.Is(err, repository.ErrStaleVersion) {
+		return ErrVersionMismatch
+	}
+	return err
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/config"
+	"school-teacher-management/internal/geo"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/tracing"
+)
+
+var (
+	ErrOutsideGeofence  = apperror.Forbidden("outside_geofence", "location is outside every campus geofence")
+	ErrGeofenceNotFound = apperror.NotFound("geofence_not_found", "geofence not found")
+)
+
+type GeofenceService struct {
+	Repo     *repository.GeofenceRepository
+	Campuses *repository.CampusRepository
+	Policy   config.GeofencePolicy
+}
+
+func NewGeofenceService(
+	repo *repository.GeofenceRepository,
+	campuses *repository.CampusRepository,
+	policy config.GeofencePolicy,
+) *GeofenceService {
+	return &GeofenceService{Repo: repo, Campuses: campuses, Policy: policy}
+}
+
+func (s *GeofenceService) CreateGeofence(ctx context.Context, input *model.GeofenceInput) (*model.Geofence, error) {
+	ctx, span := tracing.Start(ctx, "GeofenceService.CreateGeofence")
+	defer span.End()
+
+	if err := checkOwnCampus(ctx, s.Campuses, input.CampusID); err != nil {
+		return nil, err
+	}
+
+	fence := &model.Geofence{Active: true}
+	if err := applyGeofenceInput(fence, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.Repo.WithContext(ctx).Create(fence); err != nil {
+		return nil, err
+	}
+	return fence, nil
+}
+
+func (s *GeofenceService) UpdateGeofence(ctx context.Context, id uint, input *model.GeofenceInput) (*model.Geofence, error) {
+	ctx, span := tracing.Start(ctx, "GeofenceService.UpdateGeofence")
+	defer span.End()
+
+	repo := s.Repo.WithContext(ctx)
+	fence, err := repo.GetByID(id)
+	if err != nil {
+		return nil, notFound(err, ErrGeofenceNotFound)
+	}
+
+	if err := checkOwnCampus(ctx, s.Campuses, input.CampusID); err != nil {
+		return nil, err
+	}
+
+	if err := applyGeofenceInput(fence, input); err != nil {
+		return nil, err
+	}
+
+	if err := repo.Update(fence); err != nil {
+		return nil, err
+	}
+	return fence, nil
+}
+
+func (s *GeofenceService) GetGeofences(ctx context.Context) ([]model.Geofence, error) {
+	ctx, span := tracing.Start(ctx, "GeofenceService.GetGeofences")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).GetAll()
+}
+
+func (s *GeofenceService) DeleteGeofence(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "GeofenceService.DeleteGeofence")
+	defer span.End()
+
+	return notFound(s.Repo.WithContext(ctx).Delete(id), ErrGeofenceNotFound)
+}
+
+func applyGeofenceInput(fence *model.Geofence, input *model.GeofenceInput) error {
+	switch input.Type {
+	case model.GeofenceRadius:
+		if input.Center == nil || !geo.Valid(*input.Center) || input.RadiusMeters <= 0 {
+			return apperror.Validation("invalid_geofence", "a radius geofence needs a valid center and a positive radius_meters")
+		}
+		fence.Center = input.Center
+		fence.RadiusMeters = input.RadiusMeters
+		fence.Polygon = nil
+	case model.GeofencePolygon:
+		if len(input.Polygon) < 3 {
+			return apperror.Validation("invalid_geofence", "a polygon geofence needs at least three points")
+		}
+		for _, p := range input.Polygon {
+			if !geo.Valid(p) {
+				return apperror.Validation("invalid_geofence", "polygon has an invalid point")
+			}
+		}
+		fence.Polygon = input.Polygon
+		fence.Center = nil
+		fence.RadiusMeters = 0
+	default:
+		return apperror.Field("type", "oneof", "must be radius or polygon")
+	}
+
+	fence.Name = input.Name
+	fence.Type = input.Type
+	fence.CampusID = input.CampusID
+	if input.Active != nil {
+		fence.Active = *input.Active
+	}
+	return nil
+}
+
+// Unverified applies the school's policy to a punch whose position cannot be known,
+// such as one queued on a device while it was offline. With geofences of
+// the school configured such a punch counts as outside them: it fails under
+// the reject policy and is marked OutOfFence under flag.
+func (s *GeofenceService) Unverified(ctx context.Context, school *model.School) (*model.PunchLocation, error) {
+	ctx, span := tracing.Start(ctx, "GeofenceService.Unverified")
+	defer span.End()
+
+	loc := &model.PunchLocation{}
+	mode := s.mode(school)
+	if mode == config.GeofenceOff {
+		return loc, nil
+	}
+
+	fences, err := s.Repo.WithContext(ctx).FindActive(school.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(fences) == 0 {
+		return loc, nil
+	}
+
+	if mode == config.GeofenceReject {
+		return nil, ErrOutsideGeofence
+	}
+	loc.OutOfFence = true
+	return loc, nil
+}
+
+// Locate matches a punch's reported position against the active
+// geofences of the school. With no geofences configured, or the school's
+// policy off, the position is only recorded. Under the reject policy a punch outside every fence
+// fails with ErrOutsideGeofence; under flag it is marked OutOfFence. The
+// matched geofence, if any, is returned so the punch can take its campus.
+func (s *GeofenceService) Locate(ctx context.Context, school *model.School, lat, lng, accuracy *float64) (*model.PunchLocation, *model.Geofence, error) {
+	ctx, span := tracing.Start(ctx, "GeofenceService.Locate")
+	defer span.End()
+
+	if (lat == nil) != (lng == nil) {
+		return nil, nil, apperror.Validation("invalid_location", "latitude and longitude must be given together")
+	}
+
+	loc := &model.PunchLocation{Latitude: lat, Longitude: lng, Accuracy: accuracy}
+
+	if lat != nil && !geo.Valid(geo.Point{Lat: *lat, Lng: *lng}) {
+		return nil, nil, apperror.Validation("invalid_location", "invalid latitude or longitude")
+	}
+
+	mode := s.mode(school)
+	if mode == config.GeofenceOff {
+		return loc, nil, nil
+	}
+
+	fences, err := s.Repo.WithContext(ctx).FindActive(school.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(fences) == 0 {
+		return loc, nil, nil
+	}
+
+	var matched *model.Geofence
+
+	switch {
+	case lat == nil:
+		loc.OutOfFence = s.Policy.RequireLocation
+	case accuracy != nil && *accuracy > s.Policy.MaxAccuracyMeters:
+		loc.OutOfFence = true
+	default:
+		loc.OutOfFence = true
+		p := geo.Point{Lat: *lat, Lng: *lng}
+		for i := range fences {
+			if fences[i].Contains(p) {
+				loc.Geofence = fences[i].Name
+				loc.OutOfFence = false
+				matched = &fences[i]
+				break
+			}
+		}
+	}
+
+	if loc.OutOfFence && mode == config.GeofenceReject {
+		return nil, nil, ErrOutsideGeofence
+	}
+	return loc, matched, nil
+}
+
+// mode returns the school's geofence policy, or the deployment-wide one
+// when it has none.
+func (s *GeofenceService) mode(school *model.School) string {
+	if school.GeofencePolicy != "" {
+		return school.GeofencePolicy
+	}
+	return s.Policy.Mode
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"school-teacher-management/internal/model"
+
+	"gorm.io/gorm"
+)
+
+const readyPingTimeout = 2 * time.Second
+
+// HealthService answers the liveness and readiness probes. It supervises
+// the background jobs it starts, so readiness fails when one of them has
+// died, and during shutdown.
+type HealthService struct {
+	DB *gorm.DB
+
+	mu       sync.Mutex
+	migrated error
+	draining bool
+	jobs     map[string]string
+	running  sync.WaitGroup
+}
+
+func NewHealthService(db *gorm.DB) *HealthService {
+	return &HealthService{DB: db, jobs: map[string]string{}}
+}
+
+// Migrated records the outcome of the schema migrations run on start.
+func (s *HealthService) Migrated(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.migrated = err
+}
+
+// Go runs the background job run until ctx is done. A job that panics or
+// returns before then is reported by the readiness probe.
+func (s *HealthService) Go(ctx context.Context, name string, run func(ctx context.Context)) {
+	s.setJob(name, model.HealthOK)
+	s.running.Add(1)
+
+	go func() {
+		defer s.running.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				slog.ErrorContext(ctx, "job panicked", "job", name, "error", r)
+				s.setJob(name, "panicked")
+			}
+		}()
+
+		run(ctx)
+		if ctx.Err() == nil {
+			s.setJob(name, "stopped")
+		}
+	}()
+}
+
+func (s *HealthService) setJob(name, state string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[name] = state
+}
+
+// Drain makes the readiness probe fail, so the load balancer stops sending
+// requests while the server shuts down.
+func (s *HealthService) Drain() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.draining = true
+}
+
+// Wait blocks until the jobs started with Go have returned.
+func (s *HealthService) Wait() {
+	s.running.Wait()
+}
+
+// Live reports that the process is up; it checks nothing else, so a slow
+// dependency does not get the process restarted.
+func (s *HealthService) Live() model.HealthReport {
+	return model.HealthReport{Status: model.HealthOK}
+}
+
+// Ready checks the database connection, the migrations and the background
+// jobs. The report's status is ok only when every check is. The probe is
+// public, so errors are logged rather than reported.
+func (s *HealthService) Ready(ctx context.Context) model.HealthReport {
+	checks := map[string]string{"database": model.HealthOK}
+
+	pingCtx, cancel := context.WithTimeout(ctx, readyPingTimeout)
+	defer cancel()
+	db, err := s.DB.DB()
+	if err == nil {
+		err = db.PingContext(pingCtx)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "readiness database check", "error", err)
+		checks["database"] = model.HealthUnavailable
+	}
+
+	s.mu.Lock()
+	checks["migrations"] = model.HealthOK
+	if s.migrated != nil {
+		checks["migrations"] = model.HealthUnavailable
+	}
+	for name, state := range s.jobs {
+		checks["job."+name] = state
+	}
+	if s.draining {
+		checks["shutdown"] = "draining"
+	}
+	s.mu.Unlock()
+
+	report := model.HealthReport{Status: model.HealthOK, Checks: checks}
+	for _, state := range checks {
+		if state != model.HealthOK {
+			report.Status = model.HealthUnavailable
+		}
+	}
+	return report
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/tracing"
+	"time"
+)
+
+var ErrHolidayNotFound = apperror.NotFound("holiday_not_found", "holiday not found")
+
+type HolidayService struct {
+	Repo     *repository.HolidayRepository
+	Campuses *repository.CampusRepository
+}
+
+func NewHolidayService(repo *repository.HolidayRepository, campuses *repository.CampusRepository) *HolidayService {
+	return &HolidayService{Repo: repo, Campuses: campuses}
+}
+
+// CreateHoliday closes the school the request is scoped to on the date, or
+// only one of its campuses.
+func (s *HolidayService) CreateHoliday(ctx context.Context, input *model.HolidayInput) (*model.Holiday, error) {
+	ctx, span := tracing.Start(ctx, "HolidayService.CreateHoliday")
+	defer span.End()
+
+	schoolID, err := currentSchool(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := time.Parse("2006-01-02", input.Date)
+	if err != nil {
+		return nil, apperror.Field("date", "format", "must be YYYY-MM-DD")
+	}
+
+	if err := checkCampus(s.Campuses.WithContext(ctx), input.CampusID, schoolID); err != nil {
+		return nil, err
+	}
+
+	holiday := &model.Holiday{SchoolID: schoolID, Date: date, Name: input.Name}
+	if input.CampusID != nil {
+		holiday.CampusID = *input.CampusID
+	}
+	if err := s.Repo.WithContext(ctx).Create(holiday); err != nil {
+		return nil, err
+	}
+	return holiday, nil
+}
+
+// GetHolidays lists the holidays of the year; with campusID set, only those
+// closing that campus.
+func (s *HolidayService) GetHolidays(ctx context.Context, year int, campusID uint) ([]model.Holiday, error) {
+	ctx, span := tracing.Start(ctx, "HolidayService.GetHolidays")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).FindByYear(year, campusID)
+}
+
+func (s *HolidayService) DeleteHoliday(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "HolidayService.DeleteHoliday")
+	defer span.End()
+
+	return notFound(s.Repo.WithContext(ctx).Delete(id), ErrHolidayNotFound)
+}
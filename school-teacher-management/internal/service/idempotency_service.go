@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIdempotencyKeyReused  = apperror.Rule("idempotency_key_reused", "Idempotency-Key was already used for a different request")
+	ErrIdempotencyInProgress = apperror.Conflict("idempotency_in_progress", "a request with this Idempotency-Key is still being processed")
+)
+
+// IdempotencyService stores the responses to POSTs sent with an
+// Idempotency-Key for TTL and replays them to retries.
+type IdempotencyService struct {
+	Repo *repository.IdempotencyRepository
+	TTL  time.Duration
+}
+
+func NewIdempotencyService(repo *repository.IdempotencyRepository, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{Repo: repo, TTL: ttl}
+}
+
+// Begin reserves the record's key for the request. When the key was
+// already used for the same request it returns the stored record, whose
+// response is to be replayed; otherwise nil, and the request is to be
+// processed and then passed to Complete or Abandon.
+func (s *IdempotencyService) Begin(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	repo := s.Repo.WithContext(ctx)
+
+	// The second attempt follows the removal of an expired record.
+	for attempt := 0; attempt < 2; attempt++ {
+		now := time.Now()
+		record.ExpiresAt = now.Add(s.TTL)
+
+		reserved, err := repo.Reserve(record)
+		if err != nil {
+			return nil, err
+		}
+		if reserved {
+			return nil, nil
+		}
+
+		stored, err := repo.Find(record.Caller, record.Method, record.Path, record.Key)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case stored.ExpiresAt.Before(now):
+			if err := repo.Delete(stored.ID); err != nil {
+				return nil, err
+			}
+		case stored.RequestHash != record.RequestHash:
+			return nil, ErrIdempotencyKeyReused
+		case stored.StatusCode == 0:
+			return nil, ErrIdempotencyInProgress
+		default:
+			return stored, nil
+		}
+	}
+	return nil, ErrIdempotencyInProgress
+}
+
+// Complete stores the response of a request begun with Begin.
+func (s *IdempotencyService) Complete(ctx context.Context, record *model.IdempotencyRecord) error {
+	return s.Repo.WithContext(ctx).Complete(record)
+}
+
+// Abandon releases the key of a request that failed on the server, so a
+// retry is processed again.
+func (s *IdempotencyService) Abandon(ctx context.Context, record *model.IdempotencyRecord) error {
+	return s.Repo.WithContext(ctx).Delete(record.ID)
+}
+
+// Run removes expired records every hour until ctx is done.
+func (s *IdempotencyService) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Repo.WithContext(ctx).DeleteExpired(time.Now()); err != nil {
+			slog.ErrorContext(ctx, "idempotency purge", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
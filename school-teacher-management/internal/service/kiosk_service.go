@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/auth"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/tracing"
+	"time"
+)
+
+var (
+	ErrKioskNotFound     = apperror.NotFound("kiosk_not_found", "kiosk not found")
+	ErrInvalidKioskToken = apperror.Unauthorized("invalid_kiosk_token", "invalid or expired kiosk token")
+	ErrUnknownKiosk      = apperror.Unauthorized("unknown_kiosk", "unknown or inactive kiosk")
+)
+
+type KioskService struct {
+	Repo     *repository.KioskRepository
+	Campuses *repository.CampusRepository
+	Secret   []byte
+	TokenTTL time.Duration
+}
+
+func NewKioskService(
+	repo *repository.KioskRepository,
+	campuses *repository.CampusRepository,
+	secret []byte,
+	ttl time.Duration,
+) *KioskService {
+	return &KioskService{Repo: repo, Campuses: campuses, Secret: secret, TokenTTL: ttl}
+}
+
+// RegisterKiosk creates a kiosk and its key. Only the key's hash is kept.
+// Punches made through the kiosk are recorded at its campus.
+func (s *KioskService) RegisterKiosk(ctx context.Context, input *model.KioskInput) (*model.KioskRegistration, error) {
+	ctx, span := tracing.Start(ctx, "KioskService.RegisterKiosk")
+	defer span.End()
+
+	if err := checkOwnCampus(ctx, s.Campuses, input.CampusID); err != nil {
+		return nil, err
+	}
+
+	key, err := newDeviceKey()
+	if err != nil {
+		return nil, err
+	}
+
+	kiosk := model.Kiosk{
+		Name:     input.Name,
+		Location: input.Location,
+		CampusID: input.CampusID,
+		KeyHash:  hashDeviceKey(key),
+		Active:   true,
+	}
+	if err := s.Repo.WithContext(ctx).Create(&kiosk); err != nil {
+		return nil, err
+	}
+
+	return &model.KioskRegistration{Kiosk: kiosk, Key: key}, nil
+}
+
+func (s *KioskService) GetKiosks(ctx context.Context) ([]model.Kiosk, error) {
+	ctx, span := tracing.Start(ctx, "KioskService.GetKiosks")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).GetAll()
+}
+
+// DeactivateKiosk stops the kiosk issuing tokens; tokens it already showed
+// are refused too.
+func (s *KioskService) DeactivateKiosk(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "KioskService.DeactivateKiosk")
+	defer span.End()
+
+	repo := s.Repo.WithContext(ctx)
+	kiosk, err := repo.GetByID(id)
+	if err != nil {
+		return notFound(err, ErrKioskNotFound)
+	}
+
+	kiosk.Active = false
+	return repo.Update(kiosk)
+}
+
+// IssueToken signs a fresh QR token for the kiosk holding key. The kiosk
+// is looked up in the school of the request's subdomain.
+func (s *KioskService) IssueToken(ctx context.Context, key string) (*model.KioskToken, error) {
+	ctx, span := tracing.Start(ctx, "KioskService.IssueToken")
+	defer span.End()
+
+	kiosk, err := s.Repo.WithContext(ctx).GetByKeyHash(hashDeviceKey(key))
+	if err != nil || !kiosk.Active {
+		return nil, ErrUnknownKiosk
+	}
+
+	token, expires, err := auth.IssueKioskToken(kiosk.ID, s.TokenTTL, s.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.KioskToken{Token: token, ExpiresAt: expires}, nil
+}
+
+// VerifyToken returns the kiosk that displayed a fresh, genuine token. A
+// kiosk of another school than the request's is not found.
+func (s *KioskService) VerifyToken(ctx context.Context, token string) (*model.Kiosk, error) {
+	ctx, span := tracing.Start(ctx, "KioskService.VerifyToken")
+	defer span.End()
+
+	claims, err := auth.ParseKioskToken(token, s.Secret)
+	if err != nil {
+		return nil, ErrInvalidKioskToken
+	}
+
+	kiosk, err := s.Repo.WithContext(ctx).GetByID(claims.KioskID)
+	if err != nil || !kiosk.Active {
+		return nil, ErrInvalidKioskToken
+	}
+
+	return kiosk, nil
+}
+
+// newDeviceKey generates the credential of a kiosk or terminal.
+func newDeviceKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashDeviceKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
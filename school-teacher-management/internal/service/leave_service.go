@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/tracing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrLeaveNotFound = apperror.NotFound("leave_not_found", "leave request not found")
+
+type LeaveService struct {
+	Repo     *repository.LeaveRepository
+	Teachers *repository.TeacherRepository
+	Overtime *OvertimeService
+	Notifier *NotificationService
+}
+
+func NewLeaveService(
+	repo *repository.LeaveRepository,
+	teachers *repository.TeacherRepository,
+	overtime *OvertimeService,
+	notifier *NotificationService,
+) *LeaveService {
+	return &LeaveService{Repo: repo, Teachers: teachers, Overtime: overtime, Notifier: notifier}
+}
+
+func (s *LeaveService) RequestLeave(ctx context.Context, input *model.LeaveRequestInput) (*model.LeaveRequest, error) {
+	ctx, span := tracing.Start(ctx, "LeaveService.RequestLeave")
+	defer span.End()
+
+	start, err := time.Parse("2006-01-02", input.StartDate)
+	if err != nil {
+		return nil, apperror.Field("start_date", "format", "must be YYYY-MM-DD")
+	}
+
+	end, err := time.Parse("2006-01-02", input.EndDate)
+	if err != nil {
+		return nil, apperror.Field("end_date", "format", "must be YYYY-MM-DD")
+	}
+
+	if end.Before(start) {
+		return nil, apperror.Validation("invalid_date_range", "end_date is before start_date")
+	}
+
+	teacher, err := s.Teachers.WithContext(ctx).GetByID(input.TeacherID)
+	if err != nil {
+		return nil, ErrTeacherNotFound
+	}
+
+	leaveType := input.Type
+	switch leaveType {
+	case "":
+		leaveType = model.LeaveGeneral
+	case model.LeaveGeneral:
+	case model.LeaveCompOff:
+		if err := s.checkCompOff(ctx, teacher, start, end); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, apperror.Field("type", "oneof", "must be general or comp_off")
+	}
+
+	repo := s.Repo.WithContext(ctx)
+
+	overlapping, err := repo.FindOverlapping(input.TeacherID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(overlapping) > 0 {
+		return nil, apperror.Conflict("leave_overlaps", "leave already requested for some of these days")
+	}
+
+	leave := &model.LeaveRequest{
+		TeacherID: input.TeacherID,
+		Type:      leaveType,
+		StartDate: start,
+		EndDate:   end,
+		Reason:    input.Reason,
+		Status:    model.LeavePending,
+	}
+
+	if err := repo.Create(leave); err != nil {
+		return nil, err
+	}
+
+	leave, err = repo.GetByID(leave.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	go s.Notifier.NotifyLeaveRequested(leave)
+	return leave, nil
+}
+
+// DecideLeave approves or rejects a pending request. decidedBy is the
+// approver's teacher ID, or 0 for an administrator without one.
+func (s *LeaveService) DecideLeave(ctx context.Context, id uint, approve bool, decidedBy uint, note string) (*model.LeaveRequest, error) {
+	ctx, span := tracing.Start(ctx, "LeaveService.DecideLeave")
+	defer span.End()
+
+	leave, err := s.Repo.WithContext(ctx).GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if leave.Status != model.LeavePending {
+		return nil, apperror.Conflict("leave_already_decided", "leave request has already been "+leave.Status)
+	}
+
+	if decidedBy != 0 && decidedBy == leave.TeacherID {
+		return nil, apperror.Forbidden("own_leave", "cannot decide your own leave request")
+	}
+
+	now := time.Now()
+	leave.Status = model.LeaveRejected
+	if approve {
+		leave.Status = model.LeaveApproved
+	}
+	leave.DecisionNote = note
+	leave.DecidedAt = &now
+	if decidedBy != 0 {
+		leave.DecidedBy = &decidedBy
+	}
+
+	err = s.Repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if leave.Status == model.LeaveApproved && leave.Type == model.LeaveCompOff {
+			days, err := s.Overtime.WorkingDays(ctx, leave.Teacher.SchoolID, leave.Teacher.HomeCampus(), leave.StartDate, leave.EndDate)
+			if err != nil {
+				return err
+			}
+			if err := s.Overtime.SpendCompOff(tx, leave.TeacherID, leave.StartDate, float64(days)); err != nil {
+				return err
+			}
+		}
+		return s.Repo.WithTx(tx).Update(leave)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go s.Notifier.NotifyLeaveDecided(leave)
+	return leave, nil
+}
+
+// checkCompOff fails early when the credits usable on the first day do not
+// cover the working days requested. The balance is spent on approval.
+func (s *LeaveService) checkCompOff(ctx context.Context, teacher *model.Teacher, start, end time.Time) error {
+	days, err := s.Overtime.WorkingDays(ctx, teacher.SchoolID, teacher.HomeCampus(), start, end)
+	if err != nil {
+		return err
+	}
+
+	credits, err := s.Overtime.Repo.WithContext(ctx).FindActiveCredits(teacher.ID, start, false)
+	if err != nil {
+		return err
+	}
+
+	available := 0.0
+	for i := range credits {
+		available += credits[i].Remaining()
+	}
+	if available < float64(days) {
+		return ErrInsufficientCompOff
+	}
+	return nil
+}
+
+func (s *LeaveService) GetLeave(ctx context.Context, id uint) (*model.LeaveRequest, error) {
+	ctx, span := tracing.Start(ctx, "LeaveService.GetLeave")
+	defer span.End()
+
+	v, err := s.Repo.WithContext(ctx).GetByID(id)
+	return v, notFound(err, ErrLeaveNotFound)
+}
+
+func (s *LeaveService) GetLeaves(ctx context.Context, teacherID uint, department string, status string) ([]model.LeaveRequest, error) {
+	ctx, span := tracing.Start(ctx, "LeaveService.GetLeaves")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).Find(teacherID, department, status)
+}
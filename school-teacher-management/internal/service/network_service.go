@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"net/netip"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/config"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/tracing"
+)
+
+var (
+	ErrOffNetwork      = apperror.Forbidden("off_network", "check-in is only accepted from the campus network")
+	ErrNetworkNotFound = apperror.NotFound("network_not_found", "network not found")
+)
+
+type NetworkService struct {
+	Repo     *repository.NetworkRepository
+	Campuses *repository.CampusRepository
+	// Policy applies to schools that set none of their own.
+	Policy string
+}
+
+func NewNetworkService(repo *repository.NetworkRepository, campuses *repository.CampusRepository, policy string) *NetworkService {
+	return &NetworkService{Repo: repo, Campuses: campuses, Policy: policy}
+}
+
+// CreateNetwork adds a range to a campus of the school the request is
+// scoped to.
+func (s *NetworkService) CreateNetwork(ctx context.Context, input *model.CampusNetworkInput) (*model.CampusNetwork, error) {
+	ctx, span := tracing.Start(ctx, "NetworkService.CreateNetwork")
+	defer span.End()
+
+	prefix, err := netip.ParsePrefix(input.CIDR)
+	if err != nil {
+		return nil, apperror.Field("cidr", "cidr", "must be an address range such as 10.20.0.0/16")
+	}
+
+	schoolID, err := currentSchool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCampus(s.Campuses.WithContext(ctx), &input.CampusID, schoolID); err != nil {
+		return nil, err
+	}
+
+	network := &model.CampusNetwork{
+		SchoolID:    schoolID,
+		CampusID:    input.CampusID,
+		CIDR:        prefix.Masked().String(),
+		Description: input.Description,
+	}
+	if err := s.Repo.WithContext(ctx).Create(network); err != nil {
+		return nil, err
+	}
+	return network, nil
+}
+
+func (s *NetworkService) GetNetworks(ctx context.Context) ([]model.CampusNetwork, error) {
+	ctx, span := tracing.Start(ctx, "NetworkService.GetNetworks")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).GetAll()
+}
+
+func (s *NetworkService) DeleteNetwork(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "NetworkService.DeleteNetwork")
+	defer span.End()
+
+	return notFound(s.Repo.WithContext(ctx).Delete(id), ErrNetworkNotFound)
+}
+
+// Check reports whether ip is outside every network of the school's
+// campuses. With no networks configured, or the school's policy off,
+// nothing is off-network. An empty or malformed ip is off-network. Under
+// the reject policy an off-network address fails with ErrOffNetwork.
+func (s *NetworkService) Check(ctx context.Context, school *model.School, ip string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "NetworkService.Check")
+	defer span.End()
+
+	mode := s.Policy
+	if school.NetworkPolicy != "" {
+		mode = school.NetworkPolicy
+	}
+	if mode == config.NetworkOff {
+		return false, nil
+	}
+
+	networks, err := s.Repo.WithContext(ctx).ForSchool(school.ID)
+	if err != nil {
+		return false, err
+	}
+	if len(networks) == 0 {
+		return false, nil
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err == nil {
+		addr = addr.Unmap()
+		for _, n := range networks {
+			if prefix, err := netip.ParsePrefix(n.CIDR); err == nil && prefix.Contains(addr) {
+				return false, nil
+			}
+		}
+	}
+
+	if mode == config.NetworkReject {
+		return true, ErrOffNetwork
+	}
+	return true, nil
+}
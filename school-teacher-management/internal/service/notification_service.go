@@ -0,0 +1,502 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"school-teacher-management/internal/config"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/notification"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/requestctx"
+	"school-teacher-management/internal/tracing"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationService struct {
+	Repo       *repository.NotificationRepository
+	Teachers   *repository.TeacherRepository
+	Attendance *repository.AttendanceRepository
+	Leaves     *repository.LeaveRepository
+	Schools    *repository.SchoolRepository
+	Campuses   *repository.CampusRepository
+	Outbox     *repository.OutboxRepository
+	Mailer     notification.Mailer
+	Schedule   config.NotificationSchedule
+
+	// lastRun remembers the day each scheduled job last completed for each
+	// school on this instance. NotificationLog prevents duplicates across
+	// instances.
+	lastRun map[scheduledRun]time.Time
+}
+
+type scheduledRun struct {
+	kind     string
+	schoolID uint
+}
+
+func NewNotificationService(
+	repo *repository.NotificationRepository,
+	teachers *repository.TeacherRepository,
+	attendance *repository.AttendanceRepository,
+	leaves *repository.LeaveRepository,
+	schools *repository.SchoolRepository,
+	campuses *repository.CampusRepository,
+	outbox *repository.OutboxRepository,
+	mailer notification.Mailer,
+	schedule config.NotificationSchedule,
+) *NotificationService {
+	return &NotificationService{
+		Repo:       repo,
+		Teachers:   teachers,
+		Attendance: attendance,
+		Leaves:     leaves,
+		Schools:    schools,
+		Campuses:   campuses,
+		Outbox:     outbox,
+		Mailer:     mailer,
+		Schedule:   schedule,
+		lastRun:    map[scheduledRun]time.Time{},
+	}
+}
+
+func (s *NotificationService) GetPreference(ctx context.Context, teacherID uint) (*model.NotificationPreference, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.GetPreference")
+	defer span.End()
+
+	if _, err := s.Teachers.WithContext(ctx).GetByID(teacherID); err != nil {
+		return nil, notFound(err, ErrTeacherNotFound)
+	}
+	return s.Repo.WithContext(ctx).GetPreference(teacherID)
+}
+
+func (s *NotificationService) UpdatePreference(ctx context.Context, teacherID uint, req *model.NotificationPreferenceRequest) (*model.NotificationPreference, error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.UpdatePreference")
+	defer span.End()
+
+	if _, err := s.Teachers.WithContext(ctx).GetByID(teacherID); err != nil {
+		return nil, notFound(err, ErrTeacherNotFound)
+	}
+
+	pref := &model.NotificationPreference{
+		TeacherID:         teacherID,
+		LateAlerts:        req.LateAlerts,
+		CheckoutReminders: req.CheckoutReminders,
+		LeaveUpdates:      req.LeaveUpdates,
+		DailyDigest:       req.DailyDigest,
+	}
+
+	if err := s.Repo.WithContext(ctx).SavePreference(pref); err != nil {
+		return nil, err
+	}
+	return pref, nil
+}
+
+// NotifyLeaveRequested emails the heads of the teacher's department at the
+// teacher's school.
+func (s *NotificationService) NotifyLeaveRequested(leave *model.LeaveRequest) {
+	ctx := requestctx.WithSchool(context.Background(), leave.Teacher.SchoolID)
+	heads, err := s.Teachers.WithContext(ctx).FindDepartmentHeads(leave.Teacher.Department)
+	if err != nil {
+		slog.ErrorContext(ctx, "leave request notification", "error", err)
+		return
+	}
+
+	for _, head := range heads {
+		if head.ID == leave.TeacherID || !s.wants(head.ID, leaveUpdates) {
+			continue
+		}
+
+		s.send(head, notification.TemplateLeaveRequested, notification.LeaveRequestedData{
+			RecipientName: head.FirstName,
+			TeacherName:   fullName(leave.Teacher),
+			Department:    leave.Teacher.Department,
+			LeaveID:       leave.ID,
+			StartDate:     leave.StartDate.Format("02-01-2006"),
+			EndDate:       leave.EndDate.Format("02-01-2006"),
+			Days:          leave.Days(),
+			Reason:        leave.Reason,
+		})
+	}
+}
+
+// NotifyLeaveDecided emails the teacher the outcome of their request.
+func (s *NotificationService) NotifyLeaveDecided(leave *model.LeaveRequest) {
+	if !s.wants(leave.TeacherID, leaveUpdates) {
+		return
+	}
+
+	s.send(leave.Teacher, notification.TemplateLeaveDecided, notification.LeaveDecidedData{
+		RecipientName: leave.Teacher.FirstName,
+		LeaveID:       leave.ID,
+		StartDate:     leave.StartDate.Format("02-01-2006"),
+		EndDate:       leave.EndDate.Format("02-01-2006"),
+		Status:        leave.Status,
+		Note:          leave.DecisionNote,
+	})
+}
+
+// Run checks once a minute whether a scheduled job is due for a school.
+// The schedule follows each school's timezone, and jobs are skipped at the
+// school's weekends.
+func (s *NotificationService) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		s.runDue(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *NotificationService) runDue(now time.Time) {
+	schools, err := s.Schools.GetAll()
+	if err != nil {
+		slog.Error("notification schools", "error", err)
+		return
+	}
+
+	for i := range schools {
+		if schools[i].Active {
+			s.runSchoolDue(&schools[i], now)
+		}
+	}
+}
+
+// runSchoolDue runs the school's jobs that are due. The late alert goes
+// out as long after the school's start of work as NOTIFY_LATE_AT is after
+// WORK_START.
+func (s *NotificationService) runSchoolDue(school *model.School, now time.Time) {
+	loc := school.Location()
+	local := now.In(loc)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return
+	}
+
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	workStart, _ := model.WorkDay(school, nil, s.Schedule.WorkStart, 0)
+	jobs := []struct {
+		kind string
+		at   time.Duration
+		run  func(school *model.School, now time.Time) error
+	}{
+		{model.NotifyLateAlert, workStart + s.Schedule.LateAlertAt - s.Schedule.WorkStart, s.sendLateAlerts},
+		{model.NotifyCheckoutReminder, s.Schedule.CheckoutReminder, s.sendCheckoutReminders},
+		{model.NotifyDailyDigest, s.Schedule.DigestAt, s.sendDigests},
+	}
+
+	for _, job := range jobs {
+		run := scheduledRun{job.kind, school.ID}
+		if now.Before(midnight.Add(job.at)) || s.lastRun[run].Equal(midnight) {
+			continue
+		}
+		if err := job.run(school, now); err != nil {
+			slog.Error("notification job", "job", job.kind, "school_id", school.ID, "error", err)
+			continue
+		}
+		s.lastRun[run] = midnight
+	}
+}
+
+// sendLateAlerts tells each department head who has not arrived, or arrived
+// late. Heads who chose the daily digest get this in the digest instead.
+// Every teacher who has not arrived is also reported to the webhooks.
+func (s *NotificationService) sendLateAlerts(school *model.School, now time.Time) error {
+	days, absent, err := s.schoolDepartmentDays(school, now)
+	if err != nil {
+		return err
+	}
+	date := school.Today(now)
+
+	for _, day := range days {
+		if len(day.NoShows) == 0 && len(day.Late) == 0 {
+			continue
+		}
+
+		for _, head := range day.heads {
+			if !s.wants(head.ID, lateAlerts) {
+				continue
+			}
+
+			s.sendOnce(model.NotifyLateAlert, date, head, notification.TemplateLateAlert, notification.LateAlertData{
+				RecipientName: head.FirstName,
+				Department:    day.Department,
+				Date:          day.Date,
+				AsOf:          now.In(school.Location()).Format("15:04"),
+				WorkStart:     formatClock(day.workStart),
+				NoShows:       day.NoShows,
+				Late:          day.Late,
+			})
+		}
+	}
+	return s.publishAbsences(school, date, absent)
+}
+
+// publishAbsences records an attendance.absent event for each teacher,
+// once per teacher and day. The notification log entry that marks it done
+// is written in the same transaction as the event.
+func (s *NotificationService) publishAbsences(school *model.School, date time.Time, absent []model.Teacher) error {
+	ctx := requestctx.WithSchool(context.Background(), school.ID)
+
+	for _, t := range absent {
+		event := model.AttendanceEvent{
+			Type:        model.EventAbsent,
+			SchoolID:    school.ID,
+			TeacherID:   t.ID,
+			TeacherName: fullName(t),
+			Department:  t.Department,
+			Status:      model.StatusAbsent,
+			Date:        date.Format("02-01-2006"),
+			OccurredAt:  time.Now(),
+		}
+
+		err := s.Repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			first, err := s.Repo.WithTx(tx).ClaimSend(model.EventAbsent, t.ID, date)
+			if err != nil || !first {
+				return err
+			}
+			return s.Outbox.WithTx(tx).Add(school.ID, model.EventAbsent, event)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *NotificationService) sendCheckoutReminders(school *model.School, now time.Time) error {
+	ctx := requestctx.WithSchool(context.Background(), school.ID)
+	date := school.Today(now)
+	list, err := s.Attendance.WithContext(ctx).FindByDate(date)
+	if err != nil {
+		return err
+	}
+
+	for _, att := range list {
+		if att.CheckIn == nil || att.CheckOut != nil || !s.wants(att.TeacherID, checkoutReminders) {
+			continue
+		}
+
+		s.sendOnce(model.NotifyCheckoutReminder, date, att.Teacher, notification.TemplateCheckoutReminder, notification.CheckoutReminderData{
+			RecipientName: att.Teacher.FirstName,
+			Date:          att.Date.Format("02-01-2006"),
+			CheckIn:       att.CheckIn.In(school.Location()).Format("15:04"),
+		})
+	}
+	return nil
+}
+
+func (s *NotificationService) sendDigests(school *model.School, now time.Time) error {
+	days, _, err := s.schoolDepartmentDays(school, now)
+	if err != nil {
+		return err
+	}
+	date := school.Today(now)
+
+	for _, day := range days {
+		for _, head := range day.heads {
+			if !s.wants(head.ID, dailyDigest) {
+				continue
+			}
+
+			data := day.DailyDigestData
+			data.RecipientName = head.FirstName
+			s.sendOnce(model.NotifyDailyDigest, date, head, notification.TemplateDailyDigest, data)
+		}
+	}
+	return nil
+}
+
+type departmentDay struct {
+	notification.DailyDigestData
+	heads     []model.Teacher
+	workStart time.Duration
+}
+
+// schoolDepartmentDays summarises one school's day, in its timezone, and
+// returns the teachers of every department, or none, who have not arrived.
+// A teacher is late against the start of work at the campus checked in at,
+// else the home campus, else the school.
+func (s *NotificationService) schoolDepartmentDays(school *model.School, now time.Time) ([]departmentDay, []model.Teacher, error) {
+	ctx := requestctx.WithSchool(context.Background(), school.ID)
+	date := school.Today(now)
+
+	teachers, err := s.Teachers.WithContext(ctx).GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attendance, err := s.Attendance.WithContext(ctx).FindByDate(date)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	leaves, err := s.Leaves.WithContext(ctx).FindApprovedOn(date)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	campuses, err := s.Campuses.WithContext(ctx).GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	campusByID := map[uint]*model.Campus{}
+	for i := range campuses {
+		campusByID[campuses[i].ID] = &campuses[i]
+	}
+
+	byTeacher := map[uint]model.Attendance{}
+	for _, att := range attendance {
+		byTeacher[att.TeacherID] = att
+	}
+
+	onLeave := map[uint]bool{}
+	for _, leave := range leaves {
+		onLeave[leave.TeacherID] = true
+	}
+
+	loc := school.Location()
+	local := now.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	workStart, _ := model.WorkDay(school, nil, s.Schedule.WorkStart, 0)
+
+	days := map[string]*departmentDay{}
+	var absent []model.Teacher
+	for _, t := range teachers {
+		att, checkedIn := byTeacher[t.ID]
+		noShow := !onLeave[t.ID] && (!checkedIn || att.CheckIn == nil)
+		if noShow {
+			absent = append(absent, t)
+		}
+
+		if t.Department == "" {
+			continue
+		}
+
+		day, ok := days[t.Department]
+		if !ok {
+			day = &departmentDay{DailyDigestData: notification.DailyDigestData{
+				Department: t.Department,
+				Date:       date.Format("02-01-2006"),
+			}, workStart: workStart}
+			days[t.Department] = day
+		}
+
+		if t.HeadOfDepartment {
+			day.heads = append(day.heads, t)
+		}
+
+		day.Total++
+		name := fullName(t)
+
+		switch {
+		case onLeave[t.ID]:
+			day.OnLeave = append(day.OnLeave, name)
+		case noShow:
+			day.NoShows = append(day.NoShows, name)
+		default:
+			day.Present++
+			campus := campusByID[t.HomeCampus()]
+			if att.CheckInCampusID != nil {
+				campus = campusByID[*att.CheckInCampusID]
+			}
+			start, _ := model.WorkDay(school, campus, s.Schedule.WorkStart, 0)
+			if att.CheckIn.After(midnight.Add(start)) {
+				day.Late = append(day.Late, name+" ("+att.CheckIn.In(loc).Format("15:04")+")")
+			}
+			if att.CheckOut == nil {
+				day.MissingCheckOut = append(day.MissingCheckOut, name)
+			}
+		}
+	}
+
+	result := make([]departmentDay, 0, len(days))
+	for _, day := range days {
+		if len(day.heads) > 0 {
+			result = append(result, *day)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Department < result[j].Department })
+
+	return result, absent, nil
+}
+
+type preferenceCheck func(model.NotificationPreference) bool
+
+var (
+	lateAlerts = func(p model.NotificationPreference) bool {
+		return p.LateAlerts && !p.DailyDigest
+	}
+	checkoutReminders = func(p model.NotificationPreference) bool { return p.CheckoutReminders }
+	leaveUpdates      = func(p model.NotificationPreference) bool { return p.LeaveUpdates }
+	dailyDigest       = func(p model.NotificationPreference) bool { return p.DailyDigest }
+)
+
+func (s *NotificationService) wants(teacherID uint, check preferenceCheck) bool {
+	pref, err := s.Repo.GetPreference(teacherID)
+	if err != nil {
+		slog.Error("notification preference", "teacher_id", teacherID, "error", err)
+		return false
+	}
+	return check(*pref)
+}
+
+// sendOnce sends a scheduled notification unless it already went out on
+// the school's date. A failed send gives up its claim so the next run tries
+// again.
+func (s *NotificationService) sendOnce(kind string, date time.Time, to model.Teacher, template string, data interface{}) {
+	first, err := s.Repo.ClaimSend(kind, to.ID, date)
+	if err != nil {
+		slog.Error("notification log", "kind", kind, "error", err)
+		return
+	}
+	if !first {
+		return
+	}
+
+	if !s.send(to, template, data) {
+		if err := s.Repo.ReleaseSend(kind, to.ID, date); err != nil {
+			slog.Error("notification log", "kind", kind, "error", err)
+		}
+	}
+}
+
+func (s *NotificationService) send(to model.Teacher, template string, data interface{}) bool {
+	if to.Email == "" {
+		return true
+	}
+
+	subject, body, err := notification.Render(template, data)
+	if err != nil {
+		slog.Error("render notification", "template", template, "error", err)
+		return false
+	}
+
+	if err := s.Mailer.Send([]string{to.Email}, subject, body); err != nil {
+		slog.Error("send notification", "template", template, "teacher_id", to.ID, "error", err)
+		return false
+	}
+	return true
+}
+
+// attendanceDate matches the date MarkAttendance stores for a punch at a
+// school without a timezone.
+func attendanceDate(t time.Time) time.Time {
+	return t.Truncate(24 * time.Hour)
+}
+
+func formatClock(d time.Duration) string {
+	return time.Time{}.Add(d).Format("15:04")
+}
+
+func fullName(t model.Teacher) string {
+	return t.FirstName + " " + t.LastName
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"math"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/config"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/requestctx"
+	"school-teacher-management/internal/tracing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInsufficientCompOff = apperror.Rule("insufficient_comp_off", "not enough compensatory-off balance")
+	ErrOvertimeNotFound    = apperror.NotFound("overtime_not_found", "overtime record not found")
+)
+
+type OvertimeService struct {
+	Repo     *repository.OvertimeRepository
+	Holidays *repository.HolidayRepository
+	Schools  *repository.SchoolRepository
+	Campuses *repository.CampusRepository
+	Policy   config.OvertimePolicy
+}
+
+func NewOvertimeService(
+	repo *repository.OvertimeRepository,
+	holidays *repository.HolidayRepository,
+	schools *repository.SchoolRepository,
+	campuses *repository.CampusRepository,
+	policy config.OvertimePolicy,
+) *OvertimeService {
+	return &OvertimeService{Repo: repo, Holidays: holidays, Schools: schools, Campuses: campuses, Policy: policy}
+}
+
+// Detect records, inside the attendance write's transaction, the overtime
+// of a completed session: all of it on a weekend or holiday of the school
+// or campus, otherwise the time after the campus's end of the day. The
+// session's campus is where it was checked out, else where it was checked
+// in. Sessions below the minimum clear any pending record left by an
+// earlier punch or correction.
+func (s *OvertimeService) Detect(tx *gorm.DB, att *model.Attendance) error {
+	repo := s.Repo.WithTx(tx)
+
+	if att.CheckIn == nil || att.CheckOut == nil {
+		return repo.DeletePending(att.ID)
+	}
+
+	school, err := s.Schools.WithTx(tx).GetByID(att.SchoolID)
+	if err != nil {
+		return err
+	}
+
+	id := att.CheckOutCampusID
+	if id == nil {
+		id = att.CheckInCampusID
+	}
+
+	var campus *model.Campus
+	var campusID uint
+	if id != nil {
+		if campus, err = s.Campuses.WithTx(tx).GetByID(*id); err != nil {
+			return err
+		}
+		campusID = *id
+	}
+
+	holiday, err := s.Holidays.WithTx(tx).IsHoliday(att.SchoolID, campusID, att.Date)
+	if err != nil {
+		return err
+	}
+
+	kind := model.OvertimeAfterHours
+	start := *att.CheckIn
+
+	switch weekday := att.Date.UTC().Weekday(); {
+	case holiday:
+		kind = model.OvertimeHoliday
+	case weekday == time.Saturday || weekday == time.Sunday:
+		kind = model.OvertimeWeekend
+	default:
+		loc := school.Location()
+		local := att.CheckIn.In(loc)
+		_, workEnd := model.WorkDay(school, campus, 0, s.Policy.WorkEnd)
+		end := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc).Add(workEnd)
+		if end.After(start) {
+			start = end
+		}
+	}
+
+	minutes := int(att.CheckOut.Sub(start).Minutes())
+	if minutes < s.Policy.MinMinutes {
+		return repo.DeletePending(att.ID)
+	}
+
+	return repo.SavePending(&model.OvertimeRecord{
+		TeacherID:    att.TeacherID,
+		AttendanceID: att.ID,
+		Date:         att.Date,
+		Kind:         kind,
+		Minutes:      minutes,
+		Status:       model.OvertimePending,
+	})
+}
+
+func (s *OvertimeService) GetOvertime(ctx context.Context, id uint) (*model.OvertimeRecord, error) {
+	ctx, span := tracing.Start(ctx, "OvertimeService.GetOvertime")
+	defer span.End()
+
+	v, err := s.Repo.WithContext(ctx).GetByID(id)
+	return v, notFound(err, ErrOvertimeNotFound)
+}
+
+func (s *OvertimeService) GetOvertimes(ctx context.Context, teacherID uint, department string, status string) ([]model.OvertimeRecord, error) {
+	ctx, span := tracing.Start(ctx, "OvertimeService.GetOvertimes")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).Find(teacherID, department, status)
+}
+
+// DecideOvertime approves or rejects pending overtime. Approval as comp_off
+// credits whole half days of the scheduled day length.
+func (s *OvertimeService) DecideOvertime(
+	ctx context.Context,
+	id uint,
+	approve bool,
+	input *model.OvertimeDecisionInput,
+) (*model.OvertimeRecord, error) {
+	ctx, span := tracing.Start(ctx, "OvertimeService.DecideOvertime")
+	defer span.End()
+
+	actor := requestctx.Actor(ctx)
+
+	record, err := s.Repo.WithContext(ctx).GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.Status != model.OvertimePending {
+		return nil, apperror.Conflict("overtime_already_decided", "overtime has already been "+record.Status)
+	}
+
+	if actor.TeacherID != 0 && actor.TeacherID == record.TeacherID {
+		return nil, apperror.Forbidden("own_overtime", "cannot decide your own overtime")
+	}
+
+	now := time.Now()
+	record.Status = model.OvertimeRejected
+	record.DecidedBy = &actor
+	record.DecisionNote = input.Note
+	record.DecidedAt = &now
+
+	if !approve {
+		if err := s.Repo.WithContext(ctx).Update(record); err != nil {
+			return nil, err
+		}
+		return record, nil
+	}
+
+	record.Status = model.OvertimeApproved
+	record.Compensation = input.Compensation
+	if record.Compensation == "" {
+		record.Compensation = model.CompensationCompOff
+	}
+
+	var credit *model.CompOffCredit
+	switch record.Compensation {
+	case model.CompensationPaid:
+	case model.CompensationCompOff:
+		days := s.creditDays(record.Minutes)
+		if days == 0 {
+			return nil, apperror.Rule("overtime_too_short", "overtime is shorter than half a day; approve it as paid instead")
+		}
+		credit = &model.CompOffCredit{
+			TeacherID: record.TeacherID,
+			Days:      days,
+			EarnedOn:  record.Date,
+			ExpiresOn: record.Date.Add(s.Policy.CreditValidity),
+		}
+	default:
+		return nil, apperror.Field("compensation", "oneof", "must be paid or comp_off")
+	}
+
+	err = s.Repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := s.Repo.WithTx(tx)
+		if err := repo.Update(record); err != nil {
+			return err
+		}
+		if credit == nil {
+			return nil
+		}
+		credit.OvertimeID = record.ID
+		return repo.AddCredit(credit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (s *OvertimeService) creditDays(minutes int) float64 {
+	halfDay := s.Policy.DayLength().Minutes() / 2
+	if halfDay <= 0 {
+		return 0
+	}
+	return math.Floor(float64(minutes)/halfDay) / 2
+}
+
+// GetBalance lists the teacher's usable compensatory-off credits.
+func (s *OvertimeService) GetBalance(ctx context.Context, teacherID uint) (*model.CompOffBalance, error) {
+	ctx, span := tracing.Start(ctx, "OvertimeService.GetBalance")
+	defer span.End()
+
+	credits, err := s.Repo.WithContext(ctx).FindActiveCredits(teacherID, attendanceDate(time.Now()), false)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := &model.CompOffBalance{TeacherID: teacherID, Credits: credits}
+	for i := range credits {
+		balance.AvailableDays += credits[i].Remaining()
+	}
+
+	return balance, nil
+}
+
+// SpendCompOff takes days from the teacher's credits, soonest expiry first,
+// valid on the first day of the leave. It must run in the approving
+// transaction.
+func (s *OvertimeService) SpendCompOff(tx *gorm.DB, teacherID uint, on time.Time, days float64) error {
+	repo := s.Repo.WithTx(tx)
+
+	credits, err := repo.FindActiveCredits(teacherID, on, true)
+	if err != nil {
+		return err
+	}
+
+	available := 0.0
+	for i := range credits {
+		available += credits[i].Remaining()
+	}
+	if available < days {
+		return ErrInsufficientCompOff
+	}
+
+	for i := range credits {
+		if days <= 0 {
+			break
+		}
+		used := math.Min(credits[i].Remaining(), days)
+		credits[i].UsedDays += used
+		days -= used
+		if err := repo.UpdateCredit(&credits[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WorkingDays counts the weekdays from start to end, inclusive, that are
+// not holidays of the school or of the campus.
+func (s *OvertimeService) WorkingDays(ctx context.Context, schoolID, campusID uint, start, end time.Time) (int, error) {
+	holidays, err := s.Holidays.WithContext(ctx).FindBetween(start, end)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := map[string]bool{}
+	for _, h := range holidays {
+		if h.SchoolID == schoolID && (h.CampusID == 0 || h.CampusID == campusID) {
+			closed[h.Date.Format("2006-01-02")] = true
+		}
+	}
+
+	days := 0
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday && !closed[d.Format("2006-01-02")] {
+			days++
+		}
+	}
+	return days, nil
+}
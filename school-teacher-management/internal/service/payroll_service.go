@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/config"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/tracing"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// payrollColumns are the fields a PAYROLL_CSV_COLUMNS layout may name.
+var payrollColumns = map[string]func(model.PayrollLine) string{
+	"teacher_id":       func(l model.PayrollLine) string { return strconv.FormatUint(uint64(l.TeacherID), 10) },
+	"first_name":       func(l model.PayrollLine) string { return l.FirstName },
+	"last_name":        func(l model.PayrollLine) string { return l.LastName },
+	"name":             func(l model.PayrollLine) string { return l.FirstName + " " + l.LastName },
+	"email":            func(l model.PayrollLine) string { return l.Email },
+	"department":       func(l model.PayrollLine) string { return l.Department },
+	"campus_id":        func(l model.PayrollLine) string { return strconv.FormatUint(uint64(l.CampusID), 10) },
+	"working_days":     func(l model.PayrollLine) string { return strconv.Itoa(l.WorkingDays) },
+	"present_days":     func(l model.PayrollLine) string { return strconv.Itoa(l.PresentDays) },
+	"leave_days":       func(l model.PayrollLine) string { return strconv.Itoa(l.LeaveDays) },
+	"absent_days":      func(l model.PayrollLine) string { return strconv.Itoa(l.AbsentDays) },
+	"half_days":        func(l model.PayrollLine) string { return strconv.Itoa(l.HalfDays) },
+	"marked_half_days": func(l model.PayrollLine) string { return strconv.Itoa(l.MarkedHalfDays) },
+	"lop_days":         func(l model.PayrollLine) string { return formatDecimal(l.LOPDays) },
+	"payable_days":     func(l model.PayrollLine) string { return formatDecimal(l.PayableDays) },
+	"overtime_hours":   func(l model.PayrollLine) string { return formatDecimal(l.OvertimeHours) },
+	"late_arrivals":    func(l model.PayrollLine) string { return strconv.Itoa(l.LateArrivals) },
+	"late_deductions":  func(l model.PayrollLine) string { return strconv.Itoa(l.LateDeductions) },
+}
+
+type PayrollService struct {
+	Teachers   *repository.TeacherRepository
+	Attendance *repository.AttendanceRepository
+	Leaves     *repository.LeaveRepository
+	Overtime   *OvertimeService
+	Schools    *repository.SchoolRepository
+	Policy     config.PayrollPolicy
+}
+
+func NewPayrollService(
+	teachers *repository.TeacherRepository,
+	attendance *repository.AttendanceRepository,
+	leaves *repository.LeaveRepository,
+	overtime *OvertimeService,
+	schools *repository.SchoolRepository,
+	policy config.PayrollPolicy,
+) *PayrollService {
+	return &PayrollService{
+		Teachers:   teachers,
+		Attendance: attendance,
+		Leaves:     leaves,
+		Overtime:   overtime,
+		Schools:    schools,
+		Policy:     policy,
+	}
+}
+
+// CheckColumns reports an unknown name in the configured CSV layout.
+func (s *PayrollService) CheckColumns() error {
+	for _, name := range s.Policy.CSVColumns {
+		if _, ok := payrollColumns[strings.TrimSpace(name)]; !ok {
+			return fmt.Errorf("unknown payroll column %q", name)
+		}
+	}
+	return nil
+}
+
+// Report computes the payroll summary of every teacher, or with campusID
+// set of the teachers based at that campus, for the days from start to end,
+// inclusive. Weekdays that are not holidays of the teacher's school or home
+// campus are working days.
+// A day with a record is paid by its status: present, on duty, working
+// from home and leave are paid, half_day pays half, absent pays nothing and
+// holiday is not a working day. A working day without a record or approved
+// leave is an absence and a full day's loss of pay. Present days worked
+// short of the policy's hours are half days, and those beyond the
+// allowance cost half a day each. Days after today are counted as payable and never as absences, days
+// before the teacher was added are not counted at all. Overtime is what was
+// approved for payment; overtime taken as compensatory off is not paid.
+// Today and lateness follow each school's timezone and the start of work
+// at the campus checked in at, else the home campus, else the school.
+func (s *PayrollService) Report(ctx context.Context, start, end time.Time, campusID uint) (*model.PayrollReport, error) {
+	ctx, span := tracing.Start(ctx, "PayrollService.Report")
+	defer span.End()
+
+	if end.Before(start) {
+		return nil, apperror.Validation("invalid_date_range", "to must not be before from")
+	}
+	if end.Sub(start) > 366*24*time.Hour {
+		return nil, apperror.Validation("period_too_long", "pay period cannot exceed one year")
+	}
+
+	teachers, err := s.Teachers.WithContext(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	attendance, err := s.Attendance.WithContext(ctx).FindBetween(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	leaves, err := s.Leaves.WithContext(ctx).FindApprovedBetween(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := s.Overtime.Holidays.WithContext(ctx).FindBetween(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	paid, err := s.Overtime.Repo.WithContext(ctx).FindPaidBetween(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	schools, err := s.Schools.WithContext(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	campuses, err := s.Overtime.Campuses.WithContext(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	schoolByID := map[uint]*model.School{}
+	for i := range schools {
+		schoolByID[schools[i].ID] = &schools[i]
+	}
+
+	campusByID := map[uint]*model.Campus{}
+	for i := range campuses {
+		campusByID[campuses[i].ID] = &campuses[i]
+	}
+
+	// Holidays of the whole school have campusID 0.
+	type schoolDay struct {
+		schoolID uint
+		campusID uint
+		date     string
+	}
+
+	closed := map[schoolDay]bool{}
+	for _, h := range holidays {
+		closed[schoolDay{h.SchoolID, h.CampusID, h.Date.Format("2006-01-02")}] = true
+	}
+
+	overtime := map[uint]int{}
+	for _, record := range paid {
+		overtime[record.TeacherID] += record.Minutes
+	}
+
+	type dayKey struct {
+		teacherID uint
+		date      string
+	}
+
+	attended := map[dayKey]model.Attendance{}
+	for _, att := range attendance {
+		attended[dayKey{att.TeacherID, att.Date.Format("2006-01-02")}] = att
+	}
+
+	onLeave := map[dayKey]bool{}
+	for _, leave := range leaves {
+		for d := leave.StartDate; !d.After(leave.EndDate); d = d.AddDate(0, 0, 1) {
+			onLeave[dayKey{leave.TeacherID, d.Format("2006-01-02")}] = true
+		}
+	}
+
+	now := time.Now()
+	report := &model.PayrollReport{
+		From:  start.Format("2006-01-02"),
+		To:    end.Format("2006-01-02"),
+		Lines: make([]model.PayrollLine, 0, len(teachers)),
+	}
+
+	for _, t := range teachers {
+		if campusID != 0 && t.HomeCampus() != campusID {
+			continue
+		}
+
+		school := schoolByID[t.SchoolID]
+		home := campusByID[t.HomeCampus()]
+		today := school.Today(now).Format("2006-01-02")
+
+		line := model.PayrollLine{
+			SchoolID:   t.SchoolID,
+			CampusID:   t.HomeCampus(),
+			TeacherID:  t.ID,
+			FirstName:  t.FirstName,
+			LastName:   t.LastName,
+			Email:      t.Email,
+			Department: t.Department,
+		}
+		joined := t.CreatedAt.Format("2006-01-02")
+
+		for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+			date := d.Format("2006-01-02")
+			if date < joined {
+				continue
+			}
+
+			key := dayKey{t.ID, date}
+			att, recorded := attended[key]
+			if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday ||
+				closed[schoolDay{t.SchoolID, 0, date}] || closed[schoolDay{t.SchoolID, t.HomeCampus(), date}] ||
+				(recorded && att.Status == model.StatusHoliday) {
+				continue
+			}
+
+			line.WorkingDays++
+
+			status := att.Status
+			if !recorded {
+				status = model.StatusAbsent
+			}
+
+			switch status {
+			case model.StatusPresent, model.StatusOnDuty, model.StatusWorkFromHome, model.StatusHalfDay:
+				line.PresentDays++
+				if status == model.StatusHalfDay {
+					line.MarkedHalfDays++
+				}
+				if att.CheckIn == nil {
+					break
+				}
+				if status == model.StatusPresent {
+					worked := time.Duration(0)
+					if att.CheckOut != nil {
+						worked = att.CheckOut.Sub(*att.CheckIn)
+					}
+					if worked.Hours() < s.Policy.HalfDayBelowHours {
+						line.HalfDays++
+					}
+				}
+				campus := home
+				if att.CheckInCampusID != nil {
+					campus = campusByID[*att.CheckInCampusID]
+				}
+				if s.isLate(school, campus, *att.CheckIn) {
+					line.LateArrivals++
+				}
+			case model.StatusLeave:
+				line.LeaveDays++
+			default:
+				switch {
+				case onLeave[key]:
+					line.LeaveDays++
+				case date > today:
+				default:
+					line.AbsentDays++
+				}
+			}
+		}
+
+		line.LOPDays = float64(line.AbsentDays) + 0.5*float64(line.MarkedHalfDays)
+		if excess := line.HalfDays - s.Policy.HalfDaysAllowed; excess > 0 {
+			line.LOPDays += 0.5 * float64(excess)
+		}
+		line.PayableDays = float64(line.WorkingDays) - line.LOPDays
+		line.OvertimeHours = math.Round(float64(overtime[t.ID])/60*100) / 100
+		if s.Policy.LatesPerDeduction > 0 {
+			line.LateDeductions = line.LateArrivals / s.Policy.LatesPerDeduction
+		}
+
+		report.Lines = append(report.Lines, line)
+	}
+
+	return report, nil
+}
+
+// WriteCSV writes the report in the configured column layout, with a
+// header row of the column names.
+func (s *PayrollService) WriteCSV(w io.Writer, report *model.PayrollReport) error {
+	columns := make([]string, len(s.Policy.CSVColumns))
+	for i, name := range s.Policy.CSVColumns {
+		columns[i] = strings.TrimSpace(name)
+	}
+
+	out := csv.NewWriter(w)
+	if err := out.Write(columns); err != nil {
+		return err
+	}
+
+	row := make([]string, len(columns))
+	for _, line := range report.Lines {
+		for i, name := range columns {
+			row[i] = payrollColumns[name](line)
+		}
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// isLate compares the check-in with the campus's start of the working day,
+// in the school's time, plus the grace period.
+func (s *PayrollService) isLate(school *model.School, campus *model.Campus, checkIn time.Time) bool {
+	loc := school.Location()
+	local := checkIn.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	workStart, _ := model.WorkDay(school, campus, s.Policy.WorkStart, 0)
+	return local.After(midnight.Add(workStart + s.Policy.LateGrace))
+}
+
+// TrustReport sums the payroll report of the period per school, for
+// trust-level administrators comparing their schools. The attendance rate
+// is the share of the days due so far, leave aside, with a check-in.
+func (s *PayrollService) TrustReport(ctx context.Context, start, end time.Time) (*model.TrustReport, error) {
+	ctx, span := tracing.Start(ctx, "PayrollService.TrustReport")
+	defer span.End()
+
+	payroll, err := s.Report(ctx, start, end, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	schools, err := s.Schools.WithContext(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.TrustReport{
+		From:    payroll.From,
+		To:      payroll.To,
+		Schools: make([]model.SchoolSummary, len(schools)),
+	}
+
+	index := map[uint]int{}
+	for i, school := range schools {
+		index[school.ID] = i
+		report.Schools[i] = model.SchoolSummary{SchoolID: school.ID, Name: school.Name}
+	}
+
+	for _, line := range payroll.Lines {
+		i, ok := index[line.SchoolID]
+		if !ok {
+			continue
+		}
+		summary := &report.Schools[i]
+		summary.Teachers++
+		summary.WorkingDays += line.WorkingDays
+		summary.PresentDays += line.PresentDays
+		summary.LeaveDays += line.LeaveDays
+		summary.AbsentDays += line.AbsentDays
+		summary.LateArrivals += line.LateArrivals
+	}
+
+	for i := range report.Schools {
+		summary := &report.Schools[i]
+		if due := summary.PresentDays + summary.AbsentDays; due > 0 {
+			summary.Attendance = math.Round(float64(summary.PresentDays)/float64(due)*10000) / 100
+		}
+	}
+
+	return report, nil
+}
+
+func formatDecimal(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/requestctx"
+	"school-teacher-management/internal/tracing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrPeriodLocked is returned for any attendance write dated in a closed
+// month. Wrapped errors name the month.
+var ErrPeriodLocked = apperror.Conflict("period_locked", "attendance period is locked")
+
+var ErrPeriodNeverClosed = apperror.NotFound("period_never_closed", "period has never been closed")
+
+type PeriodService struct {
+	Repo       *repository.PeriodRepository
+	Attendance *repository.AttendanceRepository
+	Audit      *AuditService
+}
+
+func NewPeriodService(
+	repo *repository.PeriodRepository,
+	attendance *repository.AttendanceRepository,
+	audit *AuditService,
+) *PeriodService {
+	return &PeriodService{Repo: repo, Attendance: attendance, Audit: audit}
+}
+
+func (s *PeriodService) GetPeriods(ctx context.Context, year int) ([]model.AttendancePeriod, error) {
+	ctx, span := tracing.Start(ctx, "PeriodService.GetPeriods")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).Find(year)
+}
+
+// ClosePeriod locks the month against attendance writes and snapshots its
+// register. The exclusive month lock waits for writes already in flight,
+// so the snapshot is exactly what payroll will see.
+func (s *PeriodService) ClosePeriod(ctx context.Context, year int, month time.Month) (*model.AttendancePeriod, error) {
+	ctx, span := tracing.Start(ctx, "PeriodService.ClosePeriod")
+	defer span.End()
+
+	if time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).After(time.Now()) {
+		return nil, apperror.Rule("period_not_started", "cannot close a month that has not started")
+	}
+
+	schoolID, err := currentSchool(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var period *model.AttendancePeriod
+
+	err = s.Repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := s.Repo.WithTx(tx)
+		if err := repo.LockExclusive(schoolID, year, month); err != nil {
+			return err
+		}
+
+		var err error
+		if period, err = repo.Get(schoolID, year, month); err != nil {
+			return err
+		}
+		if period.Status == model.PeriodLocked {
+			return fmt.Errorf("%w: %s", ErrPeriodLocked, periodName(year, month))
+		}
+
+		before := *period
+		actor := requestctx.Actor(ctx)
+		now := time.Now()
+		period.Status = model.PeriodLocked
+		period.LockedBy = &actor
+		period.LockedAt = &now
+		if err := repo.Save(period); err != nil {
+			return err
+		}
+
+		list, err := s.Attendance.WithTx(tx).FindByMonth(year, month)
+		if err != nil {
+			return err
+		}
+
+		register := make([]model.RegisterEntry, 0, len(list))
+		for i := range list {
+			register = append(register, registerEntry(&list[i]))
+		}
+
+		if err := repo.AddSnapshot(&model.AttendanceSnapshot{
+			PeriodID: period.ID,
+			TakenBy:  actor,
+			Register: register,
+		}); err != nil {
+			return err
+		}
+
+		return s.Audit.Record(ctx, tx, period.SchoolID, model.AuditUpdate, model.AuditEntityPeriod, period.ID, before, period)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return period, nil
+}
+
+// ReopenPeriod allows writes into the month again. Its snapshots are kept so
+// the changes made while it is open can be compared.
+func (s *PeriodService) ReopenPeriod(ctx context.Context, year int, month time.Month) (*model.AttendancePeriod, error) {
+	ctx, span := tracing.Start(ctx, "PeriodService.ReopenPeriod")
+	defer span.End()
+
+	schoolID, err := currentSchool(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var period *model.AttendancePeriod
+
+	err = s.Repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := s.Repo.WithTx(tx)
+		if err := repo.LockExclusive(schoolID, year, month); err != nil {
+			return err
+		}
+
+		var err error
+		if period, err = repo.Get(schoolID, year, month); err != nil {
+			return err
+		}
+		if period.Status != model.PeriodLocked {
+			return apperror.Conflict("period_not_locked", "attendance period "+periodName(year, month)+" is not locked")
+		}
+
+		before := *period
+		actor := requestctx.Actor(ctx)
+		now := time.Now()
+		period.Status = model.PeriodOpen
+		period.ReopenedBy = &actor
+		period.ReopenedAt = &now
+		if err := repo.Save(period); err != nil {
+			return err
+		}
+
+		return s.Audit.Record(ctx, tx, period.SchoolID, model.AuditUpdate, model.AuditEntityPeriod, period.ID, before, period)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return period, nil
+}
+
+func (s *PeriodService) GetSnapshot(ctx context.Context, year int, month time.Month) (*model.AttendanceSnapshot, error) {
+	ctx, span := tracing.Start(ctx, "PeriodService.GetSnapshot")
+	defer span.End()
+
+	schoolID, err := currentSchool(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := s.Repo.WithContext(ctx)
+	period, err := repo.Get(schoolID, year, month)
+	if err != nil {
+		return nil, err
+	}
+	if period.ID == 0 {
+		return nil, ErrPeriodNeverClosed
+	}
+	snapshot, err := repo.LatestSnapshot(period.ID)
+	return snapshot, notFound(err, ErrPeriodNeverClosed)
+}
+
+// ComparePeriod diffs the current register against the latest snapshot.
+func (s *PeriodService) ComparePeriod(ctx context.Context, year int, month time.Month) (*model.PeriodComparison, error) {
+	ctx, span := tracing.Start(ctx, "PeriodService.ComparePeriod")
+	defer span.End()
+
+	snapshot, err := s.GetSnapshot(ctx, year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.Attendance.WithContext(ctx).FindByMonth(year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.PeriodComparison{
+		Year:       year,
+		Month:      int(month),
+		SnapshotID: snapshot.ID,
+		TakenAt:    snapshot.CreatedAt,
+		Added:      []model.RegisterEntry{},
+		Removed:    []model.RegisterEntry{},
+		Changed:    []model.RegisterChange{},
+	}
+
+	locked := map[uint]model.RegisterEntry{}
+	for _, entry := range snapshot.Register {
+		locked[entry.AttendanceID] = entry
+	}
+
+	for i := range list {
+		current := registerEntry(&list[i])
+		before, ok := locked[current.AttendanceID]
+		delete(locked, current.AttendanceID)
+
+		switch {
+		case !ok:
+			result.Added = append(result.Added, current)
+		case !sameEntry(before, current):
+			result.Changed = append(result.Changed, model.RegisterChange{Before: before, After: current})
+		}
+	}
+
+	for _, entry := range snapshot.Register {
+		if _, ok := locked[entry.AttendanceID]; ok {
+			result.Removed = append(result.Removed, entry)
+		}
+	}
+
+	return result, nil
+}
+
+// ensureOpen takes the shared lock of every month of the school touched by
+// an attendance write and fails if any of them is closed. It must run
+// inside the write's transaction so a concurrent close waits for the write
+// to finish.
+func (s *AttendanceService) ensureOpen(tx *gorm.DB, schoolID uint, dates ...time.Time) error {
+	repo := s.Periods.WithTx(tx)
+
+	for _, date := range dates {
+		year, month := date.UTC().Year(), date.UTC().Month()
+		if err := repo.LockShared(schoolID, year, month); err != nil {
+			return err
+		}
+
+		period, err := repo.Get(schoolID, year, month)
+		if err != nil {
+			return err
+		}
+		if period.Status == model.PeriodLocked {
+			return fmt.Errorf("%w: %s", ErrPeriodLocked, periodName(year, month))
+		}
+	}
+	return nil
+}
+
+func periodName(year int, month time.Month) string {
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Format("2006-01")
+}
+
+func registerEntry(att *model.Attendance) model.RegisterEntry {
+	return model.RegisterEntry{
+		AttendanceID: att.ID,
+		TeacherID:    att.TeacherID,
+		TeacherName:  fullName(att.Teacher),
+		Date:         att.Date.Format("2006-01-02"),
+		Status:       att.Status,
+		CheckIn:      att.CheckIn,
+		CheckOut:     att.CheckOut,
+	}
+}
+
+// sameEntry compares a snapshot entry a with the current entry b. Older
+// snapshots hold punch names as the status.
+func sameEntry(a, b model.RegisterEntry) bool {
+	return a.TeacherID == b.TeacherID &&
+		a.Date == b.Date &&
+		model.UpgradeStatus(a.Status) == b.Status &&
+		sameTime(a.CheckIn, b.CheckIn) &&
+		sameTime(a.CheckOut, b.CheckOut)
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"school-teacher-management/internal/config"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var errPunchDuplicate = errors.New("punch already synced")
+
+// offlineChecks are the outcomes of the checks shared by the punches of a
+// batch.
+type offlineChecks struct {
+	campusID   *uint
+	location   *model.PunchLocation
+	offNetwork bool
+	unapproved bool
+}
+
+type PunchSyncService struct {
+	Repo       *repository.PunchRepository
+	Attendance *AttendanceService
+	Policy     config.SyncPolicy
+}
+
+func NewPunchSyncService(
+	repo *repository.PunchRepository,
+	attendance *AttendanceService,
+	policy config.SyncPolicy,
+) *PunchSyncService {
+	return &PunchSyncService{Repo: repo, Attendance: attendance, Policy: policy}
+}
+
+// Sync merges a batch of punches queued on a device while it was offline.
+// Punch times are taken from the device, shifted by its clock skew when
+// that exceeds the tolerance, and applied oldest first. Among offline
+// punches the earliest check-in and the latest check-out of a day win, so
+// the resulting record does not depend on how punches were split across
+// batches or retries; a punch recorded online is never replaced. Nothing
+// shows where or when a queued punch was really made, so the geofence and
+// network policies treat it as off campus: it is rejected or flagged as
+// they say. Each punch gets its own result; one failing does not stop the
+// rest.
+func (s *PunchSyncService) Sync(
+	ctx context.Context,
+	teacherID uint,
+	deviceIdentifier string,
+	input *model.PunchSyncInput,
+) (*model.PunchSyncReport, error) {
+	received := time.Now()
+
+	deviceID, unapproved, err := s.Attendance.Devices.Resolve(ctx, teacherID, deviceIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	checks := offlineChecks{unapproved: unapproved}
+
+	school, err := s.Attendance.Schools.WithContext(ctx).ForTeacher(teacherID)
+	if err != nil {
+		return nil, ErrTeacherNotFound
+	}
+
+	// Queued punches carry no location; they count at the home campus.
+	checks.campusID, err = s.Attendance.punchCampus(ctx, &model.AttendanceRequest{TeacherID: teacherID}, nil, school.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Policy refusals reject every punch of the batch, like a rejected
+	// clock skew; other failures fail the request.
+	var policyErr error
+	checks.location, err = s.Attendance.Geofences.Unverified(ctx, school)
+	if errors.Is(err, ErrOutsideGeofence) {
+		policyErr = errors.New("offline punches cannot show they were made inside a campus geofence")
+	} else if err != nil {
+		return nil, err
+	}
+	checks.offNetwork, err = s.Attendance.Networks.Check(ctx, school, "")
+	if errors.Is(err, ErrOffNetwork) {
+		policyErr = errors.New("offline punches cannot show they were made on the campus network")
+	} else if err != nil {
+		return nil, err
+	}
+
+	skew := received.Sub(input.SentAt)
+	report := &model.PunchSyncReport{ClockSkewSeconds: int64(skew / time.Second)}
+
+	var offset time.Duration
+	var skewErr error
+	if skew > s.Policy.MaxSkew || skew < -s.Policy.MaxSkew {
+		if s.Policy.SkewMode == config.SkewReject {
+			skewErr = fmt.Errorf("device clock is off by %s", skew.Round(time.Second))
+		} else {
+			offset = skew
+			report.Corrected = true
+		}
+	}
+
+	punches := make([]model.OfflinePunch, len(input.Punches))
+	for i, p := range input.Punches {
+		punches[i] = model.OfflinePunch{
+			TeacherID:      teacherID,
+			IdempotencyKey: p.IdempotencyKey,
+			Status:         p.Status,
+			PunchedAt:      p.PunchedAt,
+			RecordedAt:     p.PunchedAt.Add(offset),
+			DeviceID:       deviceID,
+		}
+	}
+
+	sort.SliceStable(punches, func(i, j int) bool {
+		a, b := punches[i], punches[j]
+		if !a.RecordedAt.Equal(b.RecordedAt) {
+			return a.RecordedAt.Before(b.RecordedAt)
+		}
+		if a.Status != b.Status {
+			return a.Status == model.PunchCheckIn
+		}
+		return a.IdempotencyKey < b.IdempotencyKey
+	})
+
+	for i := range punches {
+		punch := &punches[i]
+
+		err := skewErr
+		if err == nil {
+			err = policyErr
+		}
+		if err == nil {
+			err = s.checkTime(punch.RecordedAt, received)
+		}
+		if err == nil {
+			err = s.apply(ctx, school, checks, punch)
+		}
+
+		switch {
+		case errors.Is(err, errPunchDuplicate):
+			stored, findErr := s.Repo.WithContext(ctx).Find(teacherID, punch.IdempotencyKey)
+			if findErr != nil {
+				return nil, findErr
+			}
+			*punch = *stored
+			punch.Message = "already synced as " + stored.Result
+			punch.Result = model.PunchDuplicate
+		case err != nil:
+			punch.Result = model.PunchRejected
+			punch.Message = err.Error()
+		}
+
+		result := model.PunchResult{
+			IdempotencyKey: punch.IdempotencyKey,
+			Status:         punch.Status,
+			Result:         punch.Result,
+			Message:        punch.Message,
+			AttendanceID:   punch.AttendanceID,
+		}
+		if punch.Result != model.PunchRejected {
+			result.RecordedAt = &punch.RecordedAt
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
+
+func (s *PunchSyncService) checkTime(at, received time.Time) error {
+	if at.After(received.Add(s.Policy.MaxSkew)) {
+		return errors.New("punch time is in the future")
+	}
+	if received.Sub(at) > s.Policy.MaxAge {
+		return errors.New("punch is too old to sync")
+	}
+	return nil
+}
+
+// apply merges one punch into its day's record and stores it under its
+// idempotency key in the same transaction, holding the record's row lock.
+// A punch that changes nothing, or would replace one recorded online, is
+// stored as ignored. The day is the school's calendar day of the punch.
+func (s *PunchSyncService) apply(
+	ctx context.Context,
+	school *model.School,
+	checks offlineChecks,
+	punch *model.OfflinePunch,
+) error {
+	if _, err := s.Repo.WithContext(ctx).Find(punch.TeacherID, punch.IdempotencyKey); err == nil {
+		return errPunchDuplicate
+	}
+
+	at := punch.RecordedAt
+	day := school.Today(at)
+
+	var event model.AttendanceEvent
+	err := s.Attendance.lockDay(ctx, punch.TeacherID, day, func(tx *gorm.DB, existing *model.Attendance) error {
+		record := func(tx *gorm.DB) error {
+			stored, err := s.Repo.WithTx(tx).Record(punch)
+			if err != nil {
+				return err
+			}
+			if !stored {
+				return errPunchDuplicate
+			}
+			return nil
+		}
+
+		ignore := func(message string) error {
+			punch.Result = model.PunchIgnored
+			punch.Message = message
+			punch.AttendanceID = &existing.ID
+			return record(tx)
+		}
+
+		punch.Result = model.PunchApplied
+		punch.AttendanceID = nil
+
+		if punch.Status == model.PunchCheckIn {
+			if existing == nil {
+				att := model.Attendance{
+					SchoolID:                school.ID,
+					TeacherID:               punch.TeacherID,
+					Date:                    day,
+					Status:                  model.StatusPresent,
+					CheckIn:                 &at,
+					CheckInLocation:         checks.location,
+					CheckInOffNetwork:       checks.offNetwork,
+					CheckInDeviceID:         punch.DeviceID,
+					CheckInDeviceUnapproved: checks.unapproved,
+					CheckInOffline:          true,
+					CheckInCampusID:         checks.campusID,
+				}
+				var err error
+				event, err = s.Attendance.commitTx(ctx, tx, model.EventCheckIn, nil, &att, func(tx *gorm.DB) error {
+					if err := s.Attendance.Repo.WithTx(tx).CreateDay(&att); err != nil {
+						return err
+					}
+					punch.AttendanceID = &att.ID
+					return record(tx)
+				})
+				return err
+			}
+
+			if existing.CheckIn != nil && !existing.CheckInOffline {
+				return ignore("a check-in is already recorded online")
+			}
+			if existing.CheckIn != nil && !existing.CheckIn.After(at) {
+				return ignore("an earlier check-in is already recorded")
+			}
+			if existing.CheckOut != nil && existing.CheckOut.Before(at) {
+				return errors.New("check-in is after the recorded check-out")
+			}
+
+			before := *existing
+			if err := checkIn(existing); err != nil {
+				return err
+			}
+			existing.CheckIn = &at
+			existing.CheckInLocation = checks.location
+			existing.CheckInOffNetwork = checks.offNetwork
+			existing.CheckInDeviceID = punch.DeviceID
+			existing.CheckInDeviceUnapproved = checks.unapproved
+			existing.CheckInOffline = true
+			existing.CheckInCampusID = checks.campusID
+			var err error
+			event, err = s.update(ctx, tx, model.EventCheckIn, &before, existing, punch, record)
+			return err
+		}
+
+		if existing == nil || existing.CheckIn == nil {
+			return errors.New("check-in required before check-out")
+		}
+		if at.Before(*existing.CheckIn) {
+			return errors.New("check-out is before the recorded check-in")
+		}
+		if existing.CheckOut != nil && !existing.CheckOutOffline {
+			return ignore("a check-out is already recorded online")
+		}
+		if existing.CheckOut != nil && !existing.CheckOut.Before(at) {
+			return ignore("a later check-out is already recorded")
+		}
+
+		before := *existing
+		existing.CheckOut = &at
+		existing.CheckOutLocation = checks.location
+		existing.CheckOutOffNetwork = checks.offNetwork
+		existing.CheckOutDeviceID = punch.DeviceID
+		existing.CheckOutDeviceUnapproved = checks.unapproved
+		existing.CheckOutOffline = true
+		existing.CheckOutCampusID = checks.campusID
+		var err error
+		event, err = s.update(ctx, tx, model.EventCheckOut, &before, existing, punch, record)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Ignored punches change no record and have no event.
+	if event.Type != "" {
+		s.Attendance.publish(ctx, event)
+	}
+	return nil
+}
+
+func (s *PunchSyncService) update(
+	ctx context.Context,
+	tx *gorm.DB,
+	eventType string,
+	before *model.Attendance,
+	att *model.Attendance,
+	punch *model.OfflinePunch,
+	record func(tx *gorm.DB) error,
+) (model.AttendanceEvent, error) {
+	punch.AttendanceID = &att.ID
+	return s.Attendance.commitTx(ctx, tx, eventType, before, att, func(tx *gorm.DB) error {
+		if err := s.Attendance.Repo.WithTx(tx).Update(before, att); err != nil {
+			return err
+		}
+		return record(tx)
+	})
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"net"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/requestctx"
+	"school-teacher-management/internal/tracing"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSchoolNotFound = apperror.NotFound("school_not_found", "school not found")
+	ErrUnknownSchool  = apperror.NotFound("unknown_school", "unknown school")
+	// ErrNoSchool is returned to trust-level administrators for operations
+	// on one school's data; they name the school by its subdomain.
+	ErrNoSchool = apperror.Validation("no_school", "request is not scoped to a school; use the school's subdomain")
+)
+
+type SchoolService struct {
+	Repo       *repository.SchoolRepository
+	BaseDomain string
+	// DefaultID is the school of requests that name none.
+	DefaultID uint
+}
+
+func NewSchoolService(repo *repository.SchoolRepository, baseDomain string, defaultID uint) *SchoolService {
+	return &SchoolService{Repo: repo, BaseDomain: baseDomain, DefaultID: defaultID}
+}
+
+func (s *SchoolService) CreateSchool(ctx context.Context, input *model.SchoolInput) (*model.School, error) {
+	ctx, span := tracing.Start(ctx, "SchoolService.CreateSchool")
+	defer span.End()
+
+	school := &model.School{Active: true}
+	if err := applySchoolInput(school, input); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.WithContext(ctx).Create(school); err != nil {
+		return nil, err
+	}
+	return school, nil
+}
+
+func (s *SchoolService) UpdateSchool(ctx context.Context, id uint, input *model.SchoolInput) (*model.School, error) {
+	ctx, span := tracing.Start(ctx, "SchoolService.UpdateSchool")
+	defer span.End()
+
+	repo := s.Repo.WithContext(ctx)
+	school, err := repo.GetByID(id)
+	if err != nil {
+		return nil, notFound(err, ErrSchoolNotFound)
+	}
+	if err := applySchoolInput(school, input); err != nil {
+		return nil, err
+	}
+	if err := repo.Update(school); err != nil {
+		return nil, err
+	}
+	return school, nil
+}
+
+func applySchoolInput(school *model.School, input *model.SchoolInput) error {
+	slug := strings.ToLower(input.Slug)
+	for _, r := range slug {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return apperror.Field("slug", "slug", "may contain only letters, digits and hyphens")
+		}
+	}
+
+	if input.Timezone != "" {
+		if _, err := time.LoadLocation(input.Timezone); err != nil {
+			return apperror.Field("timezone", "timezone", "is not a known timezone")
+		}
+	}
+
+	for _, clock := range []string{input.WorkStart, input.WorkEnd} {
+		if clock == "" {
+			continue
+		}
+		if _, err := time.Parse("15:04", clock); err != nil {
+			return apperror.Validation("invalid_work_hours", "work_start and work_end must be HH:MM")
+		}
+	}
+
+	school.Slug = slug
+	school.Name = input.Name
+	school.Timezone = input.Timezone
+	school.WorkStart = input.WorkStart
+	school.WorkEnd = input.WorkEnd
+	school.GeofencePolicy = input.GeofencePolicy
+	school.NetworkPolicy = input.NetworkPolicy
+	if input.Active != nil {
+		school.Active = *input.Active
+	}
+	return nil
+}
+
+func (s *SchoolService) GetSchools(ctx context.Context) ([]model.School, error) {
+	ctx, span := tracing.Start(ctx, "SchoolService.GetSchools")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).GetAll()
+}
+
+func (s *SchoolService) GetSchool(ctx context.Context, id uint) (*model.School, error) {
+	ctx, span := tracing.Start(ctx, "SchoolService.GetSchool")
+	defer span.End()
+
+	v, err := s.Repo.WithContext(ctx).GetByID(id)
+	return v, notFound(err, ErrSchoolNotFound)
+}
+
+// ResolveHost returns the school named by the subdomain of host, and
+// whether host named one. Hosts outside the base domain, and the base
+// domain itself, get the default school.
+func (s *SchoolService) ResolveHost(ctx context.Context, host string) (uint, bool, error) {
+	ctx, span := tracing.Start(ctx, "SchoolService.ResolveHost")
+	defer span.End()
+
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+
+	if s.BaseDomain == "" || !strings.HasSuffix(host, "."+s.BaseDomain) {
+		return s.DefaultID, false, nil
+	}
+
+	slug := strings.TrimSuffix(host, "."+s.BaseDomain)
+	school, err := s.Repo.WithContext(ctx).GetBySlug(slug)
+	if err != nil || !school.Active {
+		return 0, false, ErrUnknownSchool
+	}
+	return school.ID, true, nil
+}
+
+func currentSchool(ctx context.Context) (uint, error) {
+	schoolID, ok := requestctx.School(ctx)
+	if !ok {
+		return 0, ErrNoSchool
+	}
+	return schoolID, nil
+}
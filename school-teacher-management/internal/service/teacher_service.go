@@ -1,46 +1,171 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"school-teacher-management/internal/apperror"
 	"school-teacher-management/internal/model"
 	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/tracing"
+
+	"gorm.io/gorm"
 )
 
+var ErrTeacherNotFound = apperror.NotFound("teacher_not_found", "teacher not found")
+
 type TeacherService struct {
-	Repo *repository.TeacherRepository
+	Repo     *repository.TeacherRepository
+	Campuses *repository.CampusRepository
+	Outbox   *repository.OutboxRepository
+	Audit    *AuditService
 }
 
-func NewTeacherService(repo *repository.TeacherRepository) *TeacherService {
-	return &TeacherService{Repo: repo}
+func NewTeacherService(
+	repo *repository.TeacherRepository,
+	campuses *repository.CampusRepository,
+	outbox *repository.OutboxRepository,
+	audit *AuditService,
+) *TeacherService {
+	return &TeacherService{Repo: repo, Campuses: campuses, Outbox: outbox, Audit: audit}
 }
 
-func (s *TeacherService) CreateTeacher(teacher *model.Teacher) error {
-	return s.Repo.Create(teacher)
+// CreateTeacher adds the teacher to the school the request is scoped to.
+func (s *TeacherService) CreateTeacher(ctx context.Context, teacher *model.Teacher) error {
+	ctx, span := tracing.Start(ctx, "TeacherService.CreateTeacher")
+	defer span.End()
+
+	schoolID, err := currentSchool(ctx)
+	if err != nil {
+		return err
+	}
+	teacher.SchoolID = schoolID
+
+	if err := checkCampus(s.Campuses.WithContext(ctx), teacher.HomeCampusID, schoolID); err != nil {
+		return err
+	}
+
+	return s.Repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.Repo.WithTx(tx).Create(teacher); err != nil {
+			return err
+		}
+		if err := s.Audit.Record(ctx, tx, teacher.SchoolID, model.AuditCreate, model.AuditEntityTeacher, teacher.ID, nil, teacher); err != nil {
+			return err
+		}
+		return s.Outbox.WithTx(tx).Add(teacher.SchoolID, model.EventTeacherCreated, teacher)
+	})
 }
 
-func (s *TeacherService) UpdateTeacher(teacher *model.Teacher) error {
-	return s.Repo.Update(teacher)
+// UpdateTeacher writes the fields of teacher that changed. A non-zero
+// version must match the stored one.
+func (s *TeacherService) UpdateTeacher(ctx context.Context, teacher *model.Teacher, version uint) error {
+	ctx, span := tracing.Start(ctx, "TeacherService.UpdateTeacher")
+	defer span.End()
+
+	err := s.Repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := s.Repo.WithTx(tx)
+
+		// A missing row is still saved, as before; it is audited as a create.
+		// Teachers do not move between schools.
+		existing, err := repo.GetByID(teacher.ID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			existing = nil
+		case err != nil:
+			return err
+		}
+		if version != 0 && (existing == nil || existing.Version != version) {
+			return ErrVersionMismatch
+		}
+
+		var before interface{}
+		if existing != nil {
+			before = existing
+			teacher.SchoolID = existing.SchoolID
+		}
+
+		if err := checkCampus(s.Campuses.WithTx(tx), teacher.HomeCampusID, teacher.SchoolID); err != nil {
+			return err
+		}
+
+		action := model.AuditUpdate
+		if existing == nil {
+			action = model.AuditCreate
+			err = repo.Create(teacher)
+		} else {
+			err = repo.Update(existing, teacher)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.Audit.Record(ctx, tx, teacher.SchoolID, action, model.AuditEntityTeacher, teacher.ID, before, teacher); err != nil {
+			return err
+		}
+		return s.Outbox.WithTx(tx).Add(teacher.SchoolID, model.EventTeacherUpdated, teacher)
+	})
+	return stale(err)
 }
 
-func (s *TeacherService) GetTeacher(id uint) (*model.Teacher, error) {
-	return s.Repo.GetByID(id)
+func (s *TeacherService) GetTeacher(ctx context.Context, id uint) (*model.Teacher, error) {
+	ctx, span := tracing.Start(ctx, "TeacherService.GetTeacher")
+	defer span.End()
+
+	teacher, err := s.Repo.WithContext(ctx).GetByID(id)
+	return teacher, notFound(err, ErrTeacherNotFound)
 }
 
-func (s *TeacherService) SearchTeachers(q string, subject string) ([]model.Teacher, error) {
-	return s.Repo.SearchAllFields(q, subject)
+func (s *TeacherService) SearchTeachers(ctx context.Context, q string, subject string) ([]model.Teacher, error) {
+	ctx, span := tracing.Start(ctx, "TeacherService.SearchTeachers")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).SearchAllFields(q, subject)
 }
 
-func (s *TeacherService) CreateTeachers(req []model.TeacherRequest) error {
+func (s *TeacherService) CreateTeachers(ctx context.Context, req []model.TeacherRequest) error {
+	ctx, span := tracing.Start(ctx, "TeacherService.CreateTeachers")
+	defer span.End()
+
+	schoolID, err := currentSchool(ctx)
+	if err != nil {
+		return err
+	}
+
 	var teachers []model.Teacher
 
+	campuses := s.Campuses.WithContext(ctx)
 	for _, t := range req {
+		if err := checkCampus(campuses, t.HomeCampusID, schoolID); err != nil {
+			return err
+		}
 		teachers = append(teachers, model.Teacher{
-			FirstName: t.FirstName,
-			LastName:  t.LastName,
-			Email:     t.Email,
-			Subject:   t.Subject,
-			Phone:     t.Phone,
+			SchoolID:         schoolID,
+			FirstName:        t.FirstName,
+			LastName:         t.LastName,
+			Email:            t.Email,
+			EmployeeCode:     t.EmployeeCode,
+			Subject:          t.Subject,
+			Department:       t.Department,
+			HeadOfDepartment: t.HeadOfDepartment,
+			HomeCampusID:     t.HomeCampusID,
+			Phone:            t.Phone,
 		})
 	}
 
-	return s.Repo.BulkCreate(teachers)
+	return s.Repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.Repo.WithTx(tx).BulkCreate(teachers); err != nil {
+			return err
+		}
+
+		outbox := s.Outbox.WithTx(tx)
+		for i := range teachers {
+			err := s.Audit.Record(ctx, tx, teachers[i].SchoolID, model.AuditCreate, model.AuditEntityTeacher, teachers[i].ID, nil, teachers[i])
+			if err != nil {
+				return err
+			}
+			if err := outbox.Add(teachers[i].SchoolID, model.EventTeacherCreated, teachers[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
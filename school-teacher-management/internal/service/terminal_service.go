@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/config"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/tracing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTerminalNotFound = apperror.NotFound("terminal_not_found", "terminal not found")
+	ErrInvalidPIN       = apperror.Unauthorized("invalid_pin", "invalid employee code or PIN")
+	ErrUnknownTerminal  = apperror.Unauthorized("unknown_terminal", "unknown or inactive terminal")
+	ErrPINLocked        = apperror.Locked("pin_locked", "PIN locked after repeated failures; try again later or ask an administrator to reset it")
+)
+
+// unknownPINHash is compared against when the employee code or PIN does
+// not exist, so those cases take as long as a wrong PIN.
+var unknownPINHash, _ = bcrypt.GenerateFromPassword([]byte("unknown"), bcrypt.DefaultCost)
+
+type TerminalService struct {
+	Repo     *repository.TerminalRepository
+	Teachers *repository.TeacherRepository
+	Campuses *repository.CampusRepository
+	Policy   config.PINPolicy
+}
+
+func NewTerminalService(
+	repo *repository.TerminalRepository,
+	teachers *repository.TeacherRepository,
+	campuses *repository.CampusRepository,
+	policy config.PINPolicy,
+) *TerminalService {
+	return &TerminalService{Repo: repo, Teachers: teachers, Campuses: campuses, Policy: policy}
+}
+
+// RegisterTerminal creates a terminal and its key. Only the key's hash is
+// kept. Punches made at the terminal are recorded at its campus.
+func (s *TerminalService) RegisterTerminal(ctx context.Context, input *model.TerminalInput) (*model.TerminalRegistration, error) {
+	ctx, span := tracing.Start(ctx, "TerminalService.RegisterTerminal")
+	defer span.End()
+
+	if err := checkOwnCampus(ctx, s.Campuses, input.CampusID); err != nil {
+		return nil, err
+	}
+
+	key, err := newDeviceKey()
+	if err != nil {
+		return nil, err
+	}
+
+	terminal := model.Terminal{
+		Name:     input.Name,
+		Location: input.Location,
+		CampusID: input.CampusID,
+		KeyHash:  hashDeviceKey(key),
+		Active:   true,
+	}
+	if err := s.Repo.WithContext(ctx).Create(&terminal); err != nil {
+		return nil, err
+	}
+
+	return &model.TerminalRegistration{Terminal: terminal, Key: key}, nil
+}
+
+func (s *TerminalService) GetTerminals(ctx context.Context) ([]model.Terminal, error) {
+	ctx, span := tracing.Start(ctx, "TerminalService.GetTerminals")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).GetAll()
+}
+
+func (s *TerminalService) DeactivateTerminal(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "TerminalService.DeactivateTerminal")
+	defer span.End()
+
+	repo := s.Repo.WithContext(ctx)
+	terminal, err := repo.GetByID(id)
+	if err != nil {
+		return notFound(err, ErrTerminalNotFound)
+	}
+
+	terminal.Active = false
+	return repo.Update(terminal)
+}
+
+// Authenticate returns the active terminal holding key. The terminal is
+// looked up in the school of the request's subdomain.
+func (s *TerminalService) Authenticate(ctx context.Context, key string) (*model.Terminal, error) {
+	ctx, span := tracing.Start(ctx, "TerminalService.Authenticate")
+	defer span.End()
+
+	terminal, err := s.Repo.WithContext(ctx).GetByKeyHash(hashDeviceKey(key))
+	if err != nil || !terminal.Active {
+		return nil, ErrUnknownTerminal
+	}
+	return terminal, nil
+}
+
+// SetPIN sets or resets a teacher's PIN and lifts any lockout.
+func (s *TerminalService) SetPIN(ctx context.Context, teacherID uint, pin string) error {
+	ctx, span := tracing.Start(ctx, "TerminalService.SetPIN")
+	defer span.End()
+
+	if _, err := s.Teachers.WithContext(ctx).GetByID(teacherID); err != nil {
+		return notFound(err, ErrTeacherNotFound)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return s.Repo.WithContext(ctx).SavePIN(&model.TeacherPIN{
+		TeacherID: teacherID,
+		PINHash:   string(hash),
+	})
+}
+
+// VerifyPIN returns the teacher with the employee code if the PIN matches.
+// Each wrong PIN counts towards the lockout; a correct one resets the
+// count. The PIN row stays locked while it is checked so concurrent
+// guesses are counted one by one. Only teachers of the terminal's school
+// can punch at it.
+func (s *TerminalService) VerifyPIN(ctx context.Context, terminal *model.Terminal, employeeCode, pin string) (*model.Teacher, error) {
+	ctx, span := tracing.Start(ctx, "TerminalService.VerifyPIN")
+	defer span.End()
+
+	teacher, err := s.Teachers.WithContext(ctx).GetByEmployeeCode(employeeCode)
+	if err != nil || teacher.SchoolID != terminal.SchoolID {
+		bcrypt.CompareHashAndPassword(unknownPINHash, []byte(pin))
+		return nil, ErrInvalidPIN
+	}
+
+	var result error
+
+	err = s.Repo.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := s.Repo.WithTx(tx)
+
+		stored, err := repo.LockPIN(teacher.ID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			bcrypt.CompareHashAndPassword(unknownPINHash, []byte(pin))
+			result = ErrInvalidPIN
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if stored.LockedUntil != nil && stored.LockedUntil.After(now) {
+			result = ErrPINLocked
+			return nil
+		}
+
+		if bcrypt.CompareHashAndPassword([]byte(stored.PINHash), []byte(pin)) == nil {
+			return repo.RecordPINResult(teacher.ID, 0, nil)
+		}
+
+		result = ErrInvalidPIN
+		attempts := stored.FailedAttempts + 1
+		if attempts < s.Policy.MaxAttempts {
+			return repo.RecordPINResult(teacher.ID, attempts, nil)
+		}
+
+		until := now.Add(s.Policy.LockoutPeriod)
+		result = ErrPINLocked
+		return repo.RecordPINResult(teacher.ID, 0, &until)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result != nil {
+		return nil, result
+	}
+
+	return teacher, nil
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"school-teacher-management/internal/metrics"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	webhookBatchSize   = 50
+	webhookMaxAttempts = 8
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+	webhookLease       = 2 * time.Minute
+)
+
+// WebhookDispatcher moves outbox events into per-subscription deliveries and
+// sends the deliveries that are due. Several instances may run it at once.
+type WebhookDispatcher struct {
+	Webhooks *repository.WebhookRepository
+	Outbox   *repository.OutboxRepository
+	Client   *http.Client
+	Interval time.Duration
+}
+
+func NewWebhookDispatcher(webhooks *repository.WebhookRepository, outbox *repository.OutboxRepository) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		Webhooks: webhooks,
+		Outbox:   outbox,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+		Interval: 5 * time.Second,
+	}
+}
+
+// Run polls until ctx is cancelled.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.fanOut(); err != nil {
+			slog.ErrorContext(ctx, "webhook fan-out", "error", err)
+		}
+		if err := d.deliverDue(ctx); err != nil {
+			slog.ErrorContext(ctx, "webhook delivery", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// fanOut creates one delivery per matching active subscription of the
+// event's school for each unprocessed outbox event.
+func (d *WebhookDispatcher) fanOut() error {
+	return d.Outbox.DB.Transaction(func(tx *gorm.DB) error {
+		outbox := d.Outbox.WithTx(tx)
+
+		pending, err := outbox.LockUnprocessed(webhookBatchSize)
+		if err != nil || len(pending) == 0 {
+			return err
+		}
+
+		webhooks := d.Webhooks.WithTx(tx)
+		subs, err := webhooks.GetActive()
+		if err != nil {
+			return err
+		}
+
+		var deliveries []model.WebhookDelivery
+		ids := make([]uint, 0, len(pending))
+		for _, event := range pending {
+			ids = append(ids, event.ID)
+			for _, sub := range subs {
+				if sub.SchoolID != event.SchoolID || !sub.Subscribes(event.EventType) {
+					continue
+				}
+				deliveries = append(deliveries, model.WebhookDelivery{
+					SchoolID:       sub.SchoolID,
+					SubscriptionID: sub.ID,
+					OutboxEventID:  event.ID,
+					EventType:      event.EventType,
+					Status:         model.DeliveryPending,
+					NextAttemptAt:  time.Now(),
+				})
+			}
+		}
+
+		if err := webhooks.CreateDeliveries(deliveries); err != nil {
+			return err
+		}
+		return outbox.MarkProcessed(ids)
+	})
+}
+
+func (d *WebhookDispatcher) deliverDue(ctx context.Context) error {
+	due, err := d.Webhooks.LeaseDueDeliveries(webhookBatchSize, webhookLease)
+	if err != nil {
+		return err
+	}
+
+	for i := range due {
+		if ctx.Err() != nil {
+			return nil
+		}
+		d.attempt(ctx, &due[i])
+	}
+	return nil
+}
+
+func (d *WebhookDispatcher) attempt(ctx context.Context, delivery *model.WebhookDelivery) {
+	delivery.Attempts++
+
+	statusCode, err := d.send(ctx, delivery)
+	delivery.LastStatusCode = statusCode
+
+	if err == nil {
+		now := time.Now()
+		delivery.Status = model.DeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	} else {
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= webhookMaxAttempts {
+			delivery.Status = model.DeliveryDead
+		} else {
+			delivery.NextAttemptAt = time.Now().Add(webhookBackoff(delivery.Attempts))
+		}
+	}
+
+	metrics.WebhookDeliveriesTotal.WithLabelValues(delivery.EventType, deliveryOutcome(delivery, err)).Inc()
+
+	if err := d.Webhooks.UpdateDelivery(delivery); err != nil {
+		slog.ErrorContext(ctx, "webhook delivery: saving attempt", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, delivery *model.WebhookDelivery) (int, error) {
+	sub, err := d.Webhooks.GetByID(delivery.SubscriptionID)
+	if err != nil {
+		return 0, fmt.Errorf("subscription: %w", err)
+	}
+
+	event, err := d.Outbox.GetByID(delivery.OutboxEventID)
+	if err != nil {
+		return 0, fmt.Errorf("outbox event: %w", err)
+	}
+
+	body, err := json.Marshal(model.WebhookPayload{
+		ID:         event.ID,
+		Type:       event.EventType,
+		OccurredAt: event.CreatedAt,
+		Data:       json.RawMessage(event.Payload),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", event.EventType)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-Webhook-Signature", "t="+timestamp+",v1="+SignWebhook(sub.Secret, timestamp, body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of "<timestamp>.<body>". Receivers
+// recompute it with the shared secret and should reject stale timestamps.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff << (attempts - 1)
+	if backoff <= 0 || backoff > webhookMaxBackoff {
+		return webhookMaxBackoff
+	}
+	return backoff
+}
+
+func deliveryOutcome(delivery *model.WebhookDelivery, err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case delivery.Status == model.DeliveryDead:
+		return "dead"
+	default:
+		return "retry"
+	}
+}
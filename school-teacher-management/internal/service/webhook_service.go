@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/model"
+	"school-teacher-management/internal/repository"
+	"school-teacher-management/internal/tracing"
+	"time"
+)
+
+var (
+	ErrWebhookNotFound  = apperror.NotFound("webhook_not_found", "webhook subscription not found")
+	ErrDeliveryNotFound = apperror.NotFound("delivery_not_found", "webhook delivery not found")
+)
+
+type WebhookService struct {
+	Repo *repository.WebhookRepository
+}
+
+func NewWebhookService(repo *repository.WebhookRepository) *WebhookService {
+	return &WebhookService{Repo: repo}
+}
+
+// CreateSubscription subscribes to the events of the school the request is
+// scoped to.
+func (s *WebhookService) CreateSubscription(ctx context.Context, req *model.WebhookRequest) (*model.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.CreateSubscription")
+	defer span.End()
+
+	schoolID, err := currentSchool(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &model.WebhookSubscription{
+		SchoolID:   schoolID,
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     req.Secret,
+		Active:     req.Active == nil || *req.Active,
+	}
+
+	if err := s.Repo.WithContext(ctx).Create(sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *WebhookService) UpdateSubscription(ctx context.Context, id uint, req *model.WebhookRequest) (*model.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.UpdateSubscription")
+	defer span.End()
+
+	sub, err := s.Repo.WithContext(ctx).GetByID(id)
+	if err != nil {
+		return nil, notFound(err, ErrWebhookNotFound)
+	}
+
+	sub.URL = req.URL
+	sub.EventTypes = req.EventTypes
+	sub.Secret = req.Secret
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+
+	if err := s.Repo.WithContext(ctx).Update(sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *WebhookService) GetSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetSubscriptions")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).GetAll()
+}
+
+func (s *WebhookService) GetSubscription(ctx context.Context, id uint) (*model.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetSubscription")
+	defer span.End()
+
+	v, err := s.Repo.WithContext(ctx).GetByID(id)
+	return v, notFound(err, ErrWebhookNotFound)
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeleteSubscription")
+	defer span.End()
+
+	return notFound(s.Repo.WithContext(ctx).Delete(id), ErrWebhookNotFound)
+}
+
+func (s *WebhookService) GetDeliveries(ctx context.Context, subscriptionID uint, status string) ([]model.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetDeliveries")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).FindDeliveries(subscriptionID, status)
+}
+
+func (s *WebhookService) GetDeadLetters(ctx context.Context) ([]model.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetDeadLetters")
+	defer span.End()
+
+	return s.Repo.WithContext(ctx).FindDeliveries(0, model.DeliveryDead)
+}
+
+// RetryDelivery puts a dead-lettered delivery back in the queue with a
+// fresh set of attempts.
+func (s *WebhookService) RetryDelivery(ctx context.Context, id uint) (*model.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.RetryDelivery")
+	defer span.End()
+
+	delivery, err := s.Repo.WithContext(ctx).GetDelivery(id)
+	if err != nil {
+		return nil, notFound(err, ErrDeliveryNotFound)
+	}
+
+	if delivery.Status != model.DeliveryDead {
+		return nil, apperror.Conflict("delivery_not_dead", "only dead-lettered deliveries can be retried")
+	}
+
+	delivery.Status = model.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+
+	if err := s.Repo.WithContext(ctx).UpdateDelivery(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
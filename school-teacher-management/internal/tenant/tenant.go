@@ -0,0 +1,175 @@
+// Package tenant confines database access to the school a request is
+// scoped to. It is a GORM plugin, so every repository query is covered
+// without the repositories having to filter by school themselves.
+package tenant
+
+import (
+	"reflect"
+
+	"school-teacher-management/internal/apperror"
+	"school-teacher-management/internal/requestctx"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrOtherSchool is returned when a write names a teacher or school outside
+// the one the request is scoped to.
+var ErrOtherSchool = apperror.Forbidden("other_school", "record belongs to another school")
+
+// Plugin scopes statements whose context carries a school (see
+// requestctx.WithSchool):
+//
+//   - tables with a school_id column are filtered by it, and new rows get
+//     the school, or the school of their teacher;
+//   - other tables with a teacher_id column are filtered to the school's
+//     teachers, and new rows must name one of them.
+//
+// Statements without a school, from background jobs and trust-level
+// administrators, see every school. Rows they create without a teacher are
+// placed in DefaultSchoolID.
+type Plugin struct {
+	DefaultSchoolID uint
+}
+
+func (p *Plugin) Name() string {
+	return "tenant"
+}
+
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Query().Before("gorm:query").Register("tenant:query", scope); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("tenant:row", scope); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:update", scopeUpdate); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("tenant:delete", scope); err != nil {
+		return err
+	}
+	return cb.Create().Before("gorm:create").Register("tenant:create", p.assign)
+}
+
+func scope(db *gorm.DB) {
+	schoolID, ok := requestctx.School(db.Statement.Context)
+	if !ok || db.Statement.Schema == nil {
+		return
+	}
+
+	if _, ok := db.Statement.Schema.FieldsByDBName["school_id"]; ok {
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "school_id"}, Value: schoolID},
+		}})
+		return
+	}
+
+	if _, ok := db.Statement.Schema.FieldsByDBName["teacher_id"]; ok {
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Expr{
+				SQL:  "? IN (SELECT id FROM teachers WHERE school_id = ?)",
+				Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: "teacher_id"}, schoolID},
+			},
+		}})
+	}
+}
+
+// scopeUpdate also keeps scoped updates from moving a row to another
+// school.
+func scopeUpdate(db *gorm.DB) {
+	scope(db)
+
+	if _, ok := requestctx.School(db.Statement.Context); ok && db.Statement.Schema != nil {
+		if _, ok := db.Statement.Schema.FieldsByDBName["school_id"]; ok {
+			db.Statement.Omit("school_id")
+		}
+	}
+}
+
+func (p *Plugin) assign(db *gorm.DB) {
+	if db.Statement.Schema == nil {
+		return
+	}
+
+	schoolField := db.Statement.Schema.FieldsByDBName["school_id"]
+	teacherField := db.Statement.Schema.FieldsByDBName["teacher_id"]
+	if schoolField == nil && teacherField == nil {
+		return
+	}
+
+	// Upserts must not take over a conflicting row of another school.
+	if schoolID, ok := requestctx.School(db.Statement.Context); ok && schoolField != nil {
+		if c, ok := db.Statement.Clauses["ON CONFLICT"]; ok {
+			if onConflict, ok := c.Expression.(clause.OnConflict); ok && !onConflict.DoNothing {
+				onConflict.Where.Exprs = append(onConflict.Where.Exprs, clause.Eq{
+					Column: clause.Column{Table: db.Statement.Table, Name: "school_id"},
+					Value:  schoolID,
+				})
+				c.Expression = onConflict
+				db.Statement.Clauses["ON CONFLICT"] = c
+			}
+		}
+	}
+
+	rv := db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := p.assignRow(db, reflect.Indirect(rv.Index(i)), schoolField, teacherField); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+	case reflect.Struct:
+		if err := p.assignRow(db, rv, schoolField, teacherField); err != nil {
+			db.AddError(err)
+		}
+	}
+}
+
+func (p *Plugin) assignRow(db *gorm.DB, row reflect.Value, schoolField, teacherField *schema.Field) error {
+	ctx := db.Statement.Context
+	schoolID, scoped := requestctx.School(ctx)
+
+	target := schoolID
+	if !scoped {
+		target = p.DefaultSchoolID
+	}
+
+	if teacherField != nil {
+		teacherID, zero := teacherField.ValueOf(ctx, row)
+		if !zero {
+			var teacherSchool uint
+			err := db.Session(&gorm.Session{NewDB: true}).
+				Table("teachers").
+				Select("school_id").
+				Where("id = ?", teacherID).
+				Scan(&teacherSchool).Error
+			if err != nil {
+				return err
+			}
+			if scoped && teacherSchool != schoolID {
+				return ErrOtherSchool
+			}
+			if teacherSchool != 0 {
+				target = teacherSchool
+			}
+		}
+	}
+
+	if schoolField == nil {
+		return nil
+	}
+
+	current, zero := schoolField.ValueOf(ctx, row)
+	if zero {
+		return schoolField.Set(ctx, row, target)
+	}
+	if scoped && current != schoolID {
+		return ErrOtherSchool
+	}
+	return nil
+}